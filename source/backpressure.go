@@ -0,0 +1,231 @@
+/**
+* @file backpressure.go
+* @brief Bounded event queues and the strategies applied when one is full.
+* @author Anadian
+* @copyright 	Copyright 2019 Canosw
+	Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+software and associated documentation files (the "Software"), to deal in the Software 
+without restriction, including without limitation the rights to use, copy, modify, 
+merge, publish, distribute, sublicense, and/or sell copies of the Software, and to 
+permit persons to whom the Software is furnished to do so, subject to the following 
+conditions:
+	The above copyright notice and this permission notice shall be included in all copies 
+or substantial portions of the Software.
+	THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A 
+PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT 
+HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF 
+CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE 
+OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+package event_dispatcher;
+
+//# Dependencies
+import(
+	//## Internal
+	//## Standard
+	"context"
+	//## External
+	error_report "github.com/Anadian/error_report/source"
+);
+
+//# Constants
+const(
+	//## Exported Constants
+	//### Errors
+	ERROR_CODE_QUEUE_FULL int64 = 28;
+	ERROR_CODE_INVALID_OVERFLOW_STRATEGY int64 = 29;
+	//### Overflow Strategies
+	OVERFLOW_STRATEGY_BLOCK uint8 = 1 //Wait for room until the publisher's context is done, then fail with ERROR_CODE_QUEUE_FULL.
+	OVERFLOW_STRATEGY_DROP_NEWEST uint8 = 2 //Discard the event being published.
+	OVERFLOW_STRATEGY_DROP_OLDEST uint8 = 3 //Discard the event at the front of the queue to make room; in QUEUE_MODE_PRIORITY, the event which would be dispatched last.
+	OVERFLOW_STRATEGY_ERROR uint8 = 4 //Fail immediately with ERROR_CODE_QUEUE_FULL.
+	//## Private Constants
+);
+
+//# Types
+//## Structs
+//### Methods
+/**
+* @fn SetQueueCapacity
+* @brief Bounds the number of queued events and sets what happens to events published while the queue is full.
+* @struct event_dispatcher *EventDispatcher_struct
+* @param capacity uint [in] The maximum number of queued events; 0 means unbounded.
+* @param overflow_strategy uint8 [in] One of the `OVERFLOW_STRATEGY_*` constants.
+* @return ( return_report error_report.ErrorReport_struct ) 
+* @retval 0 Success
+* @retval 1 Not Supported
+* @retval >1 Error
+*/
+
+// SetQueueCapacity bounds the number of queued events and sets what happens to events published while the queue is full. Events already queued beyond a lowered capacity are kept. Not supported by queue backends.
+func (event_dispatcher *EventDispatcher_struct) SetQueueCapacity( capacity uint, overflow_strategy uint8 ) ( return_report error_report.ErrorReport_struct ){
+	//Variables
+	//Parametres
+	if( event_dispatcher.queue_backend != nil ){
+		return error_report.New( 1, map[string]interface{}{ "message": "Queue backends can't bound the queue." }, nil );
+	}
+	//Function
+	if( overflow_strategy >= OVERFLOW_STRATEGY_BLOCK && overflow_strategy <= OVERFLOW_STRATEGY_ERROR ){
+		event_dispatcher.mutex.Lock();
+		event_dispatcher.queue_capacity = capacity;
+		event_dispatcher.overflow_strategy = overflow_strategy;
+		///Wake any blocked publishers so they re-check against the new capacity.
+		event_dispatcher.signalSpace_Unsafe();
+		event_dispatcher.mutex.Unlock();
+		return_report = error_report.New( 0, map[string]interface{}{ "queue_capacity": capacity, "overflow_strategy": overflow_strategy }, nil );
+	} else{
+		return_report = error_report.New( ERROR_CODE_INVALID_OVERFLOW_STRATEGY, map[string]interface{}{ "message": "Invalid overflow strategy.", "overflow_strategy": overflow_strategy }, nil );
+	}
+	//Return
+	return return_report;
+}
+
+/**
+* @fn GetDroppedEventCounts
+* @brief Returns, in the "dropped_event_counts" datum, how many events each overflow strategy has discarded.
+* @struct event_dispatcher *EventDispatcher_struct
+* @return ( return_report error_report.ErrorReport_struct ) 
+* @retval 0 Success
+* @retval 1 Not Supported
+* @retval >1 Error
+*/
+
+// GetDroppedEventCounts returns, in the "dropped_event_counts" datum, how many events each overflow strategy has discarded, keyed by `OVERFLOW_STRATEGY_*`.
+func (event_dispatcher *EventDispatcher_struct) GetDroppedEventCounts() ( return_report error_report.ErrorReport_struct ){
+	//Variables
+	var dropped_event_counts map[uint8]uint64 = map[uint8]uint64{};
+	//Parametres
+	//Function
+	event_dispatcher.mutex.Lock();
+	for overflow_strategy, count := range event_dispatcher.dropped_event_counts {
+		dropped_event_counts[overflow_strategy] = count;
+	}
+	event_dispatcher.mutex.Unlock();
+	return_report = error_report.New( 0, map[string]interface{}{ "dropped_event_counts": dropped_event_counts }, nil );
+	//Return
+	return return_report;
+}
+
+/**
+* @fn reserveSpace_Unsafe
+* @brief Makes sure there's room for one more event, applying the overflow strategy when the queue is full; the caller must hold `mutex`, which may be released while blocking.
+* @struct event_dispatcher *EventDispatcher_struct
+* @param ctx context.Context [in] Bounds how long `OVERFLOW_STRATEGY_BLOCK` waits.
+* @return ( insert bool, return_report error_report.ErrorReport_struct ) `insert` is true if the event should be added.
+* @retval 0 Success; the "dropped" datum is true if the new event was discarded, and the "drop_oldest" datum is true if `dropOldest_Unsafe` must make room once the new event is logged.
+* @retval 1 Not Supported
+* @retval >1 Error
+*/
+
+// reserveSpace_Unsafe makes sure there's room for one more event, applying the overflow strategy when the queue is full; the caller must hold `mutex`, which may be released while blocking. With `OVERFLOW_STRATEGY_DROP_OLDEST` nothing is dropped yet: the caller calls `dropOldest_Unsafe` once the new event is logged, so a failed write doesn't lose both events.
+func (event_dispatcher *EventDispatcher_struct) reserveSpace_Unsafe( ctx context.Context ) ( insert bool, return_report error_report.ErrorReport_struct ){
+	//Variables
+	var space_channel chan struct{};
+	var waiting bool = true;
+	//Parametres
+	if( ctx == nil ){
+		ctx = context.Background();
+	}
+	//Function
+	for waiting == true {
+		waiting = false;
+		if( event_dispatcher.queue_capacity == 0 || uint(event_dispatcher.getEventsQueue_Unsafe().Length()) < event_dispatcher.queue_capacity ){
+			insert = true;
+			return_report = error_report.New( 0, map[string]interface{}{ "dropped": false, "drop_oldest": false }, nil );
+		} else{
+			switch( event_dispatcher.overflow_strategy ){
+				case OVERFLOW_STRATEGY_BLOCK:
+					if( event_dispatcher.space_channel == nil ){
+						event_dispatcher.space_channel = make(chan struct{});
+					}
+					space_channel = event_dispatcher.space_channel;
+					event_dispatcher.mutex.Unlock();
+					select{
+						case <-space_channel:
+							waiting = true;
+						case <-ctx.Done():
+					}
+					event_dispatcher.mutex.Lock();
+					if( waiting == false ){
+						event_dispatcher.countDroppedEvent_Unsafe( OVERFLOW_STRATEGY_BLOCK );
+						return_report = error_report.New( ERROR_CODE_QUEUE_FULL, map[string]interface{}{ "message": "The event queue stayed full until the context was done.", "context_error": ctx.Err(), "queue_capacity": event_dispatcher.queue_capacity }, nil );
+					}
+				case OVERFLOW_STRATEGY_DROP_NEWEST:
+					event_dispatcher.countDroppedEvent_Unsafe( OVERFLOW_STRATEGY_DROP_NEWEST );
+					return_report = error_report.New( 0, map[string]interface{}{ "dropped": true, "drop_oldest": false }, nil );
+				case OVERFLOW_STRATEGY_DROP_OLDEST:
+					insert = true;
+					return_report = error_report.New( 0, map[string]interface{}{ "dropped": false, "drop_oldest": true }, nil );
+				default:
+					event_dispatcher.countDroppedEvent_Unsafe( OVERFLOW_STRATEGY_ERROR );
+					return_report = error_report.New( ERROR_CODE_QUEUE_FULL, map[string]interface{}{ "message": "The event queue is full.", "queue_capacity": event_dispatcher.queue_capacity }, nil );
+			}
+		}
+	}
+	//Return
+	return insert, return_report;
+}
+
+/**
+* @fn dropOldest_Unsafe
+* @brief Discards and acknowledges the event at the front of the queue, or, in `QUEUE_MODE_PRIORITY`, the event which would be dispatched last; the caller must hold `mutex`.
+* @struct event_dispatcher *EventDispatcher_struct
+*/
+
+// dropOldest_Unsafe discards and acknowledges the event at the front of the queue, or, in `QUEUE_MODE_PRIORITY`, the event which would be dispatched last, for `OVERFLOW_STRATEGY_DROP_OLDEST`; the caller must hold `mutex`.
+func (event_dispatcher *EventDispatcher_struct) dropOldest_Unsafe(){
+	//Variables
+	//Parametres
+	//Function
+	if( event_dispatcher.queue_mode == QUEUE_MODE_PRIORITY ){
+		event_dispatcher.acknowledgeEvent( event_dispatcher.events_queue.PopBack() );
+	} else{
+		event_dispatcher.acknowledgeEvent( event_dispatcher.events_queue.PopFront() );
+	}
+	event_dispatcher.countDroppedEvent_Unsafe( OVERFLOW_STRATEGY_DROP_OLDEST );
+	//Return
+}
+
+/**
+* @fn countDroppedEvent_Unsafe
+* @brief Increments the dropped-event counter for the given overflow strategy; the caller must hold `mutex`.
+* @struct event_dispatcher *EventDispatcher_struct
+* @param overflow_strategy uint8 [in] The strategy which dropped the event.
+*/
+
+// countDroppedEvent_Unsafe increments the dropped-event counter for the given overflow strategy; the caller must hold `mutex`.
+func (event_dispatcher *EventDispatcher_struct) countDroppedEvent_Unsafe( overflow_strategy uint8 ){
+	//Variables
+	//Parametres
+	//Function
+	if( event_dispatcher.dropped_event_counts == nil ){
+		event_dispatcher.dropped_event_counts = map[uint8]uint64{};
+	}
+	event_dispatcher.dropped_event_counts[overflow_strategy]++;
+	//Return
+}
+
+/**
+* @fn signalSpace_Unsafe
+* @brief Wakes every publisher blocked on a full queue so they can check for room again; the caller must hold `mutex`.
+* @struct event_dispatcher *EventDispatcher_struct
+*/
+
+// signalSpace_Unsafe wakes every publisher blocked on a full queue so they can check for room again; the caller must hold `mutex`.
+func (event_dispatcher *EventDispatcher_struct) signalSpace_Unsafe(){
+	//Variables
+	//Parametres
+	//Function
+	if( event_dispatcher.space_channel != nil ){
+		close(event_dispatcher.space_channel);
+		event_dispatcher.space_channel = nil;
+	}
+	//Return
+}
+
+//# Global Variables
+var(
+	//## Exported Variables
+	//## Private Variables
+);
@@ -0,0 +1,141 @@
+/**
+* @file backpressure_test.go
+* @brief Contains test functions for `backpressure.go`.
+* @author Anadian
+* @copyright 	Copyright 2019 Canosw
+	Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+software and associated documentation files (the "Software"), to deal in the Software 
+without restriction, including without limitation the rights to use, copy, modify, 
+merge, publish, distribute, sublicense, and/or sell copies of the Software, and to 
+permit persons to whom the Software is furnished to do so, subject to the following 
+conditions:
+	The above copyright notice and this permission notice shall be included in all copies 
+or substantial portions of the Software.
+	THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A 
+PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT 
+HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF 
+CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE 
+OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+// backpressure_test contains test functions for `backpressure.go`.
+package event_dispatcher;
+
+//# Dependencies
+import(
+	//## Internal
+	//## Standard
+	"testing"
+	"log"
+	"context"
+	"time"
+	//## External
+	error_report "github.com/Anadian/error_report/source"
+);
+
+//# Exported Functions
+/**
+* @fn TestQueueCapacity
+* @brief Tests each overflow strategy and the dropped-event counters.
+* @param t *testing.T [in] Go stdlib testing object.
+*/
+
+// TestQueueCapacity tests each overflow strategy and the dropped-event counters.
+func TestQueueCapacity( t *testing.T ){
+	//Variables
+	var event_dispatcher *EventDispatcher_struct;
+	var first_event, second_event, third_event Event_struct;
+	var function_return error_report.ErrorReport_struct;
+	var ctx context.Context;
+	var cancel context.CancelFunc;
+	var pushed_channel chan error_report.ErrorReport_struct = make(chan error_report.ErrorReport_struct, 1);
+	var dropped_event_counts map[uint8]uint64;
+	//Parametres
+	//Function
+	first_event = NewEvent( "capacity:first", map[string]interface{}{} ).Data["event"].(Event_struct);
+	second_event = NewEvent( "capacity:second", map[string]interface{}{} ).Data["event"].(Event_struct);
+	third_event = NewEvent( "capacity:third", map[string]interface{}{} ).Data["event"].(Event_struct);
+	event_dispatcher = newEventDispatcher( false, true );
+	function_return = event_dispatcher.SetQueueCapacity( 2, 0 );
+	if( function_return.CodeEqual( ERROR_CODE_INVALID_OVERFLOW_STRATEGY ) == true ){
+		log.Printf("Success: SetQueueCapacity rejected an invalid strategy.\n");
+	} else{
+		t.Fail();
+		log.Printf("Failure: Didn't get ERROR_CODE_INVALID_OVERFLOW_STRATEGY: %v\n", function_return);
+	}
+	///OVERFLOW_STRATEGY_ERROR
+	event_dispatcher.SetQueueCapacity( 2, OVERFLOW_STRATEGY_ERROR );
+	event_dispatcher.PushEvent( first_event );
+	event_dispatcher.PushEvent( second_event );
+	function_return = event_dispatcher.PushEvent( third_event );
+	if( function_return.CodeEqual( ERROR_CODE_QUEUE_FULL ) == true ){
+		log.Printf("Success: OVERFLOW_STRATEGY_ERROR returned ERROR_CODE_QUEUE_FULL.\n");
+	} else{
+		t.Fail();
+		log.Printf("Failure: OVERFLOW_STRATEGY_ERROR returned: %v\n", function_return);
+	}
+	///Replacing a queued event needs no room.
+	function_return = event_dispatcher.InsertEventAtIndex( third_event, 1 );
+	if( function_return.NoError() == true && function_return.Data["events_slice_length"] == 2 && event_dispatcher.GetEventByIndex( 1 ).Data["event"].(Event_struct).name == "capacity:third" ){
+		log.Printf("Success: InsertEventAtIndex replaced an event in the full queue.\n");
+	} else{
+		t.Fail();
+		log.Printf("Failure: InsertEventAtIndex returned: %v\n", function_return);
+	}
+	event_dispatcher.InsertEventAtIndex( second_event, 1 );
+	///OVERFLOW_STRATEGY_DROP_NEWEST
+	event_dispatcher.SetQueueCapacity( 2, OVERFLOW_STRATEGY_DROP_NEWEST );
+	function_return = event_dispatcher.PushEvent( third_event );
+	if( function_return.NoError() == true && function_return.Data["dropped"] == true && event_dispatcher.GetEventByIndex( 1 ).Data["event"].(Event_struct).name == "capacity:second" ){
+		log.Printf("Success: OVERFLOW_STRATEGY_DROP_NEWEST dropped the new event.\n");
+	} else{
+		t.Fail();
+		log.Printf("Failure: OVERFLOW_STRATEGY_DROP_NEWEST returned: %v\n", function_return);
+	}
+	///OVERFLOW_STRATEGY_DROP_OLDEST
+	event_dispatcher.SetQueueCapacity( 2, OVERFLOW_STRATEGY_DROP_OLDEST );
+	function_return = event_dispatcher.PushEvent( third_event );
+	if( function_return.NoError() == true && event_dispatcher.GetEventByIndex( 0 ).Data["event"].(Event_struct).name == "capacity:second" && event_dispatcher.GetEventByIndex( 1 ).Data["event"].(Event_struct).name == "capacity:third" ){
+		log.Printf("Success: OVERFLOW_STRATEGY_DROP_OLDEST dropped the oldest event.\n");
+	} else{
+		t.Fail();
+		log.Printf("Failure: OVERFLOW_STRATEGY_DROP_OLDEST returned: %v\n", function_return);
+	}
+	///OVERFLOW_STRATEGY_BLOCK: times out with the context, then succeeds once room is made.
+	event_dispatcher.SetQueueCapacity( 2, OVERFLOW_STRATEGY_BLOCK );
+	ctx, cancel = context.WithTimeout( context.Background(), 10 * time.Millisecond );
+	function_return = event_dispatcher.PushEventCtx( ctx, first_event );
+	cancel();
+	if( function_return.CodeEqual( ERROR_CODE_QUEUE_FULL ) == true ){
+		log.Printf("Success: OVERFLOW_STRATEGY_BLOCK honoured the context deadline.\n");
+	} else{
+		t.Fail();
+		log.Printf("Failure: OVERFLOW_STRATEGY_BLOCK returned: %v\n", function_return);
+	}
+	go func(){
+		pushed_channel <- event_dispatcher.PushEvent( first_event );
+	}();
+	time.Sleep( 10 * time.Millisecond );
+	event_dispatcher.ShiftEvent();
+	select{
+		case function_return = <-pushed_channel:
+			if( function_return.NoError() == true ){
+				log.Printf("Success: Blocked publisher resumed once room was made.\n");
+			} else{
+				t.Fail();
+				log.Printf("Failure: Blocked publisher returned: %v\n", function_return);
+			}
+		case <-time.After( 5 * time.Second ):
+			t.Fail();
+			log.Printf("Failure: Blocked publisher never resumed.\n");
+	}
+	dropped_event_counts = event_dispatcher.GetDroppedEventCounts().Data["dropped_event_counts"].(map[uint8]uint64);
+	if( dropped_event_counts[OVERFLOW_STRATEGY_ERROR] == 1 && dropped_event_counts[OVERFLOW_STRATEGY_DROP_NEWEST] == 1 && dropped_event_counts[OVERFLOW_STRATEGY_DROP_OLDEST] == 1 && dropped_event_counts[OVERFLOW_STRATEGY_BLOCK] == 1 ){
+		log.Printf("Success: Dropped event counts: %v\n", dropped_event_counts);
+	} else{
+		t.Fail();
+		log.Printf("Failure: Unexpected dropped event counts: %v\n", dropped_event_counts);
+	}
+	//Return
+}
@@ -0,0 +1,594 @@
+/**
+* @file bolt_queue.go
+* @brief A queue backend storing events in a bbolt database.
+* @author Anadian
+* @copyright 	Copyright 2019 Canosw
+	Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+software and associated documentation files (the "Software"), to deal in the Software 
+without restriction, including without limitation the rights to use, copy, modify, 
+merge, publish, distribute, sublicense, and/or sell copies of the Software, and to 
+permit persons to whom the Software is furnished to do so, subject to the following 
+conditions:
+	The above copyright notice and this permission notice shall be included in all copies 
+or substantial portions of the Software.
+	THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A 
+PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT 
+HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF 
+CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE 
+OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+// bolt_queue implements `event_dispatcher.QueueBackend_interface` with a bbolt database.
+package bolt_queue;
+
+//# Dependencies
+import(
+	//## Internal
+	event_dispatcher "github.com/Anadian/event_dispatcher/source"
+	//## Standard
+	"encoding/binary"
+	"time"
+	//## External
+	error_report "github.com/Anadian/error_report/source"
+	bolt "go.etcd.io/bbolt"
+);
+
+//# Constants
+const(
+	//## Exported Constants
+	//## Private Constants
+	bolt_queue_hidden_prefix_length int = 8; //Visible-at time in Unix nanoseconds.
+	bolt_queue_max_codec_name_length int = 255; //Its length is stored in one byte before the name.
+);
+
+//# Types
+//## Structs
+// bolt_queue_backend_struct implements `event_dispatcher.QueueBackend_interface` with three buckets nested in the queue's bucket, all keyed by big-endian numbers so cursors walk them in order: "visible" maps sequence numbers to the codec's name and the encoded event, so the first key is the head of the queue; "hidden" holds dequeued events the same way, prefixed by when they become visible; and "timeouts" indexes the hidden events by that time, so those whose visibility timeout has passed are moved back to "visible", in their original place, without walking the others.
+type bolt_queue_backend_struct struct{
+	database *bolt.DB
+	bucket []byte
+	codec event_dispatcher.Codec_interface
+}
+// bolt_queue_buckets_struct is the queue's buckets within a transaction.
+type bolt_queue_buckets_struct struct{
+	queue *bolt.Bucket
+	visible *bolt.Bucket
+	hidden *bolt.Bucket
+	timeouts *bolt.Bucket
+}
+//### Methods
+/**
+* @fn Enqueue
+* @brief Implements `event_dispatcher.QueueBackend_interface`.
+* @struct bolt_queue_backend *bolt_queue_backend_struct
+* @param event event_dispatcher.Event_struct [in] The event to enqueue.
+* @return ( return_report error_report.ErrorReport_struct ) 
+* @retval 0 Success
+* @retval 1 Not Supported
+* @retval >1 Error
+*/
+
+// Enqueue implements `event_dispatcher.QueueBackend_interface`.
+func (bolt_queue_backend *bolt_queue_backend_struct) Enqueue( event event_dispatcher.Event_struct ) ( return_report error_report.ErrorReport_struct ){
+	//Variables
+	var function_return error_report.ErrorReport_struct;
+	var value []byte;
+	var id uint64;
+	var update_error error;
+	//Parametres
+	//Function
+	function_return = bolt_queue_backend.codec.Encode( event );
+	if( function_return.IsError() == true ){
+		return error_report.New( event_dispatcher.ERROR_CODE_QUEUE_BACKEND_ERROR, map[string]interface{}{ "message": "Couldn't encode the event.", "event": event }, &function_return );
+	}
+	value = newBoltQueueValue( bolt_queue_backend.codec.Name(), function_return.Data["encoded"].([]byte) );
+	update_error = bolt_queue_backend.database.Update( func( transaction *bolt.Tx ) error{
+		var buckets bolt_queue_buckets_struct = bolt_queue_backend.buckets( transaction );
+		var sequence_error error;
+		id, sequence_error = buckets.queue.NextSequence();
+		if( sequence_error != nil ){
+			return sequence_error;
+		}
+		return buckets.visible.Put( boltQueueKey( id ), value );
+	} );
+	return_report = boltQueueReport( update_error, map[string]interface{}{ "id": id } );
+	//Return
+	return return_report;
+}
+
+/**
+* @fn Dequeue
+* @brief Implements `event_dispatcher.QueueBackend_interface`.
+* @struct bolt_queue_backend *bolt_queue_backend_struct
+* @param visibility_timeout time.Duration [in] How long to hide the event.
+* @return ( return_report error_report.ErrorReport_struct ) 
+* @retval 0 Success
+* @retval 1 Not Supported
+* @retval >1 Error
+*/
+
+// Dequeue implements `event_dispatcher.QueueBackend_interface`; it takes the first key of the "visible" bucket.
+func (bolt_queue_backend *bolt_queue_backend_struct) Dequeue( visibility_timeout time.Duration ) ( return_report error_report.ErrorReport_struct ){
+	//Variables
+	var now time.Time = time.Now();
+	var update_error error;
+	var id uint64;
+	var value []byte;
+	//Parametres
+	//Function
+	update_error = bolt_queue_backend.database.Update( func( transaction *bolt.Tx ) error{
+		var buckets bolt_queue_buckets_struct = bolt_queue_backend.buckets( transaction );
+		var key []byte;
+		var restore_error error = buckets.restoreExpired( now );
+		if( restore_error != nil ){
+			return restore_error;
+		}
+		key, value = buckets.visible.Cursor().First();
+		if( key == nil ){
+			return nil;
+		}
+		id = binary.BigEndian.Uint64( key );
+		///Values are only valid during the transaction.
+		value = append([]byte(nil), value...);
+		return buckets.hide( id, value, now.Add( visibility_timeout ).UnixNano() );
+	} );
+	return_report = decodeBoltQueueValue( update_error, id, value );
+	if( return_report.NoError() == true ){
+		return_report.Data["id"] = id;
+	}
+	//Return
+	return return_report;
+}
+
+/**
+* @fn Acknowledge
+* @brief Implements `event_dispatcher.QueueBackend_interface`.
+* @struct bolt_queue_backend *bolt_queue_backend_struct
+* @param id uint64 [in] As returned by `Dequeue`.
+* @return ( return_report error_report.ErrorReport_struct ) 
+* @retval 0 Success
+* @retval 1 Not Supported
+* @retval >1 Error
+*/
+
+// Acknowledge implements `event_dispatcher.QueueBackend_interface`.
+func (bolt_queue_backend *bolt_queue_backend_struct) Acknowledge( id uint64 ) ( return_report error_report.ErrorReport_struct ){
+	//Variables
+	var update_error error;
+	//Parametres
+	//Function
+	update_error = bolt_queue_backend.database.Update( func( transaction *bolt.Tx ) error{
+		var buckets bolt_queue_buckets_struct = bolt_queue_backend.buckets( transaction );
+		var key []byte = boltQueueKey( id );
+		var value []byte = buckets.hidden.Get( key );
+		var delete_error error;
+		if( value != nil ){
+			delete_error = buckets.timeouts.Delete( boltQueueTimeoutKey( boltQueueVisibleAt( value ), id ) );
+			if( delete_error == nil ){
+				delete_error = buckets.hidden.Delete( key );
+			}
+			return delete_error;
+		}
+		///Its visibility timeout may have passed already.
+		return buckets.visible.Delete( key );
+	} );
+	return_report = boltQueueReport( update_error, map[string]interface{}{} );
+	//Return
+	return return_report;
+}
+
+/**
+* @fn Get
+* @brief Implements `event_dispatcher.QueueBackend_interface`.
+* @struct bolt_queue_backend *bolt_queue_backend_struct
+* @param index uint [in] The index among the visible events.
+* @return ( return_report error_report.ErrorReport_struct ) 
+* @retval 0 Success
+* @retval 1 Not Supported
+* @retval >1 Error
+*/
+
+// Get implements `event_dispatcher.QueueBackend_interface`; it walks the first `index` visible events.
+func (bolt_queue_backend *bolt_queue_backend_struct) Get( index uint ) ( return_report error_report.ErrorReport_struct ){
+	//Variables
+	var update_error error;
+	var id uint64;
+	var value []byte;
+	//Parametres
+	//Function
+	update_error = bolt_queue_backend.database.Update( func( transaction *bolt.Tx ) error{
+		var buckets bolt_queue_buckets_struct = bolt_queue_backend.buckets( transaction );
+		var key []byte;
+		var restore_error error = buckets.restoreExpired( time.Now() );
+		if( restore_error != nil ){
+			return restore_error;
+		}
+		key, value = findBoltQueueValue( buckets.visible, index );
+		if( key != nil ){
+			id = binary.BigEndian.Uint64( key );
+			value = append([]byte(nil), value...);
+		}
+		return nil;
+	} );
+	return_report = decodeBoltQueueValue( update_error, id, value );
+	//Return
+	return return_report;
+}
+
+/**
+* @fn Remove
+* @brief Implements `event_dispatcher.QueueBackend_interface`.
+* @struct bolt_queue_backend *bolt_queue_backend_struct
+* @param index uint [in] The index among the visible events.
+* @return ( return_report error_report.ErrorReport_struct ) 
+* @retval 0 Success
+* @retval 1 Not Supported
+* @retval >1 Error
+*/
+
+// Remove implements `event_dispatcher.QueueBackend_interface`; it walks the first `index` visible events.
+func (bolt_queue_backend *bolt_queue_backend_struct) Remove( index uint ) ( return_report error_report.ErrorReport_struct ){
+	//Variables
+	var update_error error;
+	var id uint64;
+	var value []byte;
+	//Parametres
+	//Function
+	update_error = bolt_queue_backend.database.Update( func( transaction *bolt.Tx ) error{
+		var buckets bolt_queue_buckets_struct = bolt_queue_backend.buckets( transaction );
+		var key []byte;
+		var restore_error error = buckets.restoreExpired( time.Now() );
+		if( restore_error != nil ){
+			return restore_error;
+		}
+		key, value = findBoltQueueValue( buckets.visible, index );
+		if( key == nil ){
+			return nil;
+		}
+		id = binary.BigEndian.Uint64( key );
+		value = append([]byte(nil), value...);
+		return buckets.visible.Delete( key );
+	} );
+	return_report = decodeBoltQueueValue( update_error, id, value );
+	//Return
+	return return_report;
+}
+
+/**
+* @fn Length
+* @brief Implements `event_dispatcher.QueueBackend_interface`.
+* @struct bolt_queue_backend *bolt_queue_backend_struct
+* @return ( return_report error_report.ErrorReport_struct ) 
+* @retval 0 Success
+* @retval 1 Not Supported
+* @retval >1 Error
+*/
+
+// Length implements `event_dispatcher.QueueBackend_interface`; it walks the visible events.
+func (bolt_queue_backend *bolt_queue_backend_struct) Length() ( return_report error_report.ErrorReport_struct ){
+	//Variables
+	var length int;
+	var update_error error;
+	//Parametres
+	//Function
+	update_error = bolt_queue_backend.database.Update( func( transaction *bolt.Tx ) error{
+		var buckets bolt_queue_buckets_struct = bolt_queue_backend.buckets( transaction );
+		var cursor *bolt.Cursor;
+		var key []byte;
+		var restore_error error = buckets.restoreExpired( time.Now() );
+		if( restore_error != nil ){
+			return restore_error;
+		}
+		///Stats don't count this transaction's changes.
+		cursor = buckets.visible.Cursor();
+		for key, _ = cursor.First(); key != nil; key, _ = cursor.Next() {
+			length++;
+		}
+		return nil;
+	} );
+	return_report = boltQueueReport( update_error, map[string]interface{}{ "length": length } );
+	//Return
+	return return_report;
+}
+
+/**
+* @fn buckets
+* @brief Returns the queue's buckets within the transaction.
+* @struct bolt_queue_backend *bolt_queue_backend_struct
+* @param transaction *bolt.Tx [in] The transaction.
+* @return bolt_queue_buckets_struct
+*/
+
+// buckets returns the queue's buckets within the transaction; `New` created them.
+func (bolt_queue_backend *bolt_queue_backend_struct) buckets( transaction *bolt.Tx ) bolt_queue_buckets_struct{
+	//Variables
+	var buckets bolt_queue_buckets_struct;
+	//Parametres
+	//Function
+	buckets.queue = transaction.Bucket( bolt_queue_backend.bucket );
+	buckets.visible = buckets.queue.Bucket( bolt_queue_visible_bucket );
+	buckets.hidden = buckets.queue.Bucket( bolt_queue_hidden_bucket );
+	buckets.timeouts = buckets.queue.Bucket( bolt_queue_timeouts_bucket );
+	//Return
+	return buckets;
+}
+
+/**
+* @fn hide
+* @brief Moves a visible event to the hidden events until the given time.
+* @struct buckets bolt_queue_buckets_struct
+* @param id uint64 [in] The event's sequence number.
+* @param value []byte [in] Its value in the "visible" bucket.
+* @param visible_at int64 [in] When it becomes visible again, in Unix nanoseconds.
+* @return error
+*/
+
+// hide moves a visible event to the hidden events until the given time.
+func (buckets bolt_queue_buckets_struct) hide( id uint64, value []byte, visible_at int64 ) error{
+	//Variables
+	var hidden_value []byte = make([]byte, bolt_queue_hidden_prefix_length, bolt_queue_hidden_prefix_length + len(value));
+	var put_error error;
+	//Parametres
+	//Function
+	binary.BigEndian.PutUint64( hidden_value, uint64(visible_at) );
+	hidden_value = append(hidden_value, value...);
+	put_error = buckets.visible.Delete( boltQueueKey( id ) );
+	if( put_error == nil ){
+		put_error = buckets.hidden.Put( boltQueueKey( id ), hidden_value );
+	}
+	if( put_error == nil ){
+		put_error = buckets.timeouts.Put( boltQueueTimeoutKey( visible_at, id ), []byte{} );
+	}
+	//Return
+	return put_error;
+}
+
+/**
+* @fn restoreExpired
+* @brief Moves the hidden events whose visibility timeout has passed back to the visible events.
+* @struct buckets bolt_queue_buckets_struct
+* @param now time.Time [in] The time to judge visibility by.
+* @return error
+*/
+
+// restoreExpired moves the hidden events whose visibility timeout has passed back to the visible events, under their original keys; it only walks the expired timeouts.
+func (buckets bolt_queue_buckets_struct) restoreExpired( now time.Time ) error{
+	//Variables
+	var now_nanoseconds int64 = now.UnixNano();
+	var cursor *bolt.Cursor = buckets.timeouts.Cursor();
+	var expired_keys [][]byte;
+	var key []byte;
+	var id []byte;
+	var move_error error;
+	//Parametres
+	//Function
+	///Deleting while walking a cursor can skip keys, so collect them first.
+	for key, _ = cursor.First(); key != nil && int64(binary.BigEndian.Uint64( key[0:8] )) <= now_nanoseconds; key, _ = cursor.Next() {
+		expired_keys = append(expired_keys, append([]byte(nil), key...));
+	}
+	for _, key = range expired_keys {
+		id = key[8:16];
+		move_error = buckets.visible.Put( id, append([]byte(nil), buckets.hidden.Get( id )[bolt_queue_hidden_prefix_length:]...) );
+		if( move_error == nil ){
+			move_error = buckets.hidden.Delete( id );
+		}
+		if( move_error == nil ){
+			move_error = buckets.timeouts.Delete( key );
+		}
+		if( move_error != nil ){
+			return move_error;
+		}
+	}
+	//Return
+	return nil;
+}
+
+//# Global Variables
+var(
+	//## Exported Variables
+	//## Private Variables
+	bolt_queue_visible_bucket []byte = []byte("visible");
+	bolt_queue_hidden_bucket []byte = []byte("hidden");
+	bolt_queue_timeouts_bucket []byte = []byte("timeouts");
+);
+
+//# Exported Functions
+/**
+* @fn New
+* @brief Creates a queue backend storing events in a bucket of a bbolt database.
+* @param database *bolt.DB [in] An open database; the caller remains responsible for closing it.
+* @param bucket string [in] The bucket's name, created if need be; the bucket should be used for nothing else.
+* @param codec event_dispatcher.Codec_interface [in] How to encode events; the JSON codec if nil. Its name must be at most 255 bytes long.
+* @return ( return_report error_report.ErrorReport_struct ) 
+* @retval 0 Success
+* @retval 1 Not Supported
+* @retval >1 Error
+*/
+
+// New creates a queue backend storing events in a bucket of a bbolt database, returned as the "queue_backend" datum for `event_dispatcher.NewEventDispatcherWithQueueBackend`. Every operation is a bbolt transaction, so it's durable once it returns unless the database was opened with `NoSync`. Dequeuing takes the first key of the visible events, however many events are in flight.
+func New( database *bolt.DB, bucket string, codec event_dispatcher.Codec_interface ) ( return_report error_report.ErrorReport_struct ){
+	//Variables
+	var update_error error;
+	//Parametres
+	if( database == nil || bucket == "" ){
+		return error_report.New( event_dispatcher.ERROR_CODE_QUEUE_BACKEND_ERROR, map[string]interface{}{ "message": "database and bucket are required.", "bucket": bucket }, nil );
+	}
+	if( codec == nil ){
+		codec = event_dispatcher.JSONCodec;
+	}
+	if( len(codec.Name()) > bolt_queue_max_codec_name_length ){
+		return error_report.New( event_dispatcher.ERROR_CODE_QUEUE_BACKEND_ERROR, map[string]interface{}{ "message": "The codec's name is longer than 255 bytes.", "codec_name": codec.Name() }, nil );
+	}
+	//Function
+	update_error = database.Update( func( transaction *bolt.Tx ) error{
+		var queue_bucket *bolt.Bucket;
+		var create_error error;
+		var name []byte;
+		queue_bucket, create_error = transaction.CreateBucketIfNotExists( []byte(bucket) );
+		for _, name = range [][]byte{ bolt_queue_visible_bucket, bolt_queue_hidden_bucket, bolt_queue_timeouts_bucket } {
+			if( create_error == nil ){
+				_, create_error = queue_bucket.CreateBucketIfNotExists( name );
+			}
+		}
+		return create_error;
+	} );
+	return_report = boltQueueReport( update_error, map[string]interface{}{ "queue_backend": event_dispatcher.QueueBackend_interface(&bolt_queue_backend_struct{ database: database, bucket: []byte(bucket), codec: codec }) } );
+	//Return
+	return return_report;
+}
+
+//# Private Functions
+/**
+* @fn findBoltQueueValue
+* @brief Returns the key and value at the index in the bucket.
+* @param bucket *bolt.Bucket [in] The "visible" bucket.
+* @param index uint [in] The index among the visible events.
+* @return ( key []byte, value []byte ) Only valid during the transaction; nil past the end.
+*/
+
+// findBoltQueueValue returns the key and value at the index in the bucket; they're only valid during the transaction, and nil past the end.
+func findBoltQueueValue( bucket *bolt.Bucket, index uint ) ( key []byte, value []byte ){
+	//Variables
+	var cursor *bolt.Cursor = bucket.Cursor();
+	//Parametres
+	//Function
+	for key, value = cursor.First(); key != nil && index > 0; key, value = cursor.Next() {
+		index--;
+	}
+	//Return
+	return key, value;
+}
+
+/**
+* @fn decodeBoltQueueValue
+* @brief Turns the outcome of looking up a value into a report with the decoded "event" datum.
+* @param transaction_error error [in] The error returned by the transaction.
+* @param id uint64 [in] The event's sequence number; 0 if nothing was found.
+* @param value []byte [in] Its value, copied out of the transaction.
+* @return ( return_report error_report.ErrorReport_struct ) 
+* @retval 0 Success
+* @retval 1 Not Supported
+* @retval >1 Error
+*/
+
+// decodeBoltQueueValue turns the outcome of looking up a value into a report with the decoded "event" datum; ERROR_CODE_INDEX_OUT_OF_RANGE if nothing was found, and the "id" datum with ERROR_CODE_UNDECODABLE_QUEUED_EVENT, including for values too short to hold the codec's name.
+func decodeBoltQueueValue( transaction_error error, id uint64, value []byte ) ( return_report error_report.ErrorReport_struct ){
+	//Variables
+	//Parametres
+	//Function
+	if( transaction_error != nil ){
+		return_report = boltQueueReport( transaction_error, nil );
+	} else if( id == 0 ){
+		return_report = error_report.New( event_dispatcher.ERROR_CODE_INDEX_OUT_OF_RANGE, map[string]interface{}{ "message": "No visible event at that index." }, nil );
+	} else if( len(value) == 0 || len(value) < 1 + int(value[0]) ){
+		return_report = error_report.New( event_dispatcher.ERROR_CODE_UNDECODABLE_QUEUED_EVENT, map[string]interface{}{ "message": "The stored value is too short for its codec's name.", "id": id }, nil );
+	} else{
+		return_report = event_dispatcher.DecodeQueuedEvent( string(value[1:1 + int(value[0])]), value[1 + int(value[0]):] );
+		if( return_report.IsError() == true ){
+			return_report.Data["id"] = id;
+		}
+	}
+	//Return
+	return return_report;
+}
+
+/**
+* @fn newBoltQueueValue
+* @brief Returns the stored form of an encoded event.
+* @param codec_name string [in] The codec it was encoded with.
+* @param encoded []byte [in] The encoded event.
+* @return []byte
+*/
+
+// newBoltQueueValue returns the stored form of an encoded event: the length of the codec's name, the name, and the encoded event.
+func newBoltQueueValue( codec_name string, encoded []byte ) []byte{
+	//Variables
+	var value []byte = make([]byte, 1, 1 + len(codec_name) + len(encoded));
+	//Parametres
+	//Function
+	value[0] = uint8(len(codec_name));
+	value = append(value, codec_name...);
+	value = append(value, encoded...);
+	//Return
+	return value;
+}
+
+/**
+* @fn boltQueueVisibleAt
+* @brief Returns when the hidden event becomes visible, in Unix nanoseconds.
+* @param value []byte [in] The value in the "hidden" bucket.
+* @return int64
+*/
+
+// boltQueueVisibleAt returns when the hidden event becomes visible, in Unix nanoseconds.
+func boltQueueVisibleAt( value []byte ) int64{
+	//Variables
+	//Parametres
+	//Function
+	//Return
+	return int64(binary.BigEndian.Uint64( value[0:bolt_queue_hidden_prefix_length] ));
+}
+
+/**
+* @fn boltQueueKey
+* @brief Returns the key for the sequence number.
+* @param id uint64 [in] The sequence number.
+* @return []byte
+*/
+
+// boltQueueKey returns the key for the sequence number; big-endian so keys sort numerically.
+func boltQueueKey( id uint64 ) []byte{
+	//Variables
+	var key []byte = make([]byte, 8);
+	//Parametres
+	//Function
+	binary.BigEndian.PutUint64( key, id );
+	//Return
+	return key;
+}
+
+/**
+* @fn boltQueueTimeoutKey
+* @brief Returns the "timeouts" key for a hidden event.
+* @param visible_at int64 [in] When it becomes visible, in Unix nanoseconds.
+* @param id uint64 [in] Its sequence number.
+* @return []byte
+*/
+
+// boltQueueTimeoutKey returns the "timeouts" key for a hidden event: when it becomes visible, then its sequence number, both big-endian so keys sort by time.
+func boltQueueTimeoutKey( visible_at int64, id uint64 ) []byte{
+	//Variables
+	var key []byte = make([]byte, 16);
+	//Parametres
+	//Function
+	binary.BigEndian.PutUint64( key[0:8], uint64(visible_at) );
+	binary.BigEndian.PutUint64( key[8:16], id );
+	//Return
+	return key;
+}
+
+/**
+* @fn boltQueueReport
+* @brief Returns a report with the data, or ERROR_CODE_QUEUE_BACKEND_ERROR if the transaction failed.
+* @param transaction_error error [in] The error returned by the transaction.
+* @param data map[string]interface{} [in] The data on success.
+* @return ( return_report error_report.ErrorReport_struct ) 
+* @retval 0 Success
+* @retval 1 Not Supported
+* @retval >1 Error
+*/
+
+// boltQueueReport returns a report with the data, or ERROR_CODE_QUEUE_BACKEND_ERROR if the transaction failed.
+func boltQueueReport( transaction_error error, data map[string]interface{} ) ( return_report error_report.ErrorReport_struct ){
+	//Variables
+	//Parametres
+	//Function
+	if( transaction_error == nil ){
+		return_report = error_report.New( 0, data, nil );
+	} else{
+		return_report = error_report.New( event_dispatcher.ERROR_CODE_QUEUE_BACKEND_ERROR, map[string]interface{}{ "message": transaction_error.Error(), "error": transaction_error }, nil );
+	}
+	//Return
+	return return_report;
+}
@@ -0,0 +1,103 @@
+/**
+* @file bolt_queue_test.go
+* @brief Contains test functions for `bolt_queue.go`.
+* @author Anadian
+* @copyright 	Copyright 2019 Canosw
+	Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+software and associated documentation files (the "Software"), to deal in the Software 
+without restriction, including without limitation the rights to use, copy, modify, 
+merge, publish, distribute, sublicense, and/or sell copies of the Software, and to 
+permit persons to whom the Software is furnished to do so, subject to the following 
+conditions:
+	The above copyright notice and this permission notice shall be included in all copies 
+or substantial portions of the Software.
+	THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A 
+PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT 
+HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF 
+CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE 
+OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+// bolt_queue_test contains test functions for `bolt_queue.go`.
+package bolt_queue;
+
+//# Dependencies
+import(
+	//## Internal
+	event_dispatcher "github.com/Anadian/event_dispatcher/source"
+	queue_backend_testing "github.com/Anadian/event_dispatcher/source/queue_backend_testing"
+	//## Standard
+	"testing"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"log"
+	//## External
+	error_report "github.com/Anadian/error_report/source"
+	bolt "go.etcd.io/bbolt"
+);
+
+//# Types
+//## Structs
+// long_name_codec_struct is a codec whose name is too long to store.
+type long_name_codec_struct struct{
+	event_dispatcher.Codec_interface
+}
+//### Methods
+/**
+* @fn Name
+* @brief Implements `event_dispatcher.Codec_interface` with a 256-byte name.
+* @struct long_name_codec long_name_codec_struct
+* @return string
+*/
+
+// Name implements `event_dispatcher.Codec_interface` with a 256-byte name.
+func (long_name_codec long_name_codec_struct) Name() string{
+	//Variables
+	//Parametres
+	//Function
+	//Return
+	return strings.Repeat( "x", 256 );
+}
+
+//# Exported Functions
+/**
+* @fn TestBoltQueueBackend
+* @brief Runs the queue backend tests against a bbolt database.
+* @param t *testing.T [in] Go stdlib testing object.
+*/
+
+// TestBoltQueueBackend runs the queue backend tests against a bbolt database, with and without a dispatcher.
+func TestBoltQueueBackend( t *testing.T ){
+	//Variables
+	var database *bolt.DB;
+	var buckets int;
+	var open_error error;
+	var new_queue_backend func( codec event_dispatcher.Codec_interface ) event_dispatcher.QueueBackend_interface;
+	//Parametres
+	//Function
+	database, open_error = bolt.Open( filepath.Join( t.TempDir(), "queue.db" ), 0600, nil );
+	if( open_error != nil ){
+		t.Fatalf("Failure: bolt.Open returned: %v\n", open_error);
+	}
+	defer database.Close();
+	new_queue_backend = func( codec event_dispatcher.Codec_interface ) event_dispatcher.QueueBackend_interface{
+		var function_return error_report.ErrorReport_struct;
+		buckets++;
+		function_return = New( database, "events_" + strconv.Itoa( buckets ), codec );
+		if( function_return.IsError() == true ){
+			t.Fatalf("Failure: New returned: %v\n", function_return);
+		}
+		return function_return.Data["queue_backend"].(event_dispatcher.QueueBackend_interface);
+	};
+	queue_backend_testing.TestQueueBackend( t, new_queue_backend );
+	queue_backend_testing.TestEventDispatcher( t, new_queue_backend );
+	if( New( database, "events_long_name", long_name_codec_struct{ event_dispatcher.JSONCodec } ).CodeEqual( event_dispatcher.ERROR_CODE_QUEUE_BACKEND_ERROR ) == true ){
+		log.Printf("Success: New rejected a codec whose name is too long to store.\n");
+	} else{
+		t.Fail();
+		log.Printf("Failure: New accepted a codec whose name is too long to store.\n");
+	}
+	//Return
+}
@@ -0,0 +1,673 @@
+/**
+* @file cloudevents.go
+* @brief Conversion between events and CloudEvents 1.0, in structured JSON and binary HTTP modes.
+* @author Anadian
+* @copyright 	Copyright 2019 Canosw
+	Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+software and associated documentation files (the "Software"), to deal in the Software 
+without restriction, including without limitation the rights to use, copy, modify, 
+merge, publish, distribute, sublicense, and/or sell copies of the Software, and to 
+permit persons to whom the Software is furnished to do so, subject to the following 
+conditions:
+	The above copyright notice and this permission notice shall be included in all copies 
+or substantial portions of the Software.
+	THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A 
+PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT 
+HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF 
+CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE 
+OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+package event_dispatcher;
+
+//# Dependencies
+import(
+	//## Internal
+	//## Standard
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+	//## External
+	error_report "github.com/Anadian/error_report/source"
+);
+
+//# Constants
+const(
+	//## Exported Constants
+	//### Errors
+	ERROR_CODE_INVALID_CLOUD_EVENT int64 = 33;
+	//### CloudEvents
+	CLOUD_EVENTS_SPEC_VERSION string = "1.0";
+	CLOUD_EVENTS_JSON_CONTENT_TYPE string = "application/cloudevents+json" //The Content-Type of a structured-mode JSON CloudEvent.
+	CLOUD_EVENTS_HTTP_HEADER_PREFIX string = "Ce-" //Binary-mode HTTP headers are this followed by the attribute name.
+	//## Private Constants
+	cloud_events_correlation_id_extension string = "correlationid";
+	cloud_events_causation_id_extension string = "causationid";
+	cloud_events_schema_version_extension string = "schemaversion";
+);
+
+//# Types
+//## Structs
+// CloudEvent_struct is a CloudEvents 1.0 event. `Data` holds the encoded data, JSON when `Data_content_type` is JSON or empty, and extension attribute values are kept as strings.
+type CloudEvent_struct struct{
+	Spec_version string
+	ID string
+	Source string
+	Type string
+	Data_content_type string
+	Data_schema string
+	Subject string
+	Time time.Time
+	Data []byte
+	Extensions map[string]string
+}
+//### Methods
+/**
+* @fn Validate
+* @brief Checks the CloudEvent's attributes against the CloudEvents 1.0 specification.
+* @struct cloud_event CloudEvent_struct
+* @return ( return_report error_report.ErrorReport_struct ) 
+* @retval 0 Success
+* @retval 1 Not Supported
+* @retval >1 Error
+*/
+
+// Validate checks the CloudEvent's attributes against the CloudEvents 1.0 specification, returning ERROR_CODE_INVALID_CLOUD_EVENT with the offending "attribute" if one is missing or malformed.
+func (cloud_event CloudEvent_struct) Validate() ( return_report error_report.ErrorReport_struct ){
+	//Variables
+	var attribute string;
+	var message string;
+	var source_url *url.URL;
+	var data_schema_url *url.URL;
+	var parse_error error;
+	var name string;
+	//Parametres
+	//Function
+	if( cloud_event.Spec_version != CLOUD_EVENTS_SPEC_VERSION ){
+		attribute, message = "specversion", fmt.Sprintf( "specversion must be %q.", CLOUD_EVENTS_SPEC_VERSION );
+	} else if( cloud_event.ID == "" ){
+		attribute, message = "id", "id must be a non-empty string.";
+	} else if( cloud_event.Type == "" ){
+		attribute, message = "type", "type must be a non-empty string.";
+	} else if( cloud_event.Source == "" ){
+		attribute, message = "source", "source must be a non-empty URI-reference.";
+	} else if source_url, parse_error = url.Parse( cloud_event.Source ); parse_error != nil || source_url == nil {
+		attribute, message = "source", "source must be a URI-reference.";
+	} else if _, _, parse_error = mime.ParseMediaType( cloud_event.Data_content_type ); cloud_event.Data_content_type != "" && parse_error != nil {
+		attribute, message = "datacontenttype", "datacontenttype must be an RFC 2046 media type.";
+	} else if data_schema_url, parse_error = url.Parse( cloud_event.Data_schema ); cloud_event.Data_schema != "" && ( parse_error != nil || data_schema_url.IsAbs() == false ) {
+		attribute, message = "dataschema", "dataschema must be an absolute URI.";
+	} else{
+		for name = range cloud_event.Extensions {
+			if( isCloudEventsAttributeName( name ) == false ){
+				attribute, message = name, "Extension attribute names must consist of lower-case letters and digits.";
+			} else if( isCloudEventsContextAttribute( name ) == true ){
+				attribute, message = name, "Extension attributes can't reuse the name of a context attribute.";
+			}
+		}
+	}
+	if( attribute == "" ){
+		return_report = error_report.New( 0, map[string]interface{}{}, nil );
+	} else{
+		return_report = error_report.New( ERROR_CODE_INVALID_CLOUD_EVENT, map[string]interface{}{ "message": message, "attribute": attribute }, nil );
+	}
+	//Return
+	return return_report;
+}
+
+/**
+* @fn MarshalJSON
+* @brief Encodes the CloudEvent in the structured-mode JSON format.
+* @struct cloud_event CloudEvent_struct
+* @return ( []byte, error )
+*/
+
+// MarshalJSON encodes the CloudEvent in the structured-mode JSON format, with JSON data embedded as is and any other data in "data_base64".
+func (cloud_event CloudEvent_struct) MarshalJSON() ( []byte, error ){
+	//Variables
+	var attributes map[string]interface{} = map[string]interface{}{};
+	var name string;
+	var value string;
+	//Parametres
+	//Function
+	for name, value = range cloud_event.Extensions {
+		attributes[name] = value;
+	}
+	attributes["specversion"] = cloud_event.Spec_version;
+	attributes["id"] = cloud_event.ID;
+	attributes["source"] = cloud_event.Source;
+	attributes["type"] = cloud_event.Type;
+	if( cloud_event.Data_content_type != "" ){
+		attributes["datacontenttype"] = cloud_event.Data_content_type;
+	}
+	if( cloud_event.Data_schema != "" ){
+		attributes["dataschema"] = cloud_event.Data_schema;
+	}
+	if( cloud_event.Subject != "" ){
+		attributes["subject"] = cloud_event.Subject;
+	}
+	if( cloud_event.Time.IsZero() == false ){
+		attributes["time"] = cloud_event.Time.Format( time.RFC3339Nano );
+	}
+	if( cloud_event.Data != nil ){
+		if( isJSONContentType( cloud_event.Data_content_type ) == true ){
+			attributes["data"] = json.RawMessage(cloud_event.Data);
+		} else{
+			attributes["data_base64"] = base64.StdEncoding.EncodeToString( cloud_event.Data );
+		}
+	}
+	//Return
+	return json.Marshal( attributes );
+}
+
+/**
+* @fn UnmarshalJSON
+* @brief Decodes a structured-mode JSON CloudEvent.
+* @struct cloud_event *CloudEvent_struct
+* @param encoded []byte [in] The JSON.
+* @return error
+*/
+
+// UnmarshalJSON decodes a structured-mode JSON CloudEvent; it doesn't validate it.
+func (cloud_event *CloudEvent_struct) UnmarshalJSON( encoded []byte ) error{
+	//Variables
+	var attributes map[string]json.RawMessage;
+	var name string;
+	var raw json.RawMessage;
+	var text string;
+	var value interface{};
+	var data_raw json.RawMessage;
+	var decode_error error;
+	//Parametres
+	//Function
+	decode_error = json.Unmarshal( encoded, &attributes );
+	if( decode_error != nil ){
+		return decode_error;
+	}
+	*cloud_event = CloudEvent_struct{};
+	for name, raw = range attributes {
+		switch name {
+			case "data":
+				data_raw = raw;
+			case "data_base64":
+				if decode_error = json.Unmarshal( raw, &text ); decode_error == nil {
+					cloud_event.Data, decode_error = base64.StdEncoding.DecodeString( text );
+				}
+			default:
+				///Extension attributes may be any JSON scalar; they're kept in their canonical string form.
+				if decode_error = json.Unmarshal( raw, &value ); decode_error == nil {
+					text = fmt.Sprint( value );
+					if( value == nil ){
+						text = "";
+					}
+					decode_error = cloud_event.setAttribute( name, text );
+				}
+		}
+		if( decode_error != nil ){
+			return fmt.Errorf( "CloudEvent attribute %q: %w", name, decode_error );
+		}
+	}
+	///JSON data is embedded as is; anything else, such as text, is a JSON string.
+	if( data_raw != nil ){
+		if( isJSONContentType( cloud_event.Data_content_type ) == true ){
+			cloud_event.Data = []byte(data_raw);
+		} else if decode_error = json.Unmarshal( data_raw, &text ); decode_error == nil {
+			cloud_event.Data = []byte(text);
+		} else{
+			return fmt.Errorf( "CloudEvent attribute \"data\": %w", decode_error );
+		}
+	}
+	//Return
+	return nil;
+}
+
+/**
+* @fn setAttribute
+* @brief Sets a context or extension attribute from its string form.
+* @struct cloud_event *CloudEvent_struct
+* @param name string [in] The attribute's name.
+* @param value string [in] Its string form.
+* @return error Only for a malformed time.
+*/
+
+// setAttribute sets a context or extension attribute from its string form; only fails for a malformed time.
+func (cloud_event *CloudEvent_struct) setAttribute( name string, value string ) error{
+	//Variables
+	var parse_error error;
+	//Parametres
+	//Function
+	switch name {
+		case "specversion":
+			cloud_event.Spec_version = value;
+		case "id":
+			cloud_event.ID = value;
+		case "source":
+			cloud_event.Source = value;
+		case "type":
+			cloud_event.Type = value;
+		case "datacontenttype":
+			cloud_event.Data_content_type = value;
+		case "dataschema":
+			cloud_event.Data_schema = value;
+		case "subject":
+			cloud_event.Subject = value;
+		case "time":
+			cloud_event.Time, parse_error = time.Parse( time.RFC3339Nano, value );
+		default:
+			if( cloud_event.Extensions == nil ){
+				cloud_event.Extensions = map[string]string{};
+			}
+			cloud_event.Extensions[name] = value;
+	}
+	//Return
+	return parse_error;
+}
+
+/**
+* @fn ToCloudEvent
+* @brief Converts the event to a CloudEvent.
+* @struct event Event_struct
+* @return ( return_report error_report.ErrorReport_struct ) 
+* @retval 0 Success
+* @retval 1 Not Supported
+* @retval >1 Error
+*/
+
+// ToCloudEvent converts the event to a validated CloudEvent, the "cloud_event" datum. The name becomes `type`, the ID `id`, the source `source` (so must be set with `WithSource`), and the "creation_time" stamp `time`. The correlation ID, causation ID, and schema version become the "correlationid", "causationid", and "schemaversion" extensions; headers named "subject" or "dataschema" become those attributes and the rest become extensions. The remaining data, without the time stamps, is encoded as a JSON object.
+func (event Event_struct) ToCloudEvent() ( return_report error_report.ErrorReport_struct ){
+	//Variables
+	var cloud_event CloudEvent_struct;
+	var data map[string]interface{};
+	var name string;
+	var value string;
+	var creation_time time.Time;
+	var ok bool;
+	var encode_error error;
+	//Parametres
+	//Function
+	cloud_event.Spec_version = CLOUD_EVENTS_SPEC_VERSION;
+	cloud_event.ID = event.id;
+	cloud_event.Source = event.source;
+	cloud_event.Type = event.name;
+	for name, value = range event.headers {
+		if( name == "subject" || name == "dataschema" ){
+			cloud_event.setAttribute( name, value );
+		} else{
+			if( cloud_event.Extensions == nil ){
+				cloud_event.Extensions = map[string]string{};
+			}
+			cloud_event.Extensions[name] = value;
+		}
+	}
+	if( event.correlation_id != "" ){
+		cloud_event.Extensions = setExtension( cloud_event.Extensions, cloud_events_correlation_id_extension, event.correlation_id );
+	}
+	if( event.causation_id != "" ){
+		cloud_event.Extensions = setExtension( cloud_event.Extensions, cloud_events_causation_id_extension, event.causation_id );
+	}
+	if( event.schema_version != "" ){
+		cloud_event.Extensions = setExtension( cloud_event.Extensions, cloud_events_schema_version_extension, event.schema_version );
+	}
+	creation_time, ok = event.data["creation_time"].(time.Time);
+	if( ok == true ){
+		cloud_event.Time = creation_time;
+	}
+	data = event.Data();
+	delete( data, "creation_time" );
+	delete( data, "submission_time" );
+	delete( data, "transmission_time" );
+	if( len(data) > 0 ){
+		cloud_event.Data_content_type = "application/json";
+		cloud_event.Data, encode_error = json.Marshal( data );
+	}
+	if( encode_error != nil ){
+		return_report = error_report.New( ERROR_CODE_INVALID_CLOUD_EVENT, map[string]interface{}{ "message": fmt.Sprintf( "The event's data couldn't be encoded as JSON: %v", encode_error ), "attribute": "data", "error": encode_error }, nil );
+	} else{
+		return_report = cloud_event.Validate();
+		if( return_report.NoError() == true ){
+			return_report.Data["cloud_event"] = cloud_event;
+		}
+	}
+	//Return
+	return return_report;
+}
+
+//# Global Variables
+var(
+	//## Exported Variables
+	//## Private Variables
+	cloud_events_context_attributes_slice []string = []string{ "specversion", "id", "source", "type", "datacontenttype", "dataschema", "subject", "time", "data", "data_base64" };
+);
+
+//# Exported Functions
+/**
+* @fn NewEventFromCloudEvent
+* @brief Converts a CloudEvent to an event, the reverse of `ToCloudEvent`.
+* @param cloud_event CloudEvent_struct [in] The CloudEvent; validated first.
+* @return ( return_report error_report.ErrorReport_struct ) 
+* @retval 0 Success
+* @retval 1 Not Supported
+* @retval >1 Error
+*/
+
+// NewEventFromCloudEvent converts a CloudEvent to an event, the reverse of `ToCloudEvent`. Data which is a JSON object becomes the event's data; any other JSON value is stored under `TOPIC_PAYLOAD_KEY`, and non-JSON data as a []byte under "data".
+func NewEventFromCloudEvent( cloud_event CloudEvent_struct ) ( return_report error_report.ErrorReport_struct ){
+	//Variables
+	var event Event_struct;
+	var data map[string]interface{};
+	var payload interface{};
+	var name string;
+	var value string;
+	var decode_error error;
+	//Parametres
+	//Function
+	return_report = cloud_event.Validate();
+	if( return_report.IsError() == true ){
+		return return_report;
+	}
+	if( cloud_event.Data != nil ){
+		if( isJSONContentType( cloud_event.Data_content_type ) == true ){
+			decode_error = json.Unmarshal( cloud_event.Data, &payload );
+			data, _ = payload.(map[string]interface{});
+			if( data == nil && decode_error == nil ){
+				data = map[string]interface{}{ TOPIC_PAYLOAD_KEY: payload };
+			}
+		} else{
+			data = map[string]interface{}{ "data": cloud_event.Data };
+		}
+	}
+	if( decode_error != nil ){
+		return error_report.New( ERROR_CODE_INVALID_CLOUD_EVENT, map[string]interface{}{ "message": fmt.Sprintf( "The CloudEvent's data isn't valid JSON: %v", decode_error ), "attribute": "data", "error": decode_error }, nil );
+	}
+	event = NewEvent( cloud_event.Type, data ).Data["event"].(Event_struct);
+	event.id = cloud_event.ID;
+	event.source = cloud_event.Source;
+	if( cloud_event.Time.IsZero() == false ){
+		event.data["creation_time"] = cloud_event.Time;
+	}
+	if( cloud_event.Subject != "" ){
+		event = event.WithHeader( "subject", cloud_event.Subject );
+	}
+	if( cloud_event.Data_schema != "" ){
+		event = event.WithHeader( "dataschema", cloud_event.Data_schema );
+	}
+	for name, value = range cloud_event.Extensions {
+		switch name {
+			case cloud_events_correlation_id_extension:
+				event.correlation_id = value;
+			case cloud_events_causation_id_extension:
+				event.causation_id = value;
+			case cloud_events_schema_version_extension:
+				event.schema_version = value;
+			default:
+				event = event.WithHeader( name, value );
+		}
+	}
+	return_report = error_report.New( 0, map[string]interface{}{ "event": event }, nil );
+	//Return
+	return return_report;
+}
+
+/**
+* @fn MarshalCloudEventJSON
+* @brief Encodes the event as a structured-mode JSON CloudEvent.
+* @param event Event_struct [in] The event; see `ToCloudEvent`.
+* @return ( return_report error_report.ErrorReport_struct ) 
+* @retval 0 Success
+* @retval 1 Not Supported
+* @retval >1 Error
+*/
+
+// MarshalCloudEventJSON encodes the event as a structured-mode JSON CloudEvent, the "json" datum.
+func MarshalCloudEventJSON( event Event_struct ) ( return_report error_report.ErrorReport_struct ){
+	//Variables
+	var encoded []byte;
+	var encode_error error;
+	//Parametres
+	//Function
+	return_report = event.ToCloudEvent();
+	if( return_report.NoError() == true ){
+		encoded, encode_error = json.Marshal( return_report.Data["cloud_event"].(CloudEvent_struct) );
+		if( encode_error == nil ){
+			return_report = error_report.New( 0, map[string]interface{}{ "json": encoded }, nil );
+		} else{
+			return_report = error_report.New( ERROR_CODE_INVALID_CLOUD_EVENT, map[string]interface{}{ "message": encode_error.Error(), "error": encode_error }, nil );
+		}
+	}
+	//Return
+	return return_report;
+}
+
+/**
+* @fn UnmarshalCloudEventJSON
+* @brief Decodes a structured-mode JSON CloudEvent into an event.
+* @param encoded []byte [in] The JSON.
+* @return ( return_report error_report.ErrorReport_struct ) 
+* @retval 0 Success
+* @retval 1 Not Supported
+* @retval >1 Error
+*/
+
+// UnmarshalCloudEventJSON decodes and validates a structured-mode JSON CloudEvent into an event, the "event" datum; see `NewEventFromCloudEvent`.
+func UnmarshalCloudEventJSON( encoded []byte ) ( return_report error_report.ErrorReport_struct ){
+	//Variables
+	var cloud_event CloudEvent_struct;
+	var decode_error error;
+	//Parametres
+	//Function
+	decode_error = json.Unmarshal( encoded, &cloud_event );
+	if( decode_error == nil ){
+		return_report = NewEventFromCloudEvent( cloud_event );
+	} else{
+		return_report = error_report.New( ERROR_CODE_INVALID_CLOUD_EVENT, map[string]interface{}{ "message": fmt.Sprintf( "Invalid structured-mode JSON CloudEvent: %v", decode_error ), "error": decode_error }, nil );
+	}
+	//Return
+	return return_report;
+}
+
+/**
+* @fn WriteCloudEventHTTP
+* @brief Maps the event to a binary-mode HTTP message: sets the headers and returns the body.
+* @param event Event_struct [in] The event; see `ToCloudEvent`.
+* @param header http.Header [out] Receives a "Ce-" header per attribute, and the Content-Type.
+* @return ( return_report error_report.ErrorReport_struct ) 
+* @retval 0 Success
+* @retval 1 Not Supported
+* @retval >1 Error
+*/
+
+// WriteCloudEventHTTP maps the event to a binary-mode HTTP message: sets a "Ce-" header per attribute, plus the Content-Type, and returns the body as the "body" datum.
+func WriteCloudEventHTTP( event Event_struct, header http.Header ) ( return_report error_report.ErrorReport_struct ){
+	//Variables
+	var cloud_event CloudEvent_struct;
+	var name string;
+	var value string;
+	//Parametres
+	//Function
+	return_report = event.ToCloudEvent();
+	if( return_report.IsError() == true ){
+		return return_report;
+	}
+	cloud_event = return_report.Data["cloud_event"].(CloudEvent_struct);
+	header.Set( CLOUD_EVENTS_HTTP_HEADER_PREFIX + "Specversion", cloud_event.Spec_version );
+	header.Set( CLOUD_EVENTS_HTTP_HEADER_PREFIX + "Id", encodeCloudEventsHeaderValue( cloud_event.ID ) );
+	header.Set( CLOUD_EVENTS_HTTP_HEADER_PREFIX + "Source", encodeCloudEventsHeaderValue( cloud_event.Source ) );
+	header.Set( CLOUD_EVENTS_HTTP_HEADER_PREFIX + "Type", encodeCloudEventsHeaderValue( cloud_event.Type ) );
+	if( cloud_event.Data_schema != "" ){
+		header.Set( CLOUD_EVENTS_HTTP_HEADER_PREFIX + "Dataschema", encodeCloudEventsHeaderValue( cloud_event.Data_schema ) );
+	}
+	if( cloud_event.Subject != "" ){
+		header.Set( CLOUD_EVENTS_HTTP_HEADER_PREFIX + "Subject", encodeCloudEventsHeaderValue( cloud_event.Subject ) );
+	}
+	if( cloud_event.Time.IsZero() == false ){
+		header.Set( CLOUD_EVENTS_HTTP_HEADER_PREFIX + "Time", cloud_event.Time.Format( time.RFC3339Nano ) );
+	}
+	for name, value = range cloud_event.Extensions {
+		header.Set( CLOUD_EVENTS_HTTP_HEADER_PREFIX + name, encodeCloudEventsHeaderValue( value ) );
+	}
+	if( cloud_event.Data_content_type != "" ){
+		header.Set( "Content-Type", cloud_event.Data_content_type );
+	}
+	return_report = error_report.New( 0, map[string]interface{}{ "body": cloud_event.Data }, nil );
+	//Return
+	return return_report;
+}
+
+/**
+* @fn ReadCloudEventHTTP
+* @brief Reads an event from an HTTP message in either binary or structured JSON mode.
+* @param header http.Header [in] The message's headers.
+* @param body []byte [in] The message's body.
+* @return ( return_report error_report.ErrorReport_struct ) 
+* @retval 0 Success
+* @retval 1 Not Supported
+* @retval >1 Error
+*/
+
+// ReadCloudEventHTTP reads an event, the "event" datum, from an HTTP message: structured mode if the Content-Type is `CLOUD_EVENTS_JSON_CONTENT_TYPE`, binary mode otherwise.
+func ReadCloudEventHTTP( header http.Header, body []byte ) ( return_report error_report.ErrorReport_struct ){
+	//Variables
+	var cloud_event CloudEvent_struct;
+	var media_type string;
+	var key string;
+	var values []string;
+	var value string;
+	var decode_error error;
+	//Parametres
+	//Function
+	media_type, _, _ = mime.ParseMediaType( header.Get( "Content-Type" ) );
+	if( media_type == CLOUD_EVENTS_JSON_CONTENT_TYPE ){
+		return UnmarshalCloudEventJSON( body );
+	}
+	for key, values = range header {
+		if( len(values) > 0 && len(key) > len(CLOUD_EVENTS_HTTP_HEADER_PREFIX) && strings.EqualFold( key[:len(CLOUD_EVENTS_HTTP_HEADER_PREFIX)], CLOUD_EVENTS_HTTP_HEADER_PREFIX ) == true ){
+			value, decode_error = url.PathUnescape( values[0] );
+			if( decode_error == nil ){
+				decode_error = cloud_event.setAttribute( strings.ToLower( key[len(CLOUD_EVENTS_HTTP_HEADER_PREFIX):] ), value );
+			}
+			if( decode_error != nil ){
+				return error_report.New( ERROR_CODE_INVALID_CLOUD_EVENT, map[string]interface{}{ "message": fmt.Sprintf( "Invalid header %s: %v", key, decode_error ), "attribute": strings.ToLower( key[len(CLOUD_EVENTS_HTTP_HEADER_PREFIX):] ), "error": decode_error }, nil );
+			}
+		}
+	}
+	cloud_event.Data_content_type = header.Get( "Content-Type" );
+	if( len(body) > 0 ){
+		cloud_event.Data = body;
+	}
+	return_report = NewEventFromCloudEvent( cloud_event );
+	//Return
+	return return_report;
+}
+
+//# Private Functions
+/**
+* @fn isCloudEventsAttributeName
+* @brief Returns whether the name consists of only lower-case letters and digits, as the specification requires.
+* @param name string [in] The attribute name.
+* @return bool
+*/
+
+// isCloudEventsAttributeName returns whether the name consists of only lower-case letters and digits, as the specification requires.
+func isCloudEventsAttributeName( name string ) bool{
+	//Variables
+	var _return bool = ( name != "" );
+	var i int;
+	//Parametres
+	//Function
+	for i = 0; i < len(name) && _return == true; i++ {
+		_return = ( ( name[i] >= 'a' && name[i] <= 'z' ) || ( name[i] >= '0' && name[i] <= '9' ) );
+	}
+	//Return
+	return _return;
+}
+
+/**
+* @fn isCloudEventsContextAttribute
+* @brief Returns whether the name is that of a context attribute defined by the specification.
+* @param name string [in] The attribute name.
+* @return bool
+*/
+
+// isCloudEventsContextAttribute returns whether the name is that of a context attribute defined by the specification, or of the structured-mode data members.
+func isCloudEventsContextAttribute( name string ) bool{
+	//Variables
+	var _return bool;
+	var i int;
+	//Parametres
+	//Function
+	for i = 0; i < len(cloud_events_context_attributes_slice) && _return == false; i++ {
+		_return = ( cloud_events_context_attributes_slice[i] == name );
+	}
+	//Return
+	return _return;
+}
+
+/**
+* @fn isJSONContentType
+* @brief Returns whether the media type is JSON; an empty one is taken to be JSON, as in structured mode.
+* @param content_type string [in] The media type.
+* @return bool
+*/
+
+// isJSONContentType returns whether the media type is JSON; an empty one is taken to be JSON, as in structured mode.
+func isJSONContentType( content_type string ) bool{
+	//Variables
+	var media_type string;
+	//Parametres
+	//Function
+	media_type, _, _ = mime.ParseMediaType( content_type );
+	//Return
+	return ( content_type == "" || media_type == "application/json" || media_type == "text/json" || strings.HasSuffix( media_type, "+json" ) == true );
+}
+
+/**
+* @fn setExtension
+* @brief Sets an extension attribute, creating the map if needed.
+* @param extensions map[string]string [in] The extensions; may be nil.
+* @param name string [in] The attribute name.
+* @param value string [in] The value.
+* @return map[string]string
+*/
+
+// setExtension sets an extension attribute, creating the map if needed.
+func setExtension( extensions map[string]string, name string, value string ) map[string]string{
+	//Variables
+	//Parametres
+	//Function
+	if( extensions == nil ){
+		extensions = map[string]string{};
+	}
+	extensions[name] = value;
+	//Return
+	return extensions;
+}
+
+/**
+* @fn encodeCloudEventsHeaderValue
+* @brief Percent-encodes the characters the HTTP binding requires: non-printable and non-ASCII bytes, space, double quote, and percent.
+* @param value string [in] The attribute value.
+* @return string
+*/
+
+// encodeCloudEventsHeaderValue percent-encodes the characters the HTTP binding requires: non-printable and non-ASCII bytes, space, double quote, and percent.
+func encodeCloudEventsHeaderValue( value string ) string{
+	//Variables
+	var builder strings.Builder;
+	var i int;
+	//Parametres
+	//Function
+	for i = 0; i < len(value); i++ {
+		if( value[i] <= ' ' || value[i] >= 0x7f || value[i] == '"' || value[i] == '%' ){
+			fmt.Fprintf( &builder, "%%%02X", value[i] );
+		} else{
+			builder.WriteByte( value[i] );
+		}
+	}
+	//Return
+	return builder.String();
+}
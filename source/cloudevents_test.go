@@ -0,0 +1,140 @@
+/**
+* @file cloudevents_test.go
+* @brief Contains test functions for `cloudevents.go`.
+* @author Anadian
+* @copyright 	Copyright 2019 Canosw
+	Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+software and associated documentation files (the "Software"), to deal in the Software 
+without restriction, including without limitation the rights to use, copy, modify, 
+merge, publish, distribute, sublicense, and/or sell copies of the Software, and to 
+permit persons to whom the Software is furnished to do so, subject to the following 
+conditions:
+	The above copyright notice and this permission notice shall be included in all copies 
+or substantial portions of the Software.
+	THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A 
+PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT 
+HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF 
+CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE 
+OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+// cloudevents_test contains test functions for `cloudevents.go`.
+package event_dispatcher;
+
+//# Dependencies
+import(
+	//## Internal
+	//## Standard
+	"testing"
+	"log"
+	"net/http"
+	"time"
+	//## External
+	error_report "github.com/Anadian/error_report/source"
+);
+
+//# Exported Functions
+/**
+* @fn TestCloudEvents
+* @brief Tests CloudEvents validation and round-tripping events through structured JSON and binary HTTP modes.
+* @param t *testing.T [in] Go stdlib testing object.
+*/
+
+// TestCloudEvents tests CloudEvents validation and round-tripping events through structured JSON and binary HTTP modes.
+func TestCloudEvents( t *testing.T ){
+	//Variables
+	var event Event_struct;
+	var decoded Event_struct;
+	var function_return error_report.ErrorReport_struct;
+	var header http.Header = http.Header{};
+	var invalid_cloud_events map[string]CloudEvent_struct;
+	var attribute string;
+	var cloud_event CloudEvent_struct;
+	var valid CloudEvent_struct = CloudEvent_struct{ Spec_version: "1.0", ID: "1", Source: "/checkout", Type: "order:placed" };
+	var i int;
+	var check func( mode string, decoded Event_struct );
+	//Parametres
+	//Function
+	///Validation.
+	invalid_cloud_events = map[string]CloudEvent_struct{
+		"specversion": { Spec_version: "0.3", ID: "1", Source: "/checkout", Type: "order:placed" },
+		"id": { Spec_version: "1.0", Source: "/checkout", Type: "order:placed" },
+		"source": { Spec_version: "1.0", ID: "1", Type: "order:placed" },
+		"type": { Spec_version: "1.0", ID: "1", Source: "/checkout" },
+		"dataschema": { Spec_version: "1.0", ID: "1", Source: "/checkout", Type: "order:placed", Data_schema: "relative/schema" },
+		"Tenant": { Spec_version: "1.0", ID: "1", Source: "/checkout", Type: "order:placed", Extensions: map[string]string{ "Tenant": "acme" } },
+		"time": { Spec_version: "1.0", ID: "1", Source: "/checkout", Type: "order:placed", Extensions: map[string]string{ "time": "now" } },
+	};
+	for attribute, cloud_event = range invalid_cloud_events {
+		function_return = cloud_event.Validate();
+		if( function_return.CodeEqual( ERROR_CODE_INVALID_CLOUD_EVENT ) == false || function_return.Data["attribute"] != attribute ){
+			t.Fail();
+			log.Printf("Failure: An invalid %s gave: %v\n", attribute, function_return);
+		}
+	}
+	if( valid.Validate().NoError() == true ){
+		log.Printf("Success: Validate checked the attributes.\n");
+	} else{
+		t.Fail();
+		log.Printf("Failure: A valid CloudEvent gave: %v\n", valid.Validate());
+	}
+	event = NewEvent( "order:placed", map[string]interface{}{ "order_id": "order-1", "total": 42.5 } ).Data["event"].(Event_struct);
+	if( event.ToCloudEvent().Data["attribute"] == "source" ){
+		log.Printf("Success: An event without a source isn't a valid CloudEvent.\n");
+	} else{
+		t.Fail();
+		log.Printf("Failure: ToCloudEvent returned: %v\n", event.ToCloudEvent());
+	}
+	event = event.WithSource( "/checkout" ).WithCausationID( "cause-1" ).WithSchemaVersion( "2" ).WithHeader( "tenant", "acme corp" ).WithHeader( "subject", "order-1" );
+	check = func( mode string, decoded Event_struct ){
+		var creation_time time.Time = event.data["creation_time"].(time.Time);
+		if( decoded.ID() == event.ID() && decoded.Name() == event.Name() && decoded.Source() == "/checkout" && decoded.CorrelationID() == event.ID() && decoded.CausationID() == "cause-1" && decoded.SchemaVersion() == "2" && decoded.Headers()["tenant"] == "acme corp" && decoded.Headers()["subject"] == "order-1" && decoded.data["order_id"] == "order-1" && decoded.data["total"] == 42.5 && decoded.data["creation_time"].(time.Time).Equal( creation_time ) == true ){
+			log.Printf("Success: The event round-tripped through %s mode.\n", mode);
+		} else{
+			t.Fail();
+			log.Printf("Failure: %s mode turned %v into %v\n", mode, event, decoded);
+		}
+	};
+	///Structured mode.
+	function_return = MarshalCloudEventJSON( event );
+	if( function_return.IsError() == true ){
+		t.Fail();
+		log.Printf("Failure: MarshalCloudEventJSON returned: %v\n", function_return);
+		return;
+	}
+	function_return = UnmarshalCloudEventJSON( function_return.Data["json"].([]byte) );
+	if( function_return.NoError() == true ){
+		check( "structured", function_return.Data["event"].(Event_struct) );
+	} else{
+		t.Fail();
+		log.Printf("Failure: UnmarshalCloudEventJSON returned: %v\n", function_return);
+	}
+	///Binary mode.
+	function_return = WriteCloudEventHTTP( event, header );
+	if( header.Get( "Ce-Tenant" ) != "acme%20corp" || header.Get( "Content-Type" ) != "application/json" ){
+		t.Fail();
+		log.Printf("Failure: WriteCloudEventHTTP wrote: %v\n", header);
+	}
+	function_return = ReadCloudEventHTTP( header, function_return.Data["body"].([]byte) );
+	if( function_return.NoError() == true ){
+		check( "binary", function_return.Data["event"].(Event_struct) );
+	} else{
+		t.Fail();
+		log.Printf("Failure: ReadCloudEventHTTP returned: %v\n", function_return);
+	}
+	///A structured-mode event from elsewhere, with text data, read over HTTP.
+	header = http.Header{ "Content-Type": { CLOUD_EVENTS_JSON_CONTENT_TYPE } };
+	function_return = ReadCloudEventHTTP( header, []byte(`{"specversion":"1.0","id":"A234-1234-1234","source":"https://github.com/cloudevents/spec/pull","type":"com.github.pull_request.opened","time":"2018-04-05T17:31:00Z","comexampleextension1":"value","comexampleothervalue":5,"datacontenttype":"text/xml","data":"<much wow=\"xml\"/>"}`) );
+	if( function_return.NoError() == true ){
+		decoded = function_return.Data["event"].(Event_struct);
+		i = len(decoded.Headers());
+	}
+	if( function_return.NoError() == true && decoded.Name() == "com.github.pull_request.opened" && string(decoded.data["data"].([]byte)) == `<much wow="xml"/>` && i == 2 && decoded.Headers()["comexampleothervalue"] == "5" ){
+		log.Printf("Success: Read the specification's example.\n");
+	} else{
+		t.Fail();
+		log.Printf("Failure: The specification's example gave: %v\n", function_return);
+	}
+	//Return
+}
@@ -0,0 +1,390 @@
+/**
+* @file codec.go
+* @brief Serialisation of events: the Codec interface, its JSON, gob, MessagePack, and CBOR implementations, and the payload type registry.
+* @author Anadian
+* @copyright 	Copyright 2019 Canosw
+	Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+software and associated documentation files (the "Software"), to deal in the Software 
+without restriction, including without limitation the rights to use, copy, modify, 
+merge, publish, distribute, sublicense, and/or sell copies of the Software, and to 
+permit persons to whom the Software is furnished to do so, subject to the following 
+conditions:
+	The above copyright notice and this permission notice shall be included in all copies 
+or substantial portions of the Software.
+	THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A 
+PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT 
+HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF 
+CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE 
+OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+package event_dispatcher;
+
+//# Dependencies
+import(
+	//## Internal
+	//## Standard
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+	//## External
+	error_report "github.com/Anadian/error_report/source"
+	msgpack "github.com/vmihailenco/msgpack/v5"
+	cbor "github.com/fxamacker/cbor/v2"
+);
+
+//# Constants
+const(
+	//## Exported Constants
+	//### Errors
+	ERROR_CODE_UNREGISTERED_PAYLOAD_TYPE int64 = 34;
+	ERROR_CODE_PAYLOAD_TYPE_CONFLICT int64 = 35;
+	ERROR_CODE_CODEC_ERROR int64 = 36;
+	//## Private Constants
+	payload_type_nil string = "nil";
+);
+
+//# Types
+//## Interfaces
+// Codec_interface serialises events to bytes and back. Every codec round-trips an event's name, envelope metadata, priority, and data, including the time stamps; not its context or retry state.
+type Codec_interface interface{
+	// Name returns the codec's name, used by `GetCodec`.
+	Name() string
+	// Encode serialises the event, the "encoded" datum.
+	Encode( event Event_struct ) error_report.ErrorReport_struct
+	// Decode deserialises an event, the "event" datum.
+	Decode( encoded []byte ) error_report.ErrorReport_struct
+}
+//## Structs
+// event_record_struct is the serialised form of an event.
+type event_record_struct struct{
+	ID string `json:"id" msgpack:"id" cbor:"id"`
+	Name string `json:"name" msgpack:"name" cbor:"name"`
+	Source string `json:"source,omitempty" msgpack:"source,omitempty" cbor:"source,omitempty"`
+	Correlation_id string `json:"correlation_id,omitempty" msgpack:"correlation_id,omitempty" cbor:"correlation_id,omitempty"`
+	Causation_id string `json:"causation_id,omitempty" msgpack:"causation_id,omitempty" cbor:"causation_id,omitempty"`
+	Schema_version string `json:"schema_version,omitempty" msgpack:"schema_version,omitempty" cbor:"schema_version,omitempty"`
+	Headers map[string]string `json:"headers,omitempty" msgpack:"headers,omitempty" cbor:"headers,omitempty"`
+	Priority int64 `json:"priority,omitempty" msgpack:"priority,omitempty" cbor:"priority,omitempty"`
+	Data map[string]event_record_value_struct `json:"data" msgpack:"data" cbor:"data"`
+}
+// event_record_value_struct is a serialised data value, tagged with its registered type name so it can be decoded to the same type.
+type event_record_value_struct struct{
+	Type string `json:"type,omitempty" msgpack:"type,omitempty" cbor:"type,omitempty"`
+	//A `json.RawMessage` so the JSON codec embeds the value as is; the other codecs see plain bytes.
+	Value json.RawMessage `json:"value,omitempty" msgpack:"value,omitempty" cbor:"value,omitempty"`
+}
+// codec_struct implements `Codec_interface` on top of a marshal/unmarshal pair.
+type codec_struct struct{
+	name string
+	marshal func( value interface{} ) ( []byte, error )
+	unmarshal func( encoded []byte, value interface{} ) error
+	//Whether values of unregistered types can be decoded into an `interface{}`; gob can't.
+	generic bool
+}
+//### Methods
+/**
+* @fn Name
+* @brief Implements `Codec_interface`.
+* @struct codec codec_struct
+* @return string
+*/
+
+// Name implements `Codec_interface`.
+func (codec codec_struct) Name() string{
+	//Variables
+	//Parametres
+	//Function
+	//Return
+	return codec.name;
+}
+
+/**
+* @fn Encode
+* @brief Implements `Codec_interface`.
+* @struct codec codec_struct
+* @param event Event_struct [in] The event to serialise.
+* @return ( return_report error_report.ErrorReport_struct ) 
+* @retval 0 Success
+* @retval 1 Not Supported
+* @retval >1 Error
+*/
+
+// Encode implements `Codec_interface`. Each data value is tagged with the name its type was registered under by `RegisterPayloadType`; with every codec but gob, values of other types are still encoded, but decode to the codec's generic representation, such as `map[string]interface{}` for structs.
+func (codec codec_struct) Encode( event Event_struct ) ( return_report error_report.ErrorReport_struct ){
+	//Variables
+	var record event_record_struct;
+	var key string;
+	var value interface{};
+	var record_value event_record_value_struct;
+	var ok bool;
+	var encoded []byte;
+	var encode_error error;
+	//Parametres
+	//Function
+	record = event_record_struct{ ID: event.id, Name: event.name, Source: event.source, Correlation_id: event.correlation_id, Causation_id: event.causation_id, Schema_version: event.schema_version, Headers: event.headers, Priority: event.priority, Data: make(map[string]event_record_value_struct, len(event.data)) };
+	for key, value = range event.data {
+		record_value = event_record_value_struct{};
+		if( value == nil ){
+			record_value.Type = payload_type_nil;
+		} else{
+			payload_types_rwmutex.RLock();
+			record_value.Type, ok = payload_type_names_map[reflect.TypeOf( value )];
+			payload_types_rwmutex.RUnlock();
+			if( ok == false && codec.generic == false ){
+				return error_report.New( ERROR_CODE_UNREGISTERED_PAYLOAD_TYPE, map[string]interface{}{ "message": fmt.Sprintf( "The %s codec can't encode %T values unless their type is registered with RegisterPayloadType.", codec.name, value ), "key": key, "codec": codec.name }, nil );
+			}
+			record_value.Value, encode_error = codec.marshal( value );
+			if( encode_error != nil ){
+				return error_report.New( ERROR_CODE_CODEC_ERROR, map[string]interface{}{ "message": fmt.Sprintf( "Couldn't encode %q: %v", key, encode_error ), "error": encode_error, "key": key, "codec": codec.name }, nil );
+			}
+		}
+		record.Data[key] = record_value;
+	}
+	encoded, encode_error = codec.marshal( record );
+	if( encode_error == nil ){
+		return_report = error_report.New( 0, map[string]interface{}{ "encoded": encoded }, nil );
+	} else{
+		return_report = error_report.New( ERROR_CODE_CODEC_ERROR, map[string]interface{}{ "message": encode_error.Error(), "error": encode_error, "codec": codec.name }, nil );
+	}
+	//Return
+	return return_report;
+}
+
+/**
+* @fn Decode
+* @brief Implements `Codec_interface`.
+* @struct codec codec_struct
+* @param encoded []byte [in] An event serialised by the same codec.
+* @return ( return_report error_report.ErrorReport_struct ) 
+* @retval 0 Success
+* @retval 1 Not Supported
+* @retval >1 Error
+*/
+
+// Decode implements `Codec_interface`. Values are decoded to the type registered under their tag, failing with ERROR_CODE_UNREGISTERED_PAYLOAD_TYPE if this process hasn't registered it.
+func (codec codec_struct) Decode( encoded []byte ) ( return_report error_report.ErrorReport_struct ){
+	//Variables
+	var record event_record_struct;
+	var event Event_struct;
+	var key string;
+	var record_value event_record_value_struct;
+	var value_type reflect.Type;
+	var value reflect.Value;
+	var generic_value interface{};
+	var ok bool;
+	var decode_error error;
+	//Parametres
+	//Function
+	decode_error = codec.unmarshal( encoded, &record );
+	if( decode_error != nil ){
+		return error_report.New( ERROR_CODE_CODEC_ERROR, map[string]interface{}{ "message": decode_error.Error(), "error": decode_error, "codec": codec.name }, nil );
+	}
+	event = Event_struct{ id: record.ID, name: record.Name, source: record.Source, correlation_id: record.Correlation_id, causation_id: record.Causation_id, schema_version: record.Schema_version, headers: record.Headers, priority: record.Priority, data: make(map[string]interface{}, len(record.Data)) };
+	for key, record_value = range record.Data {
+		switch record_value.Type {
+			case payload_type_nil:
+				event.data[key] = nil;
+			case "":
+				generic_value = nil;
+				decode_error = codec.unmarshal( record_value.Value, &generic_value );
+				event.data[key] = generic_value;
+			default:
+				payload_types_rwmutex.RLock();
+				value_type, ok = payload_types_map[record_value.Type];
+				payload_types_rwmutex.RUnlock();
+				if( ok == false ){
+					return error_report.New( ERROR_CODE_UNREGISTERED_PAYLOAD_TYPE, map[string]interface{}{ "message": fmt.Sprintf( "%q has a value of type %q, which hasn't been registered with RegisterPayloadType.", key, record_value.Type ), "key": key, "type": record_value.Type, "codec": codec.name }, nil );
+				}
+				value = reflect.New( value_type );
+				decode_error = codec.unmarshal( record_value.Value, value.Interface() );
+				event.data[key] = value.Elem().Interface();
+		}
+		if( decode_error != nil ){
+			return error_report.New( ERROR_CODE_CODEC_ERROR, map[string]interface{}{ "message": fmt.Sprintf( "Couldn't decode %q: %v", key, decode_error ), "error": decode_error, "key": key, "codec": codec.name }, nil );
+		}
+	}
+	return_report = error_report.New( 0, map[string]interface{}{ "event": event }, nil );
+	//Return
+	return return_report;
+}
+
+//# Global Variables
+var(
+	//## Exported Variables
+	JSONCodec Codec_interface = codec_struct{ name: "json", marshal: json.Marshal, unmarshal: json.Unmarshal, generic: true };
+	GobCodec Codec_interface = codec_struct{ name: "gob", marshal: gobMarshal, unmarshal: gobUnmarshal, generic: false };
+	MessagePackCodec Codec_interface = codec_struct{ name: "msgpack", marshal: msgpack.Marshal, unmarshal: msgpack.Unmarshal, generic: true };
+	CBORCodec Codec_interface = codec_struct{ name: "cbor", marshal: cbor_encoding_mode.Marshal, unmarshal: cbor_decoding_mode.Unmarshal, generic: true };
+	//## Private Variables
+	cbor_encoding_mode, _ = cbor.EncOptions{ Time: cbor.TimeRFC3339Nano, Sort: cbor.SortCanonical }.EncMode();
+	cbor_decoding_mode, _ = cbor.DecOptions{ DefaultMapType: reflect.TypeOf( map[string]interface{}{} ) }.DecMode();
+	codecs_rwmutex sync.RWMutex;
+	codecs_map map[string]Codec_interface = map[string]Codec_interface{};
+	payload_types_rwmutex sync.RWMutex;
+	payload_types_map map[string]reflect.Type = map[string]reflect.Type{};
+	payload_type_names_map map[reflect.Type]string = map[reflect.Type]string{};
+);
+
+//# Exported Functions
+/**
+* @fn RegisterPayloadType
+* @brief Registers the type of `example` under `name`, so codecs can decode event data values of that type back to it.
+* @param name string [in] A name unique to the type and stable across processes, such as "orders.OrderPlaced".
+* @param example interface{} [in] A value of the type, such as `OrderPlaced{}`.
+* @return ( return_report error_report.ErrorReport_struct ) 
+* @retval 0 Success
+* @retval 1 Not Supported
+* @retval >1 Error
+*/
+
+// RegisterPayloadType registers the type of `example` under `name`, so codecs can decode event data values of that type back to it. Every process decoding the events must register the same names. Registering the same type under the same name again is harmless; reusing either with something else fails with ERROR_CODE_PAYLOAD_TYPE_CONFLICT. Go's basic types, []byte, time.Time, time.Duration, map[string]interface{}, and []interface{} are registered already.
+func RegisterPayloadType( name string, example interface{} ) ( return_report error_report.ErrorReport_struct ){
+	//Variables
+	var payload_type reflect.Type = reflect.TypeOf( example );
+	var existing_type reflect.Type;
+	var existing_name string;
+	var type_ok bool;
+	var name_ok bool;
+	//Parametres
+	if( name == "" || name == payload_type_nil || payload_type == nil ){
+		return error_report.New( ERROR_CODE_PAYLOAD_TYPE_CONFLICT, map[string]interface{}{ "message": "A payload type needs a non-reserved name and a non-nil example.", "name": name }, nil );
+	}
+	//Function
+	payload_types_rwmutex.Lock();
+	existing_type, type_ok = payload_types_map[name];
+	existing_name, name_ok = payload_type_names_map[payload_type];
+	if( ( type_ok == true && existing_type != payload_type ) || ( name_ok == true && existing_name != name ) ){
+		return_report = error_report.New( ERROR_CODE_PAYLOAD_TYPE_CONFLICT, map[string]interface{}{ "message": fmt.Sprintf( "Can't register %v as %q: %q is %v and %v is %q.", payload_type, name, name, existing_type, payload_type, existing_name ), "name": name }, nil );
+	} else{
+		payload_types_map[name] = payload_type;
+		payload_type_names_map[payload_type] = name;
+		return_report = error_report.New( 0, map[string]interface{}{ "name": name }, nil );
+	}
+	payload_types_rwmutex.Unlock();
+	//Return
+	return return_report;
+}
+
+/**
+* @fn RegisterCodec
+* @brief Makes a codec available through `GetCodec` by its name.
+* @param codec Codec_interface [in] The codec.
+* @return ( return_report error_report.ErrorReport_struct ) 
+* @retval 0 Success
+* @retval 1 Not Supported
+* @retval >1 Error
+*/
+
+// RegisterCodec makes a codec available through `GetCodec` by its name, replacing any registered under the same name; the built-in codecs are registered already.
+func RegisterCodec( codec Codec_interface ) ( return_report error_report.ErrorReport_struct ){
+	//Variables
+	//Parametres
+	//Function
+	codecs_rwmutex.Lock();
+	codecs_map[codec.Name()] = codec;
+	codecs_rwmutex.Unlock();
+	return_report = error_report.New( 0, map[string]interface{}{ "name": codec.Name() }, nil );
+	//Return
+	return return_report;
+}
+
+/**
+* @fn GetCodec
+* @brief Returns the codec registered under the given name: "json", "gob", "msgpack", "cbor", or one added by `RegisterCodec`.
+* @param name string [in] The codec's name.
+* @return ( return_report error_report.ErrorReport_struct ) 
+* @retval 0 Success
+* @retval 1 Not Supported
+* @retval >1 Error
+*/
+
+// GetCodec returns the codec registered under the given name, the "codec" datum: "json", "gob", "msgpack", "cbor", or one added by `RegisterCodec`.
+func GetCodec( name string ) ( return_report error_report.ErrorReport_struct ){
+	//Variables
+	var codec Codec_interface;
+	var ok bool;
+	//Parametres
+	//Function
+	codecs_rwmutex.RLock();
+	codec, ok = codecs_map[name];
+	codecs_rwmutex.RUnlock();
+	if( ok == true ){
+		return_report = error_report.New( 0, map[string]interface{}{ "codec": codec }, nil );
+	} else{
+		return_report = error_report.New( ERROR_CODE_CODEC_ERROR, map[string]interface{}{ "message": fmt.Sprintf( "No codec is registered as %q.", name ), "name": name }, nil );
+	}
+	//Return
+	return return_report;
+}
+
+//# Private Functions
+/**
+* @fn init
+* @brief Registers the built-in codecs and payload types.
+*/
+
+// init registers the built-in codecs and payload types.
+func init(){
+	//Variables
+	var name string;
+	var example interface{};
+	var builtin_payload_types_map map[string]interface{} = map[string]interface{}{
+		"bool": false, "string": "", "bytes": []byte{},
+		"int": int(0), "int8": int8(0), "int16": int16(0), "int32": int32(0), "int64": int64(0),
+		"uint": uint(0), "uint8": uint8(0), "uint16": uint16(0), "uint32": uint32(0), "uint64": uint64(0),
+		"float32": float32(0), "float64": float64(0),
+		"time": time.Time{}, "duration": time.Duration(0),
+		"map": map[string]interface{}{}, "slice": []interface{}{},
+	};
+	//Parametres
+	//Function
+	for name, example = range builtin_payload_types_map {
+		RegisterPayloadType( name, example );
+	}
+	RegisterCodec( JSONCodec );
+	RegisterCodec( GobCodec );
+	RegisterCodec( MessagePackCodec );
+	RegisterCodec( CBORCodec );
+	//Return
+}
+
+/**
+* @fn gobMarshal
+* @brief Encodes the value with encoding/gob.
+* @param value interface{} [in] The value.
+* @return ( []byte, error )
+*/
+
+// gobMarshal encodes the value with encoding/gob.
+func gobMarshal( value interface{} ) ( []byte, error ){
+	//Variables
+	var buffer bytes.Buffer;
+	var encode_error error;
+	//Parametres
+	//Function
+	encode_error = gob.NewEncoder( &buffer ).Encode( value );
+	//Return
+	return buffer.Bytes(), encode_error;
+}
+
+/**
+* @fn gobUnmarshal
+* @brief Decodes a value encoded by `gobMarshal`.
+* @param encoded []byte [in] The encoded value.
+* @param value interface{} [out] A pointer to decode into.
+* @return error
+*/
+
+// gobUnmarshal decodes a value encoded by `gobMarshal`.
+func gobUnmarshal( encoded []byte, value interface{} ) error{
+	//Variables
+	//Parametres
+	//Function
+	//Return
+	return gob.NewDecoder( bytes.NewReader( encoded ) ).Decode( value );
+}
@@ -0,0 +1,124 @@
+/**
+* @file codec_test.go
+* @brief Contains test functions for `codec.go`.
+* @author Anadian
+* @copyright 	Copyright 2019 Canosw
+	Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+software and associated documentation files (the "Software"), to deal in the Software 
+without restriction, including without limitation the rights to use, copy, modify, 
+merge, publish, distribute, sublicense, and/or sell copies of the Software, and to 
+permit persons to whom the Software is furnished to do so, subject to the following 
+conditions:
+	The above copyright notice and this permission notice shall be included in all copies 
+or substantial portions of the Software.
+	THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A 
+PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT 
+HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF 
+CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE 
+OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+// codec_test contains test functions for `codec.go`.
+package event_dispatcher;
+
+//# Dependencies
+import(
+	//## Internal
+	//## Standard
+	"testing"
+	"log"
+	"reflect"
+	"time"
+	//## External
+	error_report "github.com/Anadian/error_report/source"
+);
+
+//# Types
+type codec_test_order_struct struct{
+	ID string
+	Total float64
+	Items []string
+}
+type codec_test_unregistered_struct struct{
+	Note string
+}
+
+//# Exported Functions
+/**
+* @fn TestCodecs
+* @brief Tests round-tripping events through each built-in codec, and the payload type registry.
+* @param t *testing.T [in] Go stdlib testing object.
+*/
+
+// TestCodecs tests round-tripping events through each built-in codec, and the payload type registry.
+func TestCodecs( t *testing.T ){
+	//Variables
+	var event Event_struct;
+	var decoded Event_struct;
+	var function_return error_report.ErrorReport_struct;
+	var codec_name string;
+	var codec Codec_interface;
+	var key string;
+	var round_tripped bool;
+	//Parametres
+	//Function
+	if( RegisterPayloadType( "codec_test.order", codec_test_order_struct{} ).NoError() == true && RegisterPayloadType( "codec_test.order", codec_test_order_struct{} ).NoError() == true && RegisterPayloadType( "string", codec_test_order_struct{} ).CodeEqual( ERROR_CODE_PAYLOAD_TYPE_CONFLICT ) == true && RegisterPayloadType( "codec_test.other", codec_test_order_struct{} ).CodeEqual( ERROR_CODE_PAYLOAD_TYPE_CONFLICT ) == true ){
+		log.Printf("Success: RegisterPayloadType accepted re-registration and refused conflicts.\n");
+	} else{
+		t.Fail();
+		log.Printf("Failure: RegisterPayloadType didn't detect conflicts.\n");
+	}
+	RegisterPayloadType( "codec_test.order_pointer", &codec_test_order_struct{} );
+	event = NewPriorityEvent( "order:placed", map[string]interface{}{
+		"order": codec_test_order_struct{ ID: "order-1", Total: 42.5, Items: []string{ "a", "b" } },
+		"order_pointer": &codec_test_order_struct{ ID: "order-2" },
+		"count": 3,
+		"ratio": 0.25,
+		"flag": true,
+		"raw": []byte{ 1, 2, 3 },
+		"timeout": 5 * time.Second,
+		"missing": nil,
+	}, 7 ).Data["event"].(Event_struct);
+	event = event.WithSource( "/checkout" ).WithCausationID( "cause-1" ).WithSchemaVersion( "2" ).WithHeader( "tenant", "acme" );
+	event = event.withDatum( "submission_time", time.Now() );
+	for _, codec_name = range []string{ "json", "gob", "msgpack", "cbor" } {
+		codec = GetCodec( codec_name ).Data["codec"].(Codec_interface);
+		function_return = codec.Encode( event );
+		if( function_return.NoError() == true ){
+			function_return = codec.Decode( function_return.Data["encoded"].([]byte) );
+		}
+		if( function_return.IsError() == true ){
+			t.Fail();
+			log.Printf("Failure: %s returned: %v\n", codec_name, function_return);
+			continue;
+		}
+		decoded = function_return.Data["event"].(Event_struct);
+		round_tripped = ( decoded.ID() == event.ID() && decoded.Name() == event.Name() && decoded.Priority() == 7 && decoded.Source() == "/checkout" && decoded.CorrelationID() == event.ID() && decoded.CausationID() == "cause-1" && decoded.SchemaVersion() == "2" && reflect.DeepEqual( decoded.Headers(), event.Headers() ) == true && len(decoded.data) == len(event.data) );
+		for key = range event.data {
+			if( key == "creation_time" || key == "submission_time" ){
+				round_tripped = round_tripped && decoded.data[key].(time.Time).Equal( event.data[key].(time.Time) );
+			} else if( reflect.DeepEqual( decoded.data[key], event.data[key] ) == false ){
+				round_tripped = false;
+				log.Printf("%s: %q was %#v, now %#v\n", codec_name, key, event.data[key], decoded.data[key]);
+			}
+		}
+		if( round_tripped == true ){
+			log.Printf("Success: The event round-tripped through %s.\n", codec_name);
+		} else{
+			t.Fail();
+			log.Printf("Failure: %s turned %v into %v\n", codec_name, event, decoded);
+		}
+	}
+	///Unregistered types decode generically, except with gob.
+	event = NewEvent( "note", map[string]interface{}{ "note": codec_test_unregistered_struct{ Note: "hello" } } ).Data["event"].(Event_struct);
+	function_return = JSONCodec.Encode( event );
+	function_return = JSONCodec.Decode( function_return.Data["encoded"].([]byte) );
+	if( function_return.NoError() == true && reflect.DeepEqual( function_return.Data["event"].(Event_struct).data["note"], map[string]interface{}{ "Note": "hello" } ) == true && GobCodec.Encode( event ).CodeEqual( ERROR_CODE_UNREGISTERED_PAYLOAD_TYPE ) == true ){
+		log.Printf("Success: An unregistered type decoded generically with JSON and was refused by gob.\n");
+	} else{
+		t.Fail();
+		log.Printf("Failure: An unregistered type gave: %v\n", function_return);
+	}
+	//Return
+}
@@ -0,0 +1,180 @@
+/**
+* @file context.go
+* @brief Passing a publisher's context.Context through to event listeners.
+* @author Anadian
+* @copyright 	Copyright 2019 Canosw
+	Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+software and associated documentation files (the "Software"), to deal in the Software 
+without restriction, including without limitation the rights to use, copy, modify, 
+merge, publish, distribute, sublicense, and/or sell copies of the Software, and to 
+permit persons to whom the Software is furnished to do so, subject to the following 
+conditions:
+	The above copyright notice and this permission notice shall be included in all copies 
+or substantial portions of the Software.
+	THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A 
+PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT 
+HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF 
+CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE 
+OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+package event_dispatcher;
+
+//# Dependencies
+import(
+	//## Internal
+	//## Standard
+	"context"
+	"time"
+	//## External
+	error_report "github.com/Anadian/error_report/source"
+);
+
+//# Constants
+const(
+	//## Exported Constants
+	//## Private Constants
+);
+
+//# Types
+//## Structs
+// detached_context_struct carries the values of its parent context but is never cancelled and has no deadline, for events handled after their publisher has moved on.
+type detached_context_struct struct{
+	parent context.Context
+}
+//### Methods
+/**
+* @fn Deadline
+* @brief Implements `context.Context`: there is never a deadline.
+* @struct detached_context detached_context_struct
+* @return ( deadline time.Time, ok bool )
+*/
+
+// Deadline implements `context.Context`: there is never a deadline.
+func (detached_context detached_context_struct) Deadline() ( deadline time.Time, ok bool ){
+	//Variables
+	//Parametres
+	//Function
+	//Return
+	return deadline, false;
+}
+
+/**
+* @fn Done
+* @brief Implements `context.Context`: never done.
+* @struct detached_context detached_context_struct
+* @return <-chan struct{} nil
+*/
+
+// Done implements `context.Context`: never done.
+func (detached_context detached_context_struct) Done() <-chan struct{}{
+	//Variables
+	//Parametres
+	//Function
+	//Return
+	return nil;
+}
+
+/**
+* @fn Err
+* @brief Implements `context.Context`: never cancelled.
+* @struct detached_context detached_context_struct
+* @return error nil
+*/
+
+// Err implements `context.Context`: never cancelled.
+func (detached_context detached_context_struct) Err() error{
+	//Variables
+	//Parametres
+	//Function
+	//Return
+	return nil;
+}
+
+/**
+* @fn Value
+* @brief Implements `context.Context`: returns the parent's value for the key.
+* @struct detached_context detached_context_struct
+* @param key interface{} [in] The key.
+* @return interface{}
+*/
+
+// Value implements `context.Context`: returns the parent's value for the key.
+func (detached_context detached_context_struct) Value( key interface{} ) interface{}{
+	//Variables
+	//Parametres
+	//Function
+	//Return
+	return detached_context.parent.Value( key );
+}
+
+/**
+* @fn Context
+* @brief Returns the context the event was published with, or `context.Background()` if there wasn't one.
+* @struct event Event_struct
+* @return context.Context
+*/
+
+// Context returns the context the event was published with, or `context.Background()` if there wasn't one. Within a listener it also carries the event's ID and correlation ID, so events published with it inherit its correlation ID; see `ParentEventIDs`. Events published without it, as by a plain `Publish`, don't. For queued events and asynchronous listeners it keeps the publisher's values but is never cancelled, since the publisher may be long gone by the time they run.
+func (event Event_struct) Context() context.Context{
+	//Variables
+	var _return context.Context = event.ctx;
+	//Parametres
+	//Function
+	if( _return == nil ){
+		_return = context.Background();
+	}
+	//Return
+	return _return;
+}
+
+/**
+* @fn ProcessEventCtx
+* @brief Transmits the given event as `ProcessEvent` does, making `ctx` available to its listeners.
+* @struct event_dispatcher *EventDispatcher_struct
+* @param ctx context.Context [in] Passed to synchronous listeners as is; asynchronous listeners get its values but not its cancellation or deadline.
+* @param event Event_struct [in] The event to be processed.
+* @return ( return_report error_report.ErrorReport_struct ) 
+* @retval 0 Success
+* @retval 1 Not Supported
+* @retval >1 Error
+*/
+
+// ProcessEventCtx transmits the given event as `ProcessEvent` does, making `ctx` available to its listeners through `Event.Context` and the function given to `NewContextEventListener`. Synchronous listeners get `ctx` as is; asynchronous listeners get its values but not its cancellation or deadline.
+func (event_dispatcher *EventDispatcher_struct) ProcessEventCtx( ctx context.Context, event Event_struct ) ( return_report error_report.ErrorReport_struct ){
+	//Variables
+	//Parametres
+	//Function
+	event = inheritEnvelope( ctx, event );
+	event.ctx = ctx;
+	return_report = event_dispatcher.ProcessEvent( event );
+	//Return
+	return return_report;
+}
+
+//# Global Variables
+var(
+	//## Exported Variables
+	//## Private Variables
+);
+
+//# Private Functions
+/**
+* @fn detachContext
+* @brief Returns a context with the values of `ctx` which is never cancelled.
+* @param ctx context.Context [in] The parent; may be nil.
+* @return context.Context nil if `ctx` is nil.
+*/
+
+// detachContext returns a context with the values of `ctx` which is never cancelled; nil if `ctx` is nil.
+func detachContext( ctx context.Context ) context.Context{
+	//Variables
+	var _return context.Context;
+	//Parametres
+	//Function
+	if( ctx != nil ){
+		_return = detached_context_struct{ parent: ctx };
+	}
+	//Return
+	return _return;
+}
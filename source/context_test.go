@@ -0,0 +1,98 @@
+/**
+* @file context_test.go
+* @brief Contains test functions for `context.go`.
+* @author Anadian
+* @copyright 	Copyright 2019 Canosw
+	Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+software and associated documentation files (the "Software"), to deal in the Software 
+without restriction, including without limitation the rights to use, copy, modify, 
+merge, publish, distribute, sublicense, and/or sell copies of the Software, and to 
+permit persons to whom the Software is furnished to do so, subject to the following 
+conditions:
+	The above copyright notice and this permission notice shall be included in all copies 
+or substantial portions of the Software.
+	THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A 
+PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT 
+HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF 
+CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE 
+OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+// context_test contains test functions for `context.go`.
+package event_dispatcher;
+
+//# Dependencies
+import(
+	//## Internal
+	//## Standard
+	"testing"
+	"log"
+	"context"
+	//## External
+	matchkey "github.com/Anadian/matchkey/source"
+);
+
+//# Types
+type context_test_key_struct struct{}
+
+//# Exported Functions
+/**
+* @fn TestContextPropagation
+* @brief Tests that listeners get the publisher's context, and that queued events keep its values but not its cancellation.
+* @param t *testing.T [in] Go stdlib testing object.
+*/
+
+// TestContextPropagation tests that listeners get the publisher's context, and that queued events keep its values but not its cancellation.
+func TestContextPropagation( t *testing.T ){
+	//Variables
+	var event_dispatcher *EventDispatcher_struct;
+	var key matchkey.MatchKey_struct;
+	var event Event_struct;
+	var ctx context.Context;
+	var cancel context.CancelFunc;
+	var trace_ids []interface{};
+	var context_errors []error;
+	var async_channel chan interface{} = make(chan interface{}, 1);
+	//Parametres
+	//Function
+	event_dispatcher = newEventDispatcher( false, true );
+	key, _ = matchkey.New( matchkey.MATCHKEY_TYPE_STRING, "context:test" );
+	event_dispatcher.AddEventListener( NewContextEventListener( key, false, 0, func( ctx context.Context, event Event_struct, args ...interface{} ) error{
+		trace_ids = append(trace_ids, ctx.Value( context_test_key_struct{} ));
+		context_errors = append(context_errors, ctx.Err());
+		return nil;
+	} ).Data["event_listener"].(EventListener_struct) );
+	event_dispatcher.AddEventListener( NewEventListener( key, true, func( event Event_struct, args ...interface{} ){
+		async_channel <- event.Context().Value( context_test_key_struct{} );
+	} ).Data["event_listener"].(EventListener_struct) );
+	event = NewEvent( "context:test", map[string]interface{}{} ).Data["event"].(Event_struct);
+	ctx, cancel = context.WithCancel( context.WithValue( context.Background(), context_test_key_struct{}, "trace-1" ) );
+	///Queued, then cancelled before it's processed.
+	event_dispatcher.PushEventCtx( ctx, event );
+	cancel();
+	event_dispatcher.ProcessEvents();
+	if( len(trace_ids) == 1 && trace_ids[0] == "trace-1" && context_errors[0] == nil && <-async_channel == "trace-1" ){
+		log.Printf("Success: A queued event kept its context's values but not its cancellation.\n");
+	} else{
+		t.Fail();
+		log.Printf("Failure: The listeners got %v and %v\n", trace_ids, context_errors);
+	}
+	///Processed directly with a cancelled context.
+	event_dispatcher.ProcessEventCtx( ctx, event );
+	if( len(trace_ids) == 2 && trace_ids[1] == "trace-1" && context_errors[1] == context.Canceled && <-async_channel == "trace-1" ){
+		log.Printf("Success: A synchronous listener saw the publisher's cancellation.\n");
+	} else{
+		t.Fail();
+		log.Printf("Failure: The listeners got %v and %v\n", trace_ids, context_errors);
+	}
+	///Without a context.
+	event_dispatcher.ProcessEvent( event );
+	if( len(trace_ids) == 3 && trace_ids[2] == nil && context_errors[2] == nil && <-async_channel == nil ){
+		log.Printf("Success: Events published without a context get context.Background().\n");
+	} else{
+		t.Fail();
+		log.Printf("Failure: The listeners got %v and %v\n", trace_ids, context_errors);
+	}
+	//Return
+}
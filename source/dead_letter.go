@@ -0,0 +1,407 @@
+/**
+* @file dead_letter.go
+* @brief Dead-letter queue for events which matched no listener or which every listener failed to handle.
+* @author Anadian
+* @copyright 	Copyright 2019 Canosw
+	Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+software and associated documentation files (the "Software"), to deal in the Software 
+without restriction, including without limitation the rights to use, copy, modify, 
+merge, publish, distribute, sublicense, and/or sell copies of the Software, and to 
+permit persons to whom the Software is furnished to do so, subject to the following 
+conditions:
+	The above copyright notice and this permission notice shall be included in all copies 
+or substantial portions of the Software.
+	THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A 
+PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT 
+HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF 
+CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE 
+OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+package event_dispatcher;
+
+//# Dependencies
+import(
+	//## Internal
+	//## Standard
+	"time"
+	//## External
+	error_report "github.com/Anadian/error_report/source"
+);
+
+//# Constants
+const(
+	//## Exported Constants
+	//### Dead-Letter Reasons
+	DEAD_LETTER_REASON_UNMATCHED uint8 = 1 //No listener matched the event.
+	DEAD_LETTER_REASON_LISTENER_ERROR uint8 = 2 //Every synchronous listener called returned an error, and none of them will be retried; also used when a retry policy gives up without a terminal handler.
+	DEAD_LETTER_REASON_PANIC uint8 = 3 //Every synchronous listener called failed and at least one of them panicked.
+	DEAD_LETTER_REASON_MATCH_ERROR uint8 = 4 //No listener matched the event and matching against at least one listener returned an error.
+	//## Private Constants
+);
+
+//# Types
+//## Structs
+// DeadLetter_struct is an event captured by the dead-letter queue along with why and when it was captured.
+type DeadLetter_struct struct{
+	event Event_struct
+	reason uint8
+	report error_report.ErrorReport_struct
+	time time.Time
+}
+//### Methods
+/**
+* @fn Event
+* @brief Returns the dead-lettered event.
+* @struct dead_letter DeadLetter_struct
+* @return Event_struct
+*/
+
+// Event returns the dead-lettered event.
+func (dead_letter DeadLetter_struct) Event() Event_struct{
+	//Variables
+	//Parametres
+	//Function
+	//Return
+	return dead_letter.event;
+}
+
+/**
+* @fn Reason
+* @brief Returns one of the `DEAD_LETTER_REASON_*` constants.
+* @struct dead_letter DeadLetter_struct
+* @return uint8
+*/
+
+// Reason returns one of the `DEAD_LETTER_REASON_*` constants.
+func (dead_letter DeadLetter_struct) Reason() uint8{
+	//Variables
+	//Parametres
+	//Function
+	//Return
+	return dead_letter.reason;
+}
+
+/**
+* @fn Report
+* @brief Returns the report produced while transmitting the event, including any per-listener errors keyed by listener ID.
+* @struct dead_letter DeadLetter_struct
+* @return error_report.ErrorReport_struct
+*/
+
+// Report returns the report produced while transmitting the event, including any per-listener errors keyed by listener ID.
+func (dead_letter DeadLetter_struct) Report() error_report.ErrorReport_struct{
+	//Variables
+	//Parametres
+	//Function
+	//Return
+	return dead_letter.report;
+}
+
+/**
+* @fn Time
+* @brief Returns when the event was dead-lettered.
+* @struct dead_letter DeadLetter_struct
+* @return time.Time
+*/
+
+// Time returns when the event was dead-lettered.
+func (dead_letter DeadLetter_struct) Time() time.Time{
+	//Variables
+	//Parametres
+	//Function
+	//Return
+	return dead_letter.time;
+}
+
+/**
+* @fn EnableDeadLetterQueue
+* @brief Starts capturing events which `ProcessEvent` couldn't deliver.
+* @struct event_dispatcher *EventDispatcher_struct
+* @param capacity uint [in] The maximum number of dead letters kept; the oldest is discarded to make room. 0 means unlimited.
+* @return ( return_report error_report.ErrorReport_struct ) 
+* @retval 0 Success
+* @retval 1 Not Supported
+* @retval >1 Error
+*/
+
+// EnableDeadLetterQueue starts capturing events which `ProcessEvent` couldn't deliver.
+func (event_dispatcher *EventDispatcher_struct) EnableDeadLetterQueue( capacity uint ) ( return_report error_report.ErrorReport_struct ){
+	//Variables
+	//Parametres
+	//Function
+	event_dispatcher.dead_letter_mutex.Lock();
+	event_dispatcher.dead_letter_queue_enabled = true;
+	event_dispatcher.dead_letter_capacity = capacity;
+	event_dispatcher.trimDeadLetters_Unsafe();
+	return_report = error_report.New( 0, map[string]interface{}{ "dead_letters_length": len(event_dispatcher.dead_letters_slice) }, nil );
+	event_dispatcher.dead_letter_mutex.Unlock();
+	//Return
+	return return_report;
+}
+
+/**
+* @fn DisableDeadLetterQueue
+* @brief Stops capturing undeliverable events; dead letters already captured are kept until purged.
+* @struct event_dispatcher *EventDispatcher_struct
+* @return ( return_report error_report.ErrorReport_struct ) 
+* @retval 0 Success
+* @retval 1 Not Supported
+* @retval >1 Error
+*/
+
+// DisableDeadLetterQueue stops capturing undeliverable events; dead letters already captured are kept until purged.
+func (event_dispatcher *EventDispatcher_struct) DisableDeadLetterQueue() ( return_report error_report.ErrorReport_struct ){
+	//Variables
+	//Parametres
+	//Function
+	event_dispatcher.dead_letter_mutex.Lock();
+	event_dispatcher.dead_letter_queue_enabled = false;
+	return_report = error_report.New( 0, map[string]interface{}{ "dead_letters_length": len(event_dispatcher.dead_letters_slice) }, nil );
+	event_dispatcher.dead_letter_mutex.Unlock();
+	//Return
+	return return_report;
+}
+
+/**
+* @fn GetDeadLetters
+* @brief Returns a copy of the dead letters, oldest first, in the "dead_letters" datum.
+* @struct event_dispatcher *EventDispatcher_struct
+* @return ( return_report error_report.ErrorReport_struct ) 
+* @retval 0 Success
+* @retval 1 Not Supported
+* @retval >1 Error
+*/
+
+// GetDeadLetters returns a copy of the dead letters, oldest first, in the "dead_letters" datum.
+func (event_dispatcher *EventDispatcher_struct) GetDeadLetters() ( return_report error_report.ErrorReport_struct ){
+	//Variables
+	var dead_letters_slice []DeadLetter_struct;
+	//Parametres
+	//Function
+	event_dispatcher.dead_letter_mutex.Lock();
+	dead_letters_slice = make([]DeadLetter_struct, len(event_dispatcher.dead_letters_slice));
+	copy(dead_letters_slice, event_dispatcher.dead_letters_slice);
+	event_dispatcher.dead_letter_mutex.Unlock();
+	return_report = error_report.New( 0, map[string]interface{}{ "dead_letters": dead_letters_slice }, nil );
+	//Return
+	return return_report;
+}
+
+/**
+* @fn RequeueDeadLetterByIndex
+* @brief Removes the dead letter at the given index and pushes its event back onto the event queue with `PushEvent`.
+* @struct event_dispatcher *EventDispatcher_struct
+* @param index uint [in] The index, as returned by `GetDeadLetters`, of the dead letter to requeue.
+* @return ( return_report error_report.ErrorReport_struct ) 
+* @retval 0 Success
+* @retval 1 Not Supported
+* @retval >1 Error
+*/
+
+// RequeueDeadLetterByIndex removes the dead letter at the given index and pushes its event back onto the event queue with `PushEvent`.
+func (event_dispatcher *EventDispatcher_struct) RequeueDeadLetterByIndex( index uint ) ( return_report error_report.ErrorReport_struct ){
+	//Variables
+	var dead_letter DeadLetter_struct;
+	var found bool;
+	var dead_letters_length int;
+	var function_return error_report.ErrorReport_struct;
+	//Parametres
+	//Function
+	event_dispatcher.dead_letter_mutex.Lock();
+	dead_letters_length = len(event_dispatcher.dead_letters_slice);
+	if( int(index) < len(event_dispatcher.dead_letters_slice) ){
+		dead_letter = event_dispatcher.dead_letters_slice[index];
+		event_dispatcher.dead_letters_slice = append(event_dispatcher.dead_letters_slice[:index:index], event_dispatcher.dead_letters_slice[(index+1):]...);
+		found = true;
+	}
+	event_dispatcher.dead_letter_mutex.Unlock();
+	if( found == true ){
+		function_return = event_dispatcher.PushEvent( dead_letter.event );
+		if( function_return.NoError() == true ){
+			return_report = error_report.New( 0, map[string]interface{}{ "event": dead_letter.event }, nil );
+		} else{
+			return_report = error_report.New( ERROR_CODE_SUBORDINATE_FUNCTION_ERROR, map[string]interface{}{ "message": "event_dispatcher.PushEvent() returned an error.", "event": dead_letter.event }, &function_return );
+		}
+	} else{
+		return_report = error_report.New( ERROR_CODE_INDEX_OUT_OF_RANGE, map[string]interface{}{ "message": "index out of range.", "dead_letters_length": dead_letters_length }, nil );
+	}
+	//Return
+	return return_report;
+}
+
+/**
+* @fn RequeueDeadLetters
+* @brief Removes every dead letter and pushes their events back onto the event queue, oldest first, with `PushEvent`.
+* @struct event_dispatcher *EventDispatcher_struct
+* @return ( return_report error_report.ErrorReport_struct ) 
+* @retval 0 Success
+* @retval 1 Not Supported
+* @retval >1 Error
+*/
+
+// RequeueDeadLetters removes every dead letter and pushes their events back onto the event queue, oldest first, with `PushEvent`.
+func (event_dispatcher *EventDispatcher_struct) RequeueDeadLetters() ( return_report error_report.ErrorReport_struct ){
+	//Variables
+	var dead_letters_slice []DeadLetter_struct;
+	var i int;
+	var requeued int;
+	var function_return error_report.ErrorReport_struct;
+	//Parametres
+	//Function
+	event_dispatcher.dead_letter_mutex.Lock();
+	dead_letters_slice = event_dispatcher.dead_letters_slice;
+	event_dispatcher.dead_letters_slice = nil;
+	event_dispatcher.dead_letter_mutex.Unlock();
+	for i = 0; i < len(dead_letters_slice) && function_return.NoError() == true; i++ {
+		function_return = event_dispatcher.PushEvent( dead_letters_slice[i].event );
+		if( function_return.NoError() == true ){
+			requeued++;
+		}
+	}
+	if( function_return.NoError() == true ){
+		return_report = error_report.New( 0, map[string]interface{}{ "requeued": requeued }, nil );
+	} else{
+		///Put back whatever couldn't be requeued so nothing is lost.
+		event_dispatcher.dead_letter_mutex.Lock();
+		event_dispatcher.dead_letters_slice = append(dead_letters_slice[requeued:len(dead_letters_slice):len(dead_letters_slice)], event_dispatcher.dead_letters_slice...);
+		event_dispatcher.dead_letter_mutex.Unlock();
+		return_report = error_report.New( ERROR_CODE_SUBORDINATE_FUNCTION_ERROR, map[string]interface{}{ "message": "event_dispatcher.PushEvent() returned an error.", "requeued": requeued }, &function_return );
+	}
+	//Return
+	return return_report;
+}
+
+/**
+* @fn PurgeDeadLetters
+* @brief Discards every dead letter.
+* @struct event_dispatcher *EventDispatcher_struct
+* @return ( return_report error_report.ErrorReport_struct ) 
+* @retval 0 Success
+* @retval 1 Not Supported
+* @retval >1 Error
+*/
+
+// PurgeDeadLetters discards every dead letter.
+func (event_dispatcher *EventDispatcher_struct) PurgeDeadLetters() ( return_report error_report.ErrorReport_struct ){
+	//Variables
+	var purged int;
+	//Parametres
+	//Function
+	event_dispatcher.dead_letter_mutex.Lock();
+	purged = len(event_dispatcher.dead_letters_slice);
+	event_dispatcher.dead_letters_slice = nil;
+	event_dispatcher.dead_letter_mutex.Unlock();
+	return_report = error_report.New( 0, map[string]interface{}{ "purged": purged }, nil );
+	//Return
+	return return_report;
+}
+
+/**
+* @fn deadLetterIfFailed
+* @brief Captures the event in the dead-letter queue, if it's enabled, when `transmit_report` shows it wasn't delivered.
+* @struct event_dispatcher *EventDispatcher_struct
+* @param event Event_struct [in] The transmitted event.
+* @param transmit_report error_report.ErrorReport_struct [in] The report returned by `transmitEvent`.
+*/
+
+// deadLetterIfFailed captures the event in the dead-letter queue, if it's enabled, when `transmit_report` shows it wasn't delivered.
+func (event_dispatcher *EventDispatcher_struct) deadLetterIfFailed( event Event_struct, transmit_report error_report.ErrorReport_struct ){
+	//Variables
+	var reason uint8;
+	//Parametres
+	//Function
+	reason = deadLetterReason( transmit_report );
+	if( reason != 0 ){
+		event_dispatcher.addDeadLetter( event, reason, transmit_report );
+	}
+	//Return
+}
+
+/**
+* @fn addDeadLetter
+* @brief Captures the event in the dead-letter queue if it's enabled.
+* @struct event_dispatcher *EventDispatcher_struct
+* @param event Event_struct [in] The undeliverable event.
+* @param reason uint8 [in] One of the `DEAD_LETTER_REASON_*` constants.
+* @param report error_report.ErrorReport_struct [in] The report explaining the failure.
+*/
+
+// addDeadLetter captures the event in the dead-letter queue if it's enabled.
+func (event_dispatcher *EventDispatcher_struct) addDeadLetter( event Event_struct, reason uint8, report error_report.ErrorReport_struct ){
+	//Variables
+	//Parametres
+	///A requeued dead letter is a fresh delivery to every listener, not a retry of the one which gave up.
+	event.retry_event_listener_id = 0;
+	event.retry_attempt = 0;
+	//Function
+	event_dispatcher.dead_letter_mutex.Lock();
+	if( event_dispatcher.dead_letter_queue_enabled == true ){
+		event_dispatcher.dead_letters_slice = append(event_dispatcher.dead_letters_slice, DeadLetter_struct{ event: event, reason: reason, report: report, time: time.Now() });
+		event_dispatcher.trimDeadLetters_Unsafe();
+	}
+	event_dispatcher.dead_letter_mutex.Unlock();
+	//Return
+}
+
+/**
+* @fn trimDeadLetters_Unsafe
+* @brief Discards the oldest dead letters until there are no more than `dead_letter_capacity`; the caller must hold `dead_letter_mutex`.
+* @struct event_dispatcher *EventDispatcher_struct
+*/
+
+// trimDeadLetters_Unsafe discards the oldest dead letters until there are no more than `dead_letter_capacity`; the caller must hold `dead_letter_mutex`.
+func (event_dispatcher *EventDispatcher_struct) trimDeadLetters_Unsafe(){
+	//Variables
+	var excess int;
+	//Parametres
+	//Function
+	excess = len(event_dispatcher.dead_letters_slice) - int(event_dispatcher.dead_letter_capacity);
+	if( event_dispatcher.dead_letter_capacity > 0 && excess > 0 ){
+		event_dispatcher.dead_letters_slice = append([]DeadLetter_struct(nil), event_dispatcher.dead_letters_slice[excess:]...);
+	}
+	//Return
+}
+
+//# Global Variables
+var(
+	//## Exported Variables
+	//## Private Variables
+);
+
+//# Private Functions
+/**
+* @fn deadLetterReason
+* @brief Returns the `DEAD_LETTER_REASON_*` for a report returned by `transmitEvent`, or 0 if the event was delivered.
+* @param transmit_report error_report.ErrorReport_struct [in] The report returned by `transmitEvent`.
+* @return uint8
+*/
+
+// deadLetterReason returns the `DEAD_LETTER_REASON_*` for a report returned by `transmitEvent`, or 0 if the event was delivered.
+func deadLetterReason( transmit_report error_report.ErrorReport_struct ) uint8{
+	//Variables
+	var reason uint8;
+	var matched, match_errors, listener_errors, panics, retried int;
+	//Parametres
+	matched, _ = transmit_report.Data["matched"].(int);
+	match_errors, _ = transmit_report.Data["match_errors"].(int);
+	listener_errors, _ = transmit_report.Data["listener_errors"].(int);
+	panics, _ = transmit_report.Data["panics"].(int);
+	retried, _ = transmit_report.Data["retried"].(int);
+	//Function
+	if( matched == 0 ){
+		if( match_errors > 0 ){
+			reason = DEAD_LETTER_REASON_MATCH_ERROR;
+		} else{
+			reason = DEAD_LETTER_REASON_UNMATCHED;
+		}
+	} else if( listener_errors == matched && retried == 0 ){
+		if( panics > 0 ){
+			reason = DEAD_LETTER_REASON_PANIC;
+		} else{
+			reason = DEAD_LETTER_REASON_LISTENER_ERROR;
+		}
+	}
+	//Return
+	return reason;
+}
@@ -0,0 +1,105 @@
+/**
+* @file dead_letter_test.go
+* @brief Contains test functions for `dead_letter.go`.
+* @author Anadian
+* @copyright 	Copyright 2019 Canosw
+	Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+software and associated documentation files (the "Software"), to deal in the Software 
+without restriction, including without limitation the rights to use, copy, modify, 
+merge, publish, distribute, sublicense, and/or sell copies of the Software, and to 
+permit persons to whom the Software is furnished to do so, subject to the following 
+conditions:
+	The above copyright notice and this permission notice shall be included in all copies 
+or substantial portions of the Software.
+	THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A 
+PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT 
+HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF 
+CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE 
+OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+// dead_letter_test contains test functions for `dead_letter.go`.
+package event_dispatcher;
+
+//# Dependencies
+import(
+	//## Internal
+	//## Standard
+	"testing"
+	"log"
+	"errors"
+	//## External
+	error_report "github.com/Anadian/error_report/source"
+	matchkey "github.com/Anadian/matchkey/source"
+);
+
+//# Exported Functions
+/**
+* @fn TestDeadLetterQueue
+* @brief Tests capturing, inspecting, requeueing, and purging dead letters.
+* @param t *testing.T [in] Go stdlib testing object.
+*/
+
+// TestDeadLetterQueue tests capturing, inspecting, requeueing, and purging dead letters.
+func TestDeadLetterQueue( t *testing.T ){
+	//Variables
+	var event_dispatcher *EventDispatcher_struct;
+	var key matchkey.MatchKey_struct;
+	var dead_letters_slice []DeadLetter_struct;
+	var function_return error_report.ErrorReport_struct;
+	var reasons []uint8;
+	var i int;
+	//Parametres
+	//Function
+	key, _ = matchkey.New( matchkey.MATCHKEY_TYPE_STRING, "dead_letter:failing" );
+	event_dispatcher = newEventDispatcher( false, true );
+	event_dispatcher.AddEventListener( NewFallibleEventListener( key, false, 0, func( event Event_struct, args ...interface{} ) error{
+		return errors.New( "failed" );
+	} ).Data["event_listener"].(EventListener_struct) );
+	///Nothing is captured until the queue is enabled.
+	event_dispatcher.ProcessEvent( NewEvent( "dead_letter:unmatched", map[string]interface{}{} ).Data["event"].(Event_struct) );
+	event_dispatcher.EnableDeadLetterQueue( 0 );
+	event_dispatcher.ProcessEvent( NewEvent( "dead_letter:unmatched", map[string]interface{}{} ).Data["event"].(Event_struct) );
+	event_dispatcher.ProcessEvent( NewEvent( "dead_letter:failing", map[string]interface{}{} ).Data["event"].(Event_struct) );
+	dead_letters_slice = event_dispatcher.GetDeadLetters().Data["dead_letters"].([]DeadLetter_struct);
+	for i = 0; i < len(dead_letters_slice); i++ {
+		reasons = append(reasons, dead_letters_slice[i].Reason());
+	}
+	if( len(reasons) == 2 && reasons[0] == DEAD_LETTER_REASON_UNMATCHED && reasons[1] == DEAD_LETTER_REASON_LISTENER_ERROR ){
+		log.Printf("Success: Dead letters captured with reasons %v.\n", reasons);
+	} else{
+		t.Fail();
+		log.Printf("Failure: Unexpected dead letters: %v\n", dead_letters_slice);
+	}
+	function_return = event_dispatcher.RequeueDeadLetterByIndex( 1 );
+	if( function_return.NoError() == true && event_dispatcher.GetEventByIndex( 0 ).Data["event"].(Event_struct).name == "dead_letter:failing" ){
+		log.Printf("Success: Dead letter requeued.\n");
+	} else{
+		t.Fail();
+		log.Printf("Failure: event_dispatcher.RequeueDeadLetterByIndex returned: %v\n", function_return);
+	}
+	function_return = event_dispatcher.RequeueDeadLetterByIndex( 1 );
+	if( function_return.CodeEqual( ERROR_CODE_INDEX_OUT_OF_RANGE ) == true ){
+		log.Printf("Success: RequeueDeadLetterByIndex out of range.\n");
+	} else{
+		t.Fail();
+		log.Printf("Failure: Didn't get out of range (RequeueDeadLetterByIndex): %v\n", function_return);
+	}
+	function_return = event_dispatcher.RequeueDeadLetters();
+	if( function_return.NoError() == true && function_return.Data["requeued"] == 1 ){
+		log.Printf("Success: Remaining dead letters requeued.\n");
+	} else{
+		t.Fail();
+		log.Printf("Failure: event_dispatcher.RequeueDeadLetters returned: %v\n", function_return);
+	}
+	event_dispatcher.ProcessEvents();
+	function_return = event_dispatcher.PurgeDeadLetters();
+	if( function_return.Data["purged"] == 2 && len(event_dispatcher.GetDeadLetters().Data["dead_letters"].([]DeadLetter_struct)) == 0 ){
+		log.Printf("Success: Reprocessed events were dead-lettered again and purged.\n");
+	} else{
+		t.Fail();
+		log.Printf("Failure: event_dispatcher.PurgeDeadLetters returned: %v\n", function_return);
+	}
+	//Return
+}
@@ -0,0 +1,357 @@
+/**
+* @file envelope.go
+* @brief Envelope metadata carried by events alongside their data: ID, source, correlation and causation IDs, schema version, and headers.
+* @author Anadian
+* @copyright 	Copyright 2019 Canosw
+	Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+software and associated documentation files (the "Software"), to deal in the Software 
+without restriction, including without limitation the rights to use, copy, modify, 
+merge, publish, distribute, sublicense, and/or sell copies of the Software, and to 
+permit persons to whom the Software is furnished to do so, subject to the following 
+conditions:
+	The above copyright notice and this permission notice shall be included in all copies 
+or substantial portions of the Software.
+	THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A 
+PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT 
+HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF 
+CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE 
+OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+package event_dispatcher;
+
+//# Dependencies
+import(
+	//## Internal
+	//## Standard
+	"context"
+	"crypto/rand"
+	"fmt"
+	//## External
+);
+
+//# Constants
+const(
+	//## Exported Constants
+	//## Private Constants
+);
+
+//# Types
+//## Structs
+// parent_event_key_struct is the context key under which the IDs of the event being handled by a listener are stored.
+type parent_event_key_struct struct{}
+// parent_event_struct is what's stored under `parent_event_key_struct`: only the IDs, so a context doesn't keep the whole event, and through it every ancestor's data, alive.
+type parent_event_struct struct{
+	id string
+	correlation_id string
+}
+//### Methods
+/**
+* @fn ID
+* @brief Returns the event's unique ID, a random UUID assigned by `NewEvent`.
+* @struct event Event_struct
+* @return string
+*/
+
+// ID returns the event's unique ID, a random (version 4) UUID assigned by `NewEvent`.
+func (event Event_struct) ID() string{
+	//Variables
+	//Parametres
+	//Function
+	//Return
+	return event.id;
+}
+
+/**
+* @fn Source
+* @brief Returns what produced the event, as set by `WithSource`.
+* @struct event Event_struct
+* @return string
+*/
+
+// Source returns what produced the event, as set by `WithSource`.
+func (event Event_struct) Source() string{
+	//Variables
+	//Parametres
+	//Function
+	//Return
+	return event.source;
+}
+
+/**
+* @fn CorrelationID
+* @brief Returns the ID shared by every event in the causal chain this event belongs to.
+* @struct event Event_struct
+* @return string
+*/
+
+// CorrelationID returns the ID shared by every event in the causal chain this event belongs to: set by `WithCorrelationID`, inherited from the event whose listener published this one, or otherwise the event's own ID. Inheritance goes through the context: a listener must publish with the context it was given, as in `PublishCtx( event.Context(), new_event )` or `PublishCtx( ctx, new_event )` in a context listener. A plain `Publish( new_event )` starts a new chain, since `NewEvent` events have no context.
+func (event Event_struct) CorrelationID() string{
+	//Variables
+	var _return string = event.correlation_id;
+	//Parametres
+	//Function
+	if( _return == "" ){
+		_return = event.id;
+	}
+	//Return
+	return _return;
+}
+
+/**
+* @fn CausationID
+* @brief Returns the ID of the event which caused this one, or an empty string for the start of a chain.
+* @struct event Event_struct
+* @return string
+*/
+
+// CausationID returns the ID of the event which caused this one, or an empty string for the start of a chain.
+func (event Event_struct) CausationID() string{
+	//Variables
+	//Parametres
+	//Function
+	//Return
+	return event.causation_id;
+}
+
+/**
+* @fn SchemaVersion
+* @brief Returns the version of the schema of the event's data, as set by `WithSchemaVersion`.
+* @struct event Event_struct
+* @return string
+*/
+
+// SchemaVersion returns the version of the schema of the event's data, as set by `WithSchemaVersion`.
+func (event Event_struct) SchemaVersion() string{
+	//Variables
+	//Parametres
+	//Function
+	//Return
+	return event.schema_version;
+}
+
+/**
+* @fn Header
+* @brief Returns the value of the given header.
+* @struct event Event_struct
+* @param key string [in] The header's name.
+* @return ( value string, ok bool ) `ok` is false if there's no such header.
+*/
+
+// Header returns the value of the given header, and whether there was one.
+func (event Event_struct) Header( key string ) ( value string, ok bool ){
+	//Variables
+	//Parametres
+	//Function
+	value, ok = event.headers[key];
+	//Return
+	return value, ok;
+}
+
+/**
+* @fn Headers
+* @brief Returns a copy of the event's headers.
+* @struct event Event_struct
+* @return map[string]string
+*/
+
+// Headers returns a copy of the event's headers.
+func (event Event_struct) Headers() map[string]string{
+	//Variables
+	var _return map[string]string = make(map[string]string, len(event.headers));
+	var key string;
+	var value string;
+	//Parametres
+	//Function
+	for key, value = range event.headers {
+		_return[key] = value;
+	}
+	//Return
+	return _return;
+}
+
+/**
+* @fn WithSource
+* @brief Returns a copy of the event with the given source.
+* @struct event Event_struct
+* @param source string [in] What produced the event, such as a service or component name.
+* @return Event_struct
+*/
+
+// WithSource returns a copy of the event with the given source.
+func (event Event_struct) WithSource( source string ) Event_struct{
+	//Variables
+	//Parametres
+	//Function
+	event.source = source;
+	//Return
+	return event;
+}
+
+/**
+* @fn WithCorrelationID
+* @brief Returns a copy of the event with the given correlation ID, overriding inheritance.
+* @struct event Event_struct
+* @param correlation_id string [in] The correlation ID.
+* @return Event_struct
+*/
+
+// WithCorrelationID returns a copy of the event with the given correlation ID, which is then not inherited from the publishing listener's event; useful for continuing a chain started outside the process.
+func (event Event_struct) WithCorrelationID( correlation_id string ) Event_struct{
+	//Variables
+	//Parametres
+	//Function
+	event.correlation_id = correlation_id;
+	//Return
+	return event;
+}
+
+/**
+* @fn WithCausationID
+* @brief Returns a copy of the event with the given causation ID, overriding inheritance.
+* @struct event Event_struct
+* @param causation_id string [in] The ID of the event which caused this one.
+* @return Event_struct
+*/
+
+// WithCausationID returns a copy of the event with the given causation ID, which is then not inherited from the publishing listener's event.
+func (event Event_struct) WithCausationID( causation_id string ) Event_struct{
+	//Variables
+	//Parametres
+	//Function
+	event.causation_id = causation_id;
+	//Return
+	return event;
+}
+
+/**
+* @fn WithSchemaVersion
+* @brief Returns a copy of the event with the given schema version.
+* @struct event Event_struct
+* @param schema_version string [in] The version of the schema of the event's data.
+* @return Event_struct
+*/
+
+// WithSchemaVersion returns a copy of the event with the given schema version.
+func (event Event_struct) WithSchemaVersion( schema_version string ) Event_struct{
+	//Variables
+	//Parametres
+	//Function
+	event.schema_version = schema_version;
+	//Return
+	return event;
+}
+
+/**
+* @fn WithHeader
+* @brief Returns a copy of the event with the given header set; the original's headers are left as they were.
+* @struct event Event_struct
+* @param key string [in] The header's name.
+* @param value string [in] The header's value.
+* @return Event_struct
+*/
+
+// WithHeader returns a copy of the event with the given header set; the original's headers are left as they were.
+func (event Event_struct) WithHeader( key string, value string ) Event_struct{
+	//Variables
+	//Parametres
+	//Function
+	event.headers = event.Headers();
+	event.headers[key] = value;
+	//Return
+	return event;
+}
+
+//# Global Variables
+var(
+	//## Exported Variables
+	//## Private Variables
+);
+
+//# Exported Functions
+/**
+* @fn ParentEventIDs
+* @brief Returns the ID and correlation ID of the event whose listener the context was passed to, if any.
+* @param ctx context.Context [in] A context passed to a listener, or derived from one.
+* @return ( event_id string, correlation_id string, ok bool ) `ok` is false if the context didn't come from a listener.
+*/
+
+// ParentEventIDs returns the ID and correlation ID of the event whose listener the context was passed to, and whether there was one. Events published with such a context inherit the correlation ID and take the ID as their causation ID.
+func ParentEventIDs( ctx context.Context ) ( event_id string, correlation_id string, ok bool ){
+	//Variables
+	var parent_event parent_event_struct;
+	//Parametres
+	//Function
+	if( ctx != nil ){
+		parent_event, ok = ctx.Value( parent_event_key_struct{} ).(parent_event_struct);
+	}
+	//Return
+	return parent_event.id, parent_event.correlation_id, ok;
+}
+
+//# Private Functions
+/**
+* @fn newEventID
+* @brief Returns a random (version 4) UUID.
+* @return string
+*/
+
+// newEventID returns a random (version 4) UUID.
+func newEventID() string{
+	//Variables
+	var uuid [16]byte;
+	//Parametres
+	//Function
+	///crypto/rand only fails if the OS can't provide randomness at all, in which case the process has bigger problems.
+	_, _ = rand.Read( uuid[:] );
+	uuid[6] = ( uuid[6] & 0x0f ) | 0x40;
+	uuid[8] = ( uuid[8] & 0x3f ) | 0x80;
+	//Return
+	return fmt.Sprintf( "%x-%x-%x-%x-%x", uuid[0:4], uuid[4:6], uuid[6:8], uuid[8:10], uuid[10:16] );
+}
+
+/**
+* @fn withParentEvent
+* @brief Returns a context carrying the IDs of the given event, for a listener handling it.
+* @param ctx context.Context [in] The event's own context.
+* @param event Event_struct [in] The event being handled.
+* @return context.Context
+*/
+
+// withParentEvent returns a context carrying the IDs of the given event, for a listener handling it; see `ParentEventIDs`.
+func withParentEvent( ctx context.Context, event Event_struct ) context.Context{
+	//Variables
+	//Parametres
+	//Function
+	//Return
+	return context.WithValue( ctx, parent_event_key_struct{}, parent_event_struct{ id: event.id, correlation_id: event.CorrelationID() } );
+}
+
+/**
+* @fn inheritEnvelope
+* @brief Gives the event the correlation ID of the event whose listener is publishing it, and that event's ID as its causation ID, unless they've been set already.
+* @param ctx context.Context [in] The context the event is being published with.
+* @param event Event_struct [in] The event being published.
+* @return Event_struct
+*/
+
+// inheritEnvelope gives the event the correlation ID of the event whose listener is publishing it, and that event's ID as its causation ID, unless they've been set already.
+func inheritEnvelope( ctx context.Context, event Event_struct ) Event_struct{
+	//Variables
+	var parent_event_id string;
+	var parent_correlation_id string;
+	var ok bool;
+	//Parametres
+	//Function
+	parent_event_id, parent_correlation_id, ok = ParentEventIDs( ctx );
+	if( ok == true && parent_event_id != event.id ){
+		if( event.correlation_id == "" ){
+			event.correlation_id = parent_correlation_id;
+		}
+		if( event.causation_id == "" ){
+			event.causation_id = parent_event_id;
+		}
+	}
+	//Return
+	return event;
+}
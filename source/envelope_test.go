@@ -0,0 +1,121 @@
+/**
+* @file envelope_test.go
+* @brief Contains test functions for `envelope.go`.
+* @author Anadian
+* @copyright 	Copyright 2019 Canosw
+	Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+software and associated documentation files (the "Software"), to deal in the Software 
+without restriction, including without limitation the rights to use, copy, modify, 
+merge, publish, distribute, sublicense, and/or sell copies of the Software, and to 
+permit persons to whom the Software is furnished to do so, subject to the following 
+conditions:
+	The above copyright notice and this permission notice shall be included in all copies 
+or substantial portions of the Software.
+	THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A 
+PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT 
+HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF 
+CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE 
+OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+// envelope_test contains test functions for `envelope.go`.
+package event_dispatcher;
+
+//# Dependencies
+import(
+	//## Internal
+	//## Standard
+	"testing"
+	"log"
+	"context"
+	"regexp"
+	//## External
+	matchkey "github.com/Anadian/matchkey/source"
+);
+
+//# Exported Functions
+/**
+* @fn TestEventEnvelope
+* @brief Tests event IDs, the envelope builders, and correlation and causation inheritance through listeners.
+* @param t *testing.T [in] Go stdlib testing object.
+*/
+
+// TestEventEnvelope tests event IDs, the envelope builders, and correlation and causation inheritance through listeners.
+func TestEventEnvelope( t *testing.T ){
+	//Variables
+	var event_dispatcher *EventDispatcher_struct;
+	var key matchkey.MatchKey_struct;
+	var root_event Event_struct;
+	var event Event_struct;
+	var with_header Event_struct;
+	var received []Event_struct;
+	var value string;
+	var ok bool;
+	var parent_event_id string;
+	var parent_correlation_id string;
+	var unlinked []Event_struct;
+	//Parametres
+	//Function
+	root_event = NewEvent( "order:placed", map[string]interface{}{} ).Data["event"].(Event_struct);
+	event = NewEvent( "order:placed", map[string]interface{}{} ).Data["event"].(Event_struct);
+	if( regexp.MustCompile( `^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$` ).MatchString( root_event.ID() ) == true && root_event.ID() != event.ID() && root_event.CorrelationID() == root_event.ID() && root_event.CausationID() == "" ){
+		log.Printf("Success: NewEvent assigned a unique UUID: %s\n", root_event.ID());
+	} else{
+		t.Fail();
+		log.Printf("Failure: NewEvent assigned %q and %q\n", root_event.ID(), event.ID());
+	}
+	root_event = root_event.WithSource( "checkout" ).WithSchemaVersion( "2" ).WithHeader( "tenant", "acme" );
+	with_header = root_event.WithHeader( "tenant", "other" );
+	value, ok = root_event.Header( "tenant" );
+	if( root_event.Source() == "checkout" && root_event.SchemaVersion() == "2" && ok == true && value == "acme" && with_header.Headers()["tenant"] == "other" ){
+		log.Printf("Success: The envelope builders returned modified copies.\n");
+	} else{
+		t.Fail();
+		log.Printf("Failure: Got source %q, schema version %q, and headers %v and %v\n", root_event.Source(), root_event.SchemaVersion(), root_event.Headers(), with_header.Headers());
+	}
+	///order:placed -> payment:requested (context listener) -> receipt:sent (queued by a plain listener through event.Context()).
+	event_dispatcher = newEventDispatcher( false, false );
+	key, _ = matchkey.New( matchkey.MATCHKEY_TYPE_STRING, "order:placed" );
+	event_dispatcher.AddEventListener( NewContextEventListener( key, false, 0, func( ctx context.Context, event Event_struct, args ...interface{} ) error{
+		parent_event_id, parent_correlation_id, ok = ParentEventIDs( ctx );
+		event_dispatcher.PublishCtx( ctx, NewEvent( "payment:requested", map[string]interface{}{} ).Data["event"].(Event_struct) );
+		///Published without the context, so it starts a new chain.
+		event_dispatcher.Publish( NewEvent( "audit:logged", map[string]interface{}{} ).Data["event"].(Event_struct) );
+		return nil;
+	} ).Data["event_listener"].(EventListener_struct) );
+	key, _ = matchkey.New( matchkey.MATCHKEY_TYPE_STRING, "payment:requested" );
+	event_dispatcher.AddEventListener( NewEventListener( key, false, func( event Event_struct, args ...interface{} ){
+		received = append(received, event);
+		event_dispatcher.PushEventCtx( event.Context(), NewEvent( "receipt:sent", map[string]interface{}{} ).Data["event"].(Event_struct) );
+	} ).Data["event_listener"].(EventListener_struct) );
+	key, _ = matchkey.New( matchkey.MATCHKEY_TYPE_STRING, "audit:logged" );
+	event_dispatcher.AddEventListener( NewEventListener( key, false, func( event Event_struct, args ...interface{} ){
+		unlinked = append(unlinked, event);
+	} ).Data["event_listener"].(EventListener_struct) );
+	key, _ = matchkey.New( matchkey.MATCHKEY_TYPE_STRING, "receipt:sent" );
+	event_dispatcher.AddEventListener( NewEventListener( key, false, func( event Event_struct, args ...interface{} ){
+		received = append(received, event);
+	} ).Data["event_listener"].(EventListener_struct) );
+	event_dispatcher.Publish( root_event );
+	event_dispatcher.ProcessEvents();
+	if( len(received) == 2 && received[0].CorrelationID() == root_event.ID() && received[0].CausationID() == root_event.ID() && received[1].CorrelationID() == root_event.ID() && received[1].CausationID() == received[0].ID() ){
+		log.Printf("Success: The chain kept the root's correlation ID with each event caused by the last.\n");
+	} else{
+		t.Fail();
+		log.Printf("Failure: The chain from %s was %v\n", root_event.ID(), received);
+	}
+	if( ok == true && parent_event_id == root_event.ID() && parent_correlation_id == root_event.ID() ){
+		log.Printf("Success: ParentEventIDs returned the handled event's IDs.\n");
+	} else{
+		t.Fail();
+		log.Printf("Failure: ParentEventIDs returned %q, %q, %v\n", parent_event_id, parent_correlation_id, ok);
+	}
+	if( len(unlinked) == 1 && unlinked[0].CorrelationID() == unlinked[0].ID() && unlinked[0].CausationID() == "" ){
+		log.Printf("Success: An event published without the listener's context started a new chain.\n");
+	} else{
+		t.Fail();
+		log.Printf("Failure: The event published without the context was %v\n", unlinked);
+	}
+	//Return
+}
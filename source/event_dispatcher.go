@@ -24,9 +24,14 @@ package event_dispatcher;
 import(
 	//## Internal
 	//## Standard
+	"context"
 	"time"
 	"sync"
 	"strconv"
+	"sort"
+	"sync/atomic"
+	"runtime/debug"
+	"fmt"
 	//## External
 	error_report "github.com/Anadian/error_report/source"
 	matchkey "github.com/Anadian/matchkey/source"
@@ -42,26 +47,337 @@ const(
 	ERROR_CODE_INVALID_MATCHKEY_TYPE int64 = 5;
 	ERROR_CODE_EVENT_LISTENER_MATCH int64 = 6;
 	ERROR_CODE_EVENT_PROCESSING_ERROR int64 = 7;
+	ERROR_CODE_ALREADY_RUNNING int64 = 21;
+	ERROR_CODE_NOT_RUNNING int64 = 22;
+	ERROR_CODE_EVENT_LISTENER_NOT_FOUND int64 = 23;
+	ERROR_CODE_EVENT_LISTENER_ERROR int64 = 24;
+	ERROR_CODE_EVENT_LISTENER_PANIC int64 = 25;
 	//## Private Constants
 );
 
 //Types, structs, and methods
+// Event_struct is passed by value and its data is never modified after `NewEvent`: the dispatcher replaces the map with a modified copy instead, so listeners running concurrently each see a consistent snapshot.
 type Event_struct struct{
+	id string
 	name string
 	//time time.Time
 	data map[string]interface{}
+	propagation *propagation_struct
+	priority int64
+	//Set on the copies queued by a retry policy so only the failed listener is called again.
+	retry_event_listener_id uint64
+	retry_attempt uint
+	partition_key string
+	//The context the event was published with; only its values are kept for queued events.
+	ctx context.Context
+	//Envelope metadata; see envelope.go.
+	source string
+	correlation_id string
+	causation_id string
+	schema_version string
+	headers map[string]string
+	//Set on events queued by a dispatcher with a write-ahead log or queue backend, to acknowledge them by.
+	acknowledgement_id uint64
+}
+// propagation_struct is shared by every copy of an event handed to the synchronous listeners of a single dispatch.
+type propagation_struct struct{
+	stopped int32
+	default_prevented int32
 }
 type EventListener_struct struct{
+	id uint64
 	key matchkey.MatchKey_struct
 	async bool
-	function func( event Event_struct, args ...interface{} )
+	priority int64
+	retry_policy *RetryPolicy_struct
+	function func( ctx context.Context, event Event_struct, args ...interface{} ) error
+	//The name `function` is registered under, for listeners from `NewNamedEventListener`; what `Snapshot` records.
+	function_name string
+}
+// Subscription_struct is returned by `AddEventListener` and identifies exactly one added event listener.
+type Subscription_struct struct{
+	id uint64
+	event_dispatcher *EventDispatcher_struct
 }
 type EventDispatcher_struct struct{
-	mutex sync.Mutex
+	//A pointer so the struct can be returned by value, as `NewEventDispatcher` does, without copying a lock.
+	mutex *sync.Mutex
 	add_times bool
 	buffered bool
-	events_slice []Event_struct
+	events_queue event_queue_interface
+	queue_mode uint8
+	//Copy-on-write: never modified in place so a dispatch can iterate a snapshot of it without holding `mutex`.
 	event_listeners_slice []EventListener_struct
+	last_event_listener_id uint64
+	error_sink func( report error_report.ErrorReport_struct )
+	panic_hook func( report error_report.ErrorReport_struct )
+	//The dead-letter queue is guarded by `dead_letter_mutex`, not `mutex`, because failed events are captured from within a dispatch.
+	dead_letter_mutex *sync.Mutex
+	dead_letter_queue_enabled bool
+	dead_letter_capacity uint
+	dead_letters_slice []DeadLetter_struct
+	queue_capacity uint
+	overflow_strategy uint8
+	dropped_event_counts map[uint8]uint64
+	space_channel chan struct{}
+	auto_drain bool
+	//Set while a `Publish` call is draining the queue, so concurrent publishers leave their events to it.
+	draining bool
+	wake_channel chan struct{}
+	worker_pool *worker_pool_struct
+	//Set by `NewEventDispatcherWithWriteAheadLog` and never changed.
+	write_ahead_log *write_ahead_log_struct
+	//Set by `NewEventDispatcherWithQueueBackend` and never changed; replaces `events_queue`.
+	queue_backend QueueBackend_interface
+	visibility_timeout time.Duration
+	recorder *Recorder_struct
+	partition_key_function func( event Event_struct ) string
+	//Retries waiting out their backoff, so `Stop` and `Close` can cancel them; guarded by `retry_mutex`, not `mutex`, because retries are scheduled from within a dispatch.
+	retry_mutex *sync.Mutex
+	retry_timers_map map[*time.Timer]struct{}
+	//The number of asynchronous listener calls which haven't returned; `idle_channel` is closed when it drops to 0. Both are guarded by `in_flight_mutex`, not `mutex`, because calls are submitted from within a dispatch.
+	in_flight_mutex *sync.Mutex
+	in_flight uint64
+	idle_channel chan struct{}
+	//Run loop state; guarded by `run_mutex` so `Stop` can wait on the loop without holding `mutex`.
+	run_mutex *sync.Mutex
+	running bool
+	drain_on_stop bool
+	stop_channel chan struct{}
+	done_channel chan struct{}
+	run_report error_report.ErrorReport_struct
+}
+// dispatch_snapshot_struct is a copy of the dispatcher state a dispatch depends on, taken under `mutex` so nothing called from `transmitEvent` has to lock it again.
+type dispatch_snapshot_struct struct{
+	event_listeners_slice []EventListener_struct
+	add_times bool
+	recorder *Recorder_struct
+	error_sink func( report error_report.ErrorReport_struct )
+	panic_hook func( report error_report.ErrorReport_struct )
+	worker_pool *worker_pool_struct
+	partition_key_function func( event Event_struct ) string
+}
+
+/**
+* @fn Name
+* @brief Returns the event's name.
+* @struct event Event_struct
+* @return string
+*/
+
+// Name returns the event's name.
+func (event Event_struct) Name() string{
+	//Variables
+	//Parametres
+	//Function
+	//Return
+	return event.name;
+}
+
+/**
+* @fn Get
+* @brief Returns the value stored under the given key in the event's data.
+* @struct event Event_struct
+* @param key string [in] The key.
+* @return ( value interface{}, ok bool ) `ok` is false if there's no such key.
+*/
+
+// Get returns the value stored under the given key in the event's data, and whether there was one.
+func (event Event_struct) Get( key string ) ( value interface{}, ok bool ){
+	//Variables
+	//Parametres
+	//Function
+	value, ok = event.data[key];
+	//Return
+	return value, ok;
+}
+
+/**
+* @fn Data
+* @brief Returns a shallow copy of the event's data, including the "creation_time", "submission_time", and "transmission_time" stamps.
+* @struct event Event_struct
+* @return map[string]interface{}
+*/
+
+// Data returns a shallow copy of the event's data, including the "creation_time", "submission_time", and "transmission_time" stamps. Changing the copy doesn't affect the event; values the map points into are shared, so shouldn't be modified by listeners.
+func (event Event_struct) Data() map[string]interface{}{
+	//Variables
+	//Parametres
+	//Function
+	//Return
+	return copyData( event.data );
+}
+
+/**
+* @fn withDatum
+* @brief Returns a copy of the event whose data is a copy of the original with `key` set to `value`; the original's data is left as it was.
+* @struct event Event_struct
+* @param key string [in] The key.
+* @param value interface{} [in] The value.
+* @return Event_struct
+*/
+
+// withDatum returns a copy of the event whose data is a copy of the original with `key` set to `value`; the original's data is left as it was.
+func (event Event_struct) withDatum( key string, value interface{} ) Event_struct{
+	//Variables
+	//Parametres
+	//Function
+	event.data = copyData( event.data );
+	event.data[key] = value;
+	//Return
+	return event;
+}
+
+/**
+* @fn StopPropagation
+* @brief Stops the event currently being dispatched from reaching any further, lower-priority, listeners; only has an effect when called from a synchronous listener.
+* @struct event Event_struct
+*/
+
+// StopPropagation stops the event currently being dispatched from reaching any further, lower-priority, listeners; only has an effect when called from a synchronous listener.
+func (event Event_struct) StopPropagation(){
+	//Variables
+	//Parametres
+	//Function
+	if( event.propagation != nil ){
+		atomic.StoreInt32( &(event.propagation.stopped), 1 );
+	}
+	//Return
+}
+
+/**
+* @fn PreventDefault
+* @brief Marks the event currently being dispatched as cancelled so the publisher can veto whatever the event announced; only has an effect when called from a synchronous listener.
+* @struct event Event_struct
+*/
+
+// PreventDefault marks the event currently being dispatched as cancelled so the publisher can veto whatever the event announced; only has an effect when called from a synchronous listener.
+func (event Event_struct) PreventDefault(){
+	//Variables
+	//Parametres
+	//Function
+	if( event.propagation != nil ){
+		atomic.StoreInt32( &(event.propagation.default_prevented), 1 );
+	}
+	//Return
+}
+
+/**
+* @fn IsPropagationStopped
+* @brief Returns true if a listener has called `StopPropagation` during the current dispatch.
+* @struct event Event_struct
+* @return bool
+*/
+
+// IsPropagationStopped returns true if a listener has called `StopPropagation` during the current dispatch.
+func (event Event_struct) IsPropagationStopped() bool{
+	//Variables
+	var _return bool;
+	//Parametres
+	//Function
+	if( event.propagation != nil ){
+		_return = ( atomic.LoadInt32( &(event.propagation.stopped) ) != 0 );
+	}
+	//Return
+	return _return;
+}
+
+/**
+* @fn IsDefaultPrevented
+* @brief Returns true if a listener has called `PreventDefault` during the current dispatch.
+* @struct event Event_struct
+* @return bool
+*/
+
+// IsDefaultPrevented returns true if a listener has called `PreventDefault` during the current dispatch.
+func (event Event_struct) IsDefaultPrevented() bool{
+	//Variables
+	var _return bool;
+	//Parametres
+	//Function
+	if( event.propagation != nil ){
+		_return = ( atomic.LoadInt32( &(event.propagation.default_prevented) ) != 0 );
+	}
+	//Return
+	return _return;
+}
+
+/**
+* @fn ID
+* @brief Returns the unique ID assigned to the subscription's event listener.
+* @struct subscription Subscription_struct
+* @return uint64
+*/
+
+// ID returns the unique ID assigned to the subscription's event listener.
+func (subscription Subscription_struct) ID() uint64{
+	//Variables
+	//Parametres
+	//Function
+	//Return
+	return subscription.id;
+}
+
+/**
+* @fn Unsubscribe
+* @brief Removes the subscription's event listener, and only that listener, from the event dispatcher it was added to.
+* @struct subscription Subscription_struct
+* @return ( return_report error_report.ErrorReport_struct ) 
+* @retval 0 Success
+* @retval 1 Not Supported
+* @retval >1 Error
+*/
+
+// Unsubscribe removes the subscription's event listener, and only that listener, from the event dispatcher it was added to.
+func (subscription Subscription_struct) Unsubscribe() ( return_report error_report.ErrorReport_struct ){
+	//Variables
+	var function_return error_report.ErrorReport_struct;
+	//Parametres
+	//Function
+	if( subscription.event_dispatcher != nil ){
+		function_return = subscription.event_dispatcher.RemoveEventListenerByID( subscription.id );
+		if( function_return.NoError() == true ){
+			return_report = function_return;
+		} else{
+			return_report = error_report.New( ERROR_CODE_SUBORDINATE_FUNCTION_ERROR, map[string]interface{}{ "message": "event_dispatcher.RemoveEventListenerByID() returned an error." }, &function_return );
+		}
+	} else{
+		return_report = error_report.New( ERROR_CODE_EVENT_LISTENER_NOT_FOUND, map[string]interface{}{ "message": "The subscription isn't attached to an event dispatcher." }, nil );
+	}
+	//Return
+	return return_report;
+}
+
+/**
+* @fn ID
+* @brief Returns the ID assigned to the event listener when it was added to an event dispatcher; 0 if it hasn't been added.
+* @struct event_listener EventListener_struct
+* @return uint64
+*/
+
+// ID returns the ID assigned to the event listener when it was added to an event dispatcher; 0 if it hasn't been added.
+func (event_listener EventListener_struct) ID() uint64{
+	//Variables
+	//Parametres
+	//Function
+	//Return
+	return event_listener.id;
+}
+
+/**
+* @fn Priority
+* @brief Returns the event listener's priority.
+* @struct event_listener EventListener_struct
+* @return int64
+*/
+
+// Priority returns the event listener's priority.
+func (event_listener EventListener_struct) Priority() int64{
+	//Variables
+	//Parametres
+	//Function
+	//Return
+	return event_listener.priority;
 }
 
 /**
@@ -75,14 +391,59 @@ type EventDispatcher_struct struct{
 * @retval >1 Error
 */
 
-// AddEventListener adds an event listener to the event dispatcher.
+// AddEventListener adds an event listener to the event dispatcher. The returned report's "subscription" datum is a `Subscription_struct` which can remove exactly this listener again.
 func (event_dispatcher *EventDispatcher_struct) AddEventListener( event_listener EventListener_struct ) (return_report error_report.ErrorReport_struct){
 	/* Variables */
+	var event_listeners_slice []EventListener_struct;
+	var subscription Subscription_struct;
+	var index int;
+	/* Parametres */
+	/* Function */
+	event_dispatcher.mutex.Lock();
+	event_dispatcher.last_event_listener_id++;
+	event_listener.id = event_dispatcher.last_event_listener_id;
+	///Keep the slice ordered by descending priority; a new listener goes after every listener of the same priority.
+	index = sort.Search( len(event_dispatcher.event_listeners_slice), func( i int ) bool{
+		return ( event_dispatcher.event_listeners_slice[i].priority < event_listener.priority );
+	} );
+	event_listeners_slice = make([]EventListener_struct, 0, (len(event_dispatcher.event_listeners_slice) + 1));
+	event_listeners_slice = append(event_listeners_slice, event_dispatcher.event_listeners_slice[:index]...);
+	event_listeners_slice = append(event_listeners_slice, event_listener);
+	event_dispatcher.event_listeners_slice = append(event_listeners_slice, event_dispatcher.event_listeners_slice[index:]...);
+	subscription.id = event_listener.id;
+	subscription.event_dispatcher = event_dispatcher;
+	return_report = error_report.New( 0, map[string]interface{}{ "event_listeners_slice_length": len(event_dispatcher.event_listeners_slice), "event_listener_id": event_listener.id, "subscription": subscription }, nil );
+	event_dispatcher.mutex.Unlock();
+	/* Return */
+	return return_report;
+}
+
+/**
+* @fn RemoveEventListenerByID
+* @brief Removes the event listener with the given ID; safe to call from within a listener while an event is being dispatched.
+* @struct event_dispatcher *EventDispatcher_struct
+* @param id uint64 [in] The ID returned by `AddEventListener` for the event listener to be removed.
+* @return (return_report error_report.ErrorReport_struct)
+* @retval 0 Success
+* @retval 1 Not Supported
+* @retval >1 Error
+*/
+
+// RemoveEventListenerByID removes the event listener with the given ID; safe to call from within a listener while an event is being dispatched.
+func (event_dispatcher *EventDispatcher_struct) RemoveEventListenerByID( id uint64 ) (return_report error_report.ErrorReport_struct){
+	/* Variables */
+	var removed int;
 	/* Parametres */
 	/* Function */
 	event_dispatcher.mutex.Lock();
-	event_dispatcher.event_listeners_slice = append(event_dispatcher.event_listeners_slice, event_listener);
-	return_report = error_report.New( 0, map[string]interface{}{ "event_listeners_slice_length": len(event_dispatcher.event_listeners_slice) }, nil );
+	removed = event_dispatcher.removeEventListeners_Unsafe( func( event_listener EventListener_struct ) bool{
+		return ( event_listener.id == id );
+	} );
+	if( removed > 0 ){
+		return_report = error_report.New( 0, map[string]interface{}{ "event_listeners_slice_length": len(event_dispatcher.event_listeners_slice), "event_listener_id": id }, nil );
+	} else{
+		return_report = error_report.New( ERROR_CODE_EVENT_LISTENER_NOT_FOUND, map[string]interface{}{ "message": "No event listener with the given ID.", "event_listener_id": id }, nil );
+	}
 	event_dispatcher.mutex.Unlock();
 	/* Return */
 	return return_report;
@@ -90,7 +451,7 @@ func (event_dispatcher *EventDispatcher_struct) AddEventListener( event_listener
 
 /**
 * @fn RemoveEventListenerByStringLiteral
-* @brief RemoveEventListenerByStringLiteralByStringLiteral
+* @brief Removes every event listener whose key has the given string literal.
 * @struct event_dispatcher *EventDispatcher_struct
 * @param string_literal string [in] The key.matchkey_string value for the event listener to be removed.
 * @return (return_report error_report.ErrorReport_struct)
@@ -99,23 +460,17 @@ func (event_dispatcher *EventDispatcher_struct) AddEventListener( event_listener
 * @retval >1 Error
 */
 
-// RemoveEventListenerByStringLiteral removeEventListenerByStringLiteral
+// RemoveEventListenerByStringLiteral removes every event listener whose key has the given string literal; use `RemoveEventListenerByID` to remove just one of several listeners sharing a pattern.
 func (event_dispatcher *EventDispatcher_struct) RemoveEventListenerByStringLiteral( string_literal string ) (return_report error_report.ErrorReport_struct){
 	/* Variables */
-	var i uint;
-	var before_slice []EventListener_struct;
-	var after_slice []EventListener_struct;
+	var removed int;
 	/* Parametres */
 	/* Function */
 	event_dispatcher.mutex.Lock();
-	for i = 0; int(i) < len(event_dispatcher.event_listeners_slice); i++ {
-		if( event_dispatcher.event_listeners_slice[i].key.Matchkey_string == string_literal ){
-			before_slice = event_dispatcher.event_listeners_slice[:i];
-			after_slice = event_dispatcher.event_listeners_slice[(i+1):];
-			event_dispatcher.event_listeners_slice = append(before_slice,after_slice...);
-		}
-	}
-	return_report = error_report.New( 0, map[string]interface{}{ "event_listeners_slice_length": len(event_dispatcher.event_listeners_slice) }, nil );
+	removed = event_dispatcher.removeEventListeners_Unsafe( func( event_listener EventListener_struct ) bool{
+		return ( event_listener.key.Matchkey_string == string_literal );
+	} );
+	return_report = error_report.New( 0, map[string]interface{}{ "event_listeners_slice_length": len(event_dispatcher.event_listeners_slice), "removed": removed }, nil );
 	event_dispatcher.mutex.Unlock();
 	/* Return */
 	return return_report;
@@ -124,7 +479,7 @@ func (event_dispatcher *EventDispatcher_struct) RemoveEventListenerByStringLiter
 /**
 * @fn GetEventByIndex
 * @brief Returns a copy of the event, at the given index, in the events slice; it does not modify the event slice.
-* @struct event_dispatcher EventDispatcher_struct
+* @struct event_dispatcher *EventDispatcher_struct
 * @param index uint [in] The index you the event to be retrieved.
 * @return ( return_report error_report.ErrorReport_struct ) 
 * @retval 0 Success
@@ -133,17 +488,20 @@ func (event_dispatcher *EventDispatcher_struct) RemoveEventListenerByStringLiter
 */
 
 // GetEventByIndex returns a copy of the event, at the given index, in the events slice; it does not modify the event slice.
-func (event_dispatcher EventDispatcher_struct) GetEventByIndex( index uint ) ( return_report error_report.ErrorReport_struct ){
+func (event_dispatcher *EventDispatcher_struct) GetEventByIndex( index uint ) ( return_report error_report.ErrorReport_struct ){
 	//Variables
 	var event Event_struct;
 	//Parametres
+	if( event_dispatcher.queue_backend != nil ){
+		return event_dispatcher.queue_backend.Get( index );
+	}
 	//Function
 	event_dispatcher.mutex.Lock();
-	if( int(index) < len(event_dispatcher.events_slice) ){
-		event = event_dispatcher.events_slice[index];
+	if( int(index) < event_dispatcher.getEventsQueue_Unsafe().Length() ){
+		event = event_dispatcher.events_queue.Get( int(index) );
 		return_report = error_report.New( 0, map[string]interface{}{ "event": event }, nil );
 	} else{
-		return_report = error_report.New( ERROR_CODE_INDEX_OUT_OF_RANGE, map[string]interface{}{ "message": "index out of range.", "events_slice_length": len(event_dispatcher.events_slice) }, nil );
+		return_report = error_report.New( ERROR_CODE_INDEX_OUT_OF_RANGE, map[string]interface{}{ "message": "index out of range.", "events_slice_length": event_dispatcher.events_queue.Length() }, nil );
 	}
 	event_dispatcher.mutex.Unlock();
 
@@ -165,18 +523,18 @@ func (event_dispatcher EventDispatcher_struct) GetEventByIndex( index uint ) ( r
 // RemoveEventByIndex removes the event at the given index from the events slice.
 func (event_dispatcher *EventDispatcher_struct) RemoveEventByIndex( index uint ) ( return_report error_report.ErrorReport_struct ){
 	//Variables
-	var preceding_events []Event_struct;
-	var following_events []Event_struct;
 	//Parametres
+	if( event_dispatcher.queue_backend != nil ){
+		return event_dispatcher.queue_backend.Remove( index );
+	}
 	//Function
 	event_dispatcher.mutex.Lock();
-	if( int(index) < len(event_dispatcher.events_slice) ){
-		preceding_events = event_dispatcher.events_slice[:index];
-		following_events = event_dispatcher.events_slice[(index+1):];
-		event_dispatcher.events_slice = append(preceding_events, following_events...);
-		return_report = error_report.New( 0, map[string]interface{}{ "events_slice_length": len(event_dispatcher.events_slice) }, nil );
+	if( int(index) < event_dispatcher.getEventsQueue_Unsafe().Length() ){
+		event_dispatcher.acknowledgeEvent( event_dispatcher.events_queue.Remove( int(index) ) );
+		event_dispatcher.signalSpace_Unsafe();
+		return_report = error_report.New( 0, map[string]interface{}{ "events_slice_length": event_dispatcher.events_queue.Length() }, nil );
 	} else{
-		return_report = error_report.New( ERROR_CODE_INDEX_OUT_OF_RANGE, map[string]interface{}{ "message": "index out of range.", "events_slice_length": len(event_dispatcher.events_slice) }, nil );
+		return_report = error_report.New( ERROR_CODE_INDEX_OUT_OF_RANGE, map[string]interface{}{ "message": "index out of range.", "events_slice_length": event_dispatcher.events_queue.Length() }, nil );
 	}
 	event_dispatcher.mutex.Unlock();
 	//Return
@@ -197,25 +555,23 @@ func (event_dispatcher *EventDispatcher_struct) RemoveEventByIndex( index uint )
 // ExtractEventByIndex extracts the event at the given index from the events slice, removing it from the slice, and returns it.
 func (event_dispatcher *EventDispatcher_struct) ExtractEventByIndex( index uint ) ( return_report error_report.ErrorReport_struct ){
 	//Variables
-	var temp_event interface{};
 	var event Event_struct;
-	var get_error_report error_report.ErrorReport_struct;
-	var remove_error_report error_report.ErrorReport_struct;
+	var range_error_report error_report.ErrorReport_struct;
 	//Parametres
+	if( event_dispatcher.queue_backend != nil ){
+		return event_dispatcher.queue_backend.Remove( index );
+	}
 	//Function
-	get_error_report = event_dispatcher.GetEventByIndex(index);
-	if( get_error_report.NoError() == true ){
-		temp_event = get_error_report.Data["event"]
-		event = temp_event.(Event_struct);
-		remove_error_report = event_dispatcher.RemoveEventByIndex(index);
-		if( remove_error_report.NoError() == true ){
-			return_report = error_report.New( 0, map[string]interface{}{ "event": event, "events_slice_length": remove_error_report.Data["events_slice_length"] }, nil );
-		} else{
-			return_report = error_report.New( ERROR_CODE_SUBORDINATE_FUNCTION_ERROR, map[string]interface{}{ "message": "event_dispatcher.RemoveEventByIndex() returned an error." }, &remove_error_report );
-		}
+	event_dispatcher.mutex.Lock();
+	if( int(index) < event_dispatcher.getEventsQueue_Unsafe().Length() ){
+		event = event_dispatcher.events_queue.Remove( int(index) );
+		event_dispatcher.signalSpace_Unsafe();
+		return_report = error_report.New( 0, map[string]interface{}{ "event": event, "events_slice_length": event_dispatcher.events_queue.Length() }, nil );
 	} else{
-		return_report = error_report.New( ERROR_CODE_SUBORDINATE_FUNCTION_ERROR, map[string]interface{}{ "message": "event_dispatcher.GetEventByIndex() returned an error." }, &get_error_report );
+		range_error_report = error_report.New( ERROR_CODE_INDEX_OUT_OF_RANGE, map[string]interface{}{ "message": "index out of range.", "events_slice_length": event_dispatcher.events_queue.Length() }, nil );
+		return_report = error_report.New( ERROR_CODE_SUBORDINATE_FUNCTION_ERROR, map[string]interface{}{ "message": "event_dispatcher.GetEventByIndex() returned an error." }, &range_error_report );
 	}
+	event_dispatcher.mutex.Unlock();
 	//Return
 	return return_report;
 }
@@ -232,266 +588,701 @@ func (event_dispatcher *EventDispatcher_struct) ExtractEventByIndex( index uint
 * @retval >1 Error
 */
 
-// InsertEventAtIndex inserts the given event into the events slice at the given index.
+// InsertEventAtIndex inserts the given event into the events slice at the given index, replacing the event already there; indices past the end append it. Appending to a full bounded queue is handled as described for `PushEventCtx`.
 func (event_dispatcher *EventDispatcher_struct) InsertEventAtIndex( event Event_struct, index uint ) ( return_report error_report.ErrorReport_struct ){
 	//Variables
-	var preceding_events []Event_struct;
-	var following_events []Event_struct;
 	//Parametres
 	//Function
-	event_dispatcher.mutex.Lock();
-	if( event_dispatcher.add_times == true ){
-		event.data["submission_time"] = time.Now();
-	}
-	if( int(index) >= len(event_dispatcher.events_slice) ){
-		event_dispatcher.events_slice = append(event_dispatcher.events_slice,event);
-	} else{
-		preceding_events = event_dispatcher.events_slice[:index];
-		following_events = event_dispatcher.events_slice[(index+1):];
-		event_dispatcher.events_slice = append(preceding_events,event);
-		event_dispatcher.events_slice = append(event_dispatcher.events_slice,following_events...);
-	}
-	return_report = error_report.New( 0, map[string]interface{}{ "events_slice_length": len(event_dispatcher.events_slice) }, nil );
-	event_dispatcher.mutex.Unlock();
+	return_report = event_dispatcher.InsertEventAtIndexCtx( event.Context(), event, index );
 	//Return
 	return return_report;
 }
 
 /**
-* @fn PublishEvent
-* @brief Publishes the given event to listeners registered with the dispatcher.
-* @struct event_dispatcher EventDispatcher_struct
-* @param event Event_struct [in] The event to be published.
+* @fn InsertEventAtIndexCtx
+* @brief Inserts the given event into the events slice at the given index, replacing the event already there, giving up when `ctx` is done if appending to a full bounded queue blocks.
+* @struct event_dispatcher *EventDispatcher_struct
+* @param ctx context.Context [in] Bounds how long to wait for room in a queue using `OVERFLOW_STRATEGY_BLOCK`.
+* @param event Event_struct [in] The event to insert.
+* @param index uint [in] The index of the event to replace; indices past the end append it.
 * @return ( return_report error_report.ErrorReport_struct ) 
 * @retval 0 Success
 * @retval 1 Not Supported
 * @retval >1 Error
-*
+*/
 
-// PublishEvent publishes the given event to listeners registered with the dispatcher.
-func (event_dispatcher EventDispatcher_struct) PublishEvent( event Event_struct ) ( return_report error_report.ErrorReport_struct ){
+// InsertEventAtIndexCtx inserts the given event into the events slice at the given index, replacing the event already there, giving up when `ctx` is done if appending to a full bounded queue blocks. A replaced event is acknowledged, as if it had been removed.
+func (event_dispatcher *EventDispatcher_struct) InsertEventAtIndexCtx( ctx context.Context, event Event_struct, index uint ) ( return_report error_report.ErrorReport_struct ){
 	//Variables
-	var listener_index int;
-	var match bool;
-	var match_error_report error_report.ErrorReport_struct;
-	var listener_index_string string;
-	var errors_interface interface{};
-	var errors_int int;
+	var function_return error_report.ErrorReport_struct;
+	var insert bool;
+	var replace bool;
 	//Parametres
+	if( event_dispatcher.queue_backend != nil ){
+		return event_dispatcher.enqueueBackendEvent( ctx, event, int(index) );
+	}
 	//Function
 	event_dispatcher.mutex.Lock();
-	if( event_dispatcher.buffered == true ){
-		event_dispatcher.events_slice = append(event_dispatcher.events_slice, event);
+	replace = ( int(index) < event_dispatcher.getEventsQueue_Unsafe().Length() );
+	if( replace == true ){
+		///Replacing an event doesn't need any room.
+		insert = true;
+		function_return = error_report.New( 0, map[string]interface{}{}, nil );
 	} else{
-		for listener_index = 0; listener_index < len(event_dispatcher.event_listeners_slice); listener_index++ {
-			match, match_error_report = event_dispatcher.event_listeners_slice[listener_index].key.Match( event.name );
-			if( match_error_report.NoError() == true ){
-				if( match == true ){
-					if( event_dispatcher.event_listeners_slice[listener_index].async == true ){
-						go event_dispatcher.event_listeners_slice[listener_index].function( event );
-					} else{
-						event_dispatcher.event_listeners_slice[listener_index].function( event );
-					}
-				}
+		insert, function_return = event_dispatcher.reserveSpace_Unsafe( ctx );
+	}
+	if( insert == true ){
+		if( event_dispatcher.add_times == true ){
+			event = event.withDatum( "submission_time", time.Now() );
+		}
+		event = inheritEnvelope( ctx, event );
+		event.ctx = detachContext( ctx );
+		function_return = event_dispatcher.logEvent_Unsafe( &event, function_return );
+		if( function_return.NoError() == true ){
+			if( function_return.Data["drop_oldest"] == true ){
+				event_dispatcher.dropOldest_Unsafe();
+			}
+			if( replace == true ){
+				event_dispatcher.acknowledgeEvent( event_dispatcher.events_queue.Remove( int(index) ) );
+				event_dispatcher.events_queue.Insert( int(index), event );
 			} else{
-				listener_index_string = strconv.FormatUint( uint64(listener_index), 10 );
-				return_report.Code = ERROR_CODE_MATCH_ERROR;
-				errors_interface = return_report.Data["errors"];
-				errors_int = errors_interface.(int);
-				errors_int++;
-				return_report.Data["errors"] = errors_int;
-				return_report.Data[listener_index_string] = match_error_report;
+				event_dispatcher.events_queue.PushBack( event );
 			}
+			event_dispatcher.wake_Unsafe();
 		}
 	}
+	if( function_return.NoError() == true ){
+		return_report = error_report.New( 0, map[string]interface{}{ "events_slice_length": event_dispatcher.getEventsQueue_Unsafe().Length(), "dropped": function_return.Data["dropped"] }, nil );
+	} else{
+		return_report = function_return;
+	}
 	event_dispatcher.mutex.Unlock();
 	//Return
 	return return_report;
-} */
+}
 
 /**
-* @fn PushEvent
-* @brief Adds an event to the end of the event queue.
+* @fn Publish
+* @brief Publishes the given event according to how the dispatcher was created: transmitted immediately if unbuffered, queued if buffered.
 * @struct event_dispatcher *EventDispatcher_struct
-* @param event Event_struct [in] The event to be added to the end of the queue.
+* @param event Event_struct [in] The event to be published.
 * @return ( return_report error_report.ErrorReport_struct ) 
 * @retval 0 Success
 * @retval 1 Not Supported
 * @retval >1 Error
 */
 
-// PushEvent adds an event to the end of the event queue.
-func (event_dispatcher *EventDispatcher_struct) PushEvent( event Event_struct ) ( return_report error_report.ErrorReport_struct ){
+// Publish publishes the given event according to how the dispatcher was created; see `PublishCtx`. Within a listener, publish with `PublishCtx( event.Context(), new_event )` instead so the new event inherits the correlation ID of the one being handled.
+func (event_dispatcher *EventDispatcher_struct) Publish( event Event_struct ) ( return_report error_report.ErrorReport_struct ){
 	//Variables
 	//Parametres
 	//Function
-	event_dispatcher.mutex.Lock();
-	if( event_dispatcher.add_times == true ){
-		event.data["submission_time"] = time.Now();
-	}
-	event_dispatcher.events_slice = append(event_dispatcher.events_slice, event);
-	return_report = error_report.New( 0, map[string]interface{}{ "new_length": len(event_dispatcher.events_slice) }, nil );
-	event_dispatcher.mutex.Unlock();
+	return_report = event_dispatcher.PublishCtx( event.Context(), event );
 	//Return
 	return return_report;
 }
 
 /**
-* @fn PopEvent
-* @brief Returns the last event in the queue.
-* @struct event_dispatcher *EventDispatcher
+* @fn PublishCtx
+* @brief Publishes the given event according to how the dispatcher was created: transmitted immediately if unbuffered, queued if buffered.
+* @struct event_dispatcher *EventDispatcher_struct
+* @param ctx context.Context [in] Passed on to the listeners as described for `ProcessEventCtx` and `PushEventCtx`.
+* @param event Event_struct [in] The event to be published.
 * @return ( return_report error_report.ErrorReport_struct ) 
 * @retval 0 Success
 * @retval 1 Not Supported
 * @retval >1 Error
 */
 
-// PopEvent returns the last event in the queue.
-func (event_dispatcher *EventDispatcher_struct) PopEvent() ( return_report error_report.ErrorReport_struct ){
+// PublishCtx publishes the given event according to how the dispatcher was created, so callers needn't know whether it's buffered. Unbuffered, it's `ProcessEventCtx`. Buffered, it's `PushEventCtx` followed, if `SetAutoDrain` is on and the run loop isn't running, by processing the queue in the caller's goroutine. The report's "queued" datum says whether the event was queued and its "errors" datum counts the failures seen by this call: listener and match errors when unbuffered, failed events when draining.
+func (event_dispatcher *EventDispatcher_struct) PublishCtx( ctx context.Context, event Event_struct ) ( return_report error_report.ErrorReport_struct ){
 	//Variables
-	var event Event_struct;
+	var buffered bool;
+	var auto_drain bool;
+	var running bool;
 	//Parametres
-	//Function
 	event_dispatcher.mutex.Lock();
-	event = event_dispatcher.events_slice[(len(event_dispatcher.events_slice) - 1)];
-	event_dispatcher.events_slice = event_dispatcher.events_slice[:(len(event_dispatcher.events_slice) - 1)];
+	buffered = event_dispatcher.buffered;
+	auto_drain = event_dispatcher.auto_drain;
 	event_dispatcher.mutex.Unlock();
-	return_report = error_report.New( 0, map[string]interface{}{ "event": event }, nil );
+	//Function
+	if( buffered == false ){
+		return_report = event_dispatcher.ProcessEventCtx( ctx, event );
+		return_report.Data["queued"] = false;
+	} else{
+		return_report = event_dispatcher.PushEventCtx( ctx, event );
+		if( return_report.NoError() == true ){
+			return_report.Data["queued"] = true;
+			return_report.Data["errors"] = 0;
+			if( auto_drain == true ){
+				event_dispatcher.run_mutex.Lock();
+				running = event_dispatcher.running;
+				event_dispatcher.run_mutex.Unlock();
+				if( running == false ){
+					return_report = event_dispatcher.autoDrain();
+					return_report.Data["queued"] = true;
+				}
+			}
+		}
+	}
 	//Return
 	return return_report;
 }
 
 /**
-* @fn ShiftEvent
-* @brief Extracts and returns the first event in the queue.
-* @struct event_dispatcher EventDispatcher_struct
-* @return ( return_report error_report.ErrorReport_struct ) 
-* @retval 0 Success
-* @retval 1 Not Supported
-* @retval >1 Error
+* @fn SetAutoDrain
+* @brief Sets whether `Publish` on a buffered dispatcher processes the queue straight away when the run loop isn't running.
+* @struct event_dispatcher *EventDispatcher_struct
+* @param auto_drain bool [in] Off by default, leaving queued events for `ProcessEvents` or the run loop.
 */
 
-// ShiftEvent extracts and returns the first event in the queue.
-func (event_dispatcher EventDispatcher_struct) ShiftEvent() ( return_report error_report.ErrorReport_struct ){
+// SetAutoDrain sets whether `Publish` on a buffered dispatcher processes the queue straight away when the run loop isn't running. Off by default, leaving queued events for `ProcessEvents` or the run loop.
+func (event_dispatcher *EventDispatcher_struct) SetAutoDrain( auto_drain bool ){
 	//Variables
-	var function_return error_report.ErrorReport_struct;
 	//Parametres
 	//Function
-	function_return = event_dispatcher.ExtractEventByIndex( 0 );
-	if( function_return.NoError() == true ){
-		return_report = error_report.New( 0, map[string]interface{}{ "event": function_return.Data["event"] }, &function_return );
-	} else{
-		return_report = error_report.New( ERROR_CODE_SUBORDINATE_FUNCTION_ERROR, map[string]interface{}{}, &function_return );
-	}
+	event_dispatcher.mutex.Lock();
+	event_dispatcher.auto_drain = auto_drain;
+	event_dispatcher.mutex.Unlock();
 	//Return
-	return return_report;
 }
 
 /**
-* @fn ProcessEvent
-* @brief Transmits the given event.
-* @struct event_dispatcher EventDispatcher_struct
-* @param event Event_struct [in] The event to be processed.
+* @fn PushEvent
+* @brief Adds an event to the end of the event queue.
+* @struct event_dispatcher *EventDispatcher_struct
+* @param event Event_struct [in] The event to be added to the end of the queue.
 * @return ( return_report error_report.ErrorReport_struct ) 
 * @retval 0 Success
 * @retval 1 Not Supported
 * @retval >1 Error
 */
 
-// ProcessEvent transmits the given event.
-func (event_dispatcher EventDispatcher_struct) ProcessEvent( event Event_struct ) ( return_report error_report.ErrorReport_struct ){
+// PushEvent adds an event to the end of the event queue, keeping any context it was published with. A full bounded queue is handled as described for `PushEventCtx`.
+func (event_dispatcher *EventDispatcher_struct) PushEvent( event Event_struct ) ( return_report error_report.ErrorReport_struct ){
 	//Variables
-	var function_return error_report.ErrorReport_struct;
 	//Parametres
-	event_dispatcher.mutex.Lock();
-	function_return = event_dispatcher.ProcessEvent_Unsafe( event );
-	if( function_return.IsError() == true ){
-		return_report = error_report.New( ERROR_CODE_EVENT_PROCESSING_ERROR, map[string]interface{}{ "event": event }, &function_return );
-	}
-	event_dispatcher.mutex.Unlock();
+	//Function
+	return_report = event_dispatcher.PushEventCtx( event.Context(), event );
 	//Return
 	return return_report;
 }
 
 /**
-* @fn ProcessEvent_Unsafe
-* @brief Actually transmits the event.
-* @struct event_dispatcher EventDispatcher_struct
-* @param event Event_struct [in] The event to be transmitted.
+* @fn PushEventCtx
+* @brief Adds an event to the end of the event queue, applying the overflow strategy set by `SetQueueCapacity` if the queue is full.
+* @struct event_dispatcher *EventDispatcher_struct
+* @param ctx context.Context [in] Bounds how long to wait for room in a queue using `OVERFLOW_STRATEGY_BLOCK`. Its values, but not its cancellation or deadline, are passed on to the listeners when the event is processed.
+* @param event Event_struct [in] The event to be added to the end of the queue.
 * @return ( return_report error_report.ErrorReport_struct ) 
-* @retval 0 Success
+* @retval 0 Success; the "dropped" datum is true if `OVERFLOW_STRATEGY_DROP_NEWEST` discarded the event.
 * @retval 1 Not Supported
 * @retval >1 Error
 */
 
-// ProcessEvent_Unsafe actually transmits the event.
-func (event_dispatcher EventDispatcher_struct) ProcessEvent_Unsafe( event Event_struct ) ( return_report error_report.ErrorReport_struct ){
+// PushEventCtx adds an event to the end of the event queue, applying the overflow strategy set by `SetQueueCapacity` if the queue is full.
+func (event_dispatcher *EventDispatcher_struct) PushEventCtx( ctx context.Context, event Event_struct ) ( return_report error_report.ErrorReport_struct ){
 	//Variables
-	var i int; //Event listener index
-	var match bool;
-	var index_string string;
 	var function_return error_report.ErrorReport_struct;
+	var insert bool;
 	//Parametres
-	if( event_dispatcher.add_times == true ){
-		event.data["transmission_time"] = time.Now();
+	if( event_dispatcher.queue_backend != nil ){
+		return event_dispatcher.enqueueBackendEvent( ctx, event, -1 );
 	}
 	//Function
-	for i = 0; i < len(event_dispatcher.event_listeners_slice); i++ {
-		match, function_return = event_dispatcher.event_listeners_slice[i].key.Match( event.name );
+	event_dispatcher.mutex.Lock();
+	insert, function_return = event_dispatcher.reserveSpace_Unsafe( ctx );
+	if( insert == true ){
+		if( event_dispatcher.add_times == true ){
+			event = event.withDatum( "submission_time", time.Now() );
+		}
+		event = inheritEnvelope( ctx, event );
+		event.ctx = detachContext( ctx );
+		function_return = event_dispatcher.logEvent_Unsafe( &event, function_return );
 		if( function_return.NoError() == true ){
-			if( match == true ){
-				if( event_dispatcher.event_listeners_slice[i].async == true ){
-					go event_dispatcher.event_listeners_slice[i].function( event );
-				} else{
-					event_dispatcher.event_listeners_slice[i].function( event );
-				}
-			}
-		} else{
-			index_string = strconv.FormatUint( uint64(i), 10 );
-			if( return_report.CodeEqual( 0 ) == true ){
-				return_report = error_report.New( ERROR_CODE_EVENT_LISTENER_MATCH, map[string]interface{}{ "message": "Matching against an event listener returned an error.", index_string: function_return }, nil);
-			} else{
-				return_report.Data[index_string] = function_return;
+			if( function_return.Data["drop_oldest"] == true ){
+				event_dispatcher.dropOldest_Unsafe();
 			}
+			event_dispatcher.getEventsQueue_Unsafe().PushBack( event );
+			event_dispatcher.wake_Unsafe();
 		}
 	}
+	if( function_return.NoError() == true ){
+		return_report = error_report.New( 0, map[string]interface{}{ "new_length": event_dispatcher.getEventsQueue_Unsafe().Length(), "dropped": function_return.Data["dropped"] }, nil );
+	} else{
+		return_report = function_return;
+	}
+	event_dispatcher.mutex.Unlock();
 	//Return
 	return return_report;
 }
 
 /**
-* @fn ProcessEvents
-* @brief Processes all of the events in the queue.
-* @struct event_dispatcher EventDispatcher_struct
-* @return ( return_report error_report.ErrorReport_struct ) 
-* @retval 0 Success
+* @fn PopEvent
+* @brief Returns the last event in the queue.
+* @struct event_dispatcher *EventDispatcher_struct
+* @return ( return_report error_report.ErrorReport_struct ) 
+* @retval 0 Success
+* @retval 1 Not Supported
+* @retval >1 Error
+*/
+
+// PopEvent returns the last event in the queue.
+func (event_dispatcher *EventDispatcher_struct) PopEvent() ( return_report error_report.ErrorReport_struct ){
+	//Variables
+	var event Event_struct;
+	//Parametres
+	if( event_dispatcher.queue_backend != nil ){
+		return error_report.New( 1, map[string]interface{}{ "message": "Queue backends can't pop the last event." }, nil );
+	}
+	//Function
+	event_dispatcher.mutex.Lock();
+	if( event_dispatcher.getEventsQueue_Unsafe().Length() > 0 ){
+		event = event_dispatcher.events_queue.PopBack();
+		event_dispatcher.signalSpace_Unsafe();
+		return_report = error_report.New( 0, map[string]interface{}{ "event": event }, nil );
+	} else{
+		return_report = error_report.New( ERROR_CODE_INDEX_OUT_OF_RANGE, map[string]interface{}{ "message": "The event queue is empty.", "events_slice_length": 0 }, nil );
+	}
+	event_dispatcher.mutex.Unlock();
+	//Return
+	return return_report;
+}
+
+/**
+* @fn ShiftEvent
+* @brief Extracts and returns the first event in the queue.
+* @struct event_dispatcher *EventDispatcher_struct
+* @return ( return_report error_report.ErrorReport_struct ) 
+* @retval 0 Success
+* @retval 1 Not Supported
+* @retval >1 Error
+*/
+
+// ShiftEvent extracts and returns the first event in the queue.
+func (event_dispatcher *EventDispatcher_struct) ShiftEvent() ( return_report error_report.ErrorReport_struct ){
+	//Variables
+	var event Event_struct;
+	var range_error_report error_report.ErrorReport_struct;
+	//Parametres
+	if( event_dispatcher.queue_backend != nil ){
+		return event_dispatcher.dequeueBackendEvent();
+	}
+	//Function
+	event_dispatcher.mutex.Lock();
+	if( event_dispatcher.getEventsQueue_Unsafe().Length() > 0 ){
+		event = event_dispatcher.events_queue.PopFront();
+		event_dispatcher.signalSpace_Unsafe();
+		return_report = error_report.New( 0, map[string]interface{}{ "event": event, "events_slice_length": event_dispatcher.events_queue.Length() }, nil );
+	} else{
+		range_error_report = error_report.New( ERROR_CODE_INDEX_OUT_OF_RANGE, map[string]interface{}{ "message": "The event queue is empty.", "events_slice_length": 0 }, nil );
+		return_report = error_report.New( ERROR_CODE_SUBORDINATE_FUNCTION_ERROR, map[string]interface{}{}, &range_error_report );
+	}
+	event_dispatcher.mutex.Unlock();
+	//Return
+	return return_report;
+}
+
+/**
+* @fn ProcessEvent
+* @brief Transmits the given event.
+* @struct event_dispatcher *EventDispatcher_struct
+* @param event Event_struct [in] The event to be processed.
+* @return ( return_report error_report.ErrorReport_struct ) 
+* @retval 0 Success
+* @retval 1 Not Supported
+* @retval >1 Error
+*/
+
+// ProcessEvent transmits the given event. The listeners are called without holding the dispatcher's lock so they may add and remove listeners or publish further events. The returned report's "propagation_stopped", "stopped_by" (the ID of the listener which called `StopPropagation`), and "default_prevented" data describe the outcome.
+func (event_dispatcher *EventDispatcher_struct) ProcessEvent( event Event_struct ) ( return_report error_report.ErrorReport_struct ){
+	//Variables
+	var function_return error_report.ErrorReport_struct;
+	var dispatch_snapshot dispatch_snapshot_struct;
+	//Parametres
+	event_dispatcher.mutex.Lock();
+	dispatch_snapshot = event_dispatcher.snapshotDispatch_Unsafe();
+	event_dispatcher.mutex.Unlock();
+	//Function
+	function_return = event_dispatcher.processEvent( event, dispatch_snapshot );
+	if( function_return.IsError() == true ){
+		return_report = error_report.New( ERROR_CODE_EVENT_PROCESSING_ERROR, map[string]interface{}{ "event": event, "errors": function_return.Data["errors"], "propagation_stopped": function_return.Data["propagation_stopped"], "stopped_by": function_return.Data["stopped_by"], "default_prevented": function_return.Data["default_prevented"] }, &function_return );
+	} else{
+		return_report = function_return;
+		return_report.Data["event"] = event;
+	}
+	//Return
+	return return_report;
+}
+
+/**
+* @fn ProcessEvent_Unsafe
+* @brief Processes the event like `ProcessEvent` but without taking the dispatcher's lock, returning the report of the transmission itself; the caller must hold `mutex` or otherwise guarantee no concurrent changes.
+* @struct event_dispatcher *EventDispatcher_struct
+* @param event Event_struct [in] The event to be transmitted.
+* @return ( return_report error_report.ErrorReport_struct ) 
+* @retval 0 Success
+* @retval 1 Not Supported
+* @retval >1 Error
+*/
+
+// ProcessEvent_Unsafe processes the event like `ProcessEvent`, recording, dead-lettering, and acknowledging it, but without taking the dispatcher's lock, and returns the report of the transmission itself. The caller must hold `mutex` or otherwise guarantee no concurrent changes; nothing it calls takes `mutex`, so holding it is safe, though listeners which add listeners or publish will then block.
+func (event_dispatcher *EventDispatcher_struct) ProcessEvent_Unsafe( event Event_struct ) ( return_report error_report.ErrorReport_struct ){
+	//Variables
+	//Parametres
+	//Function
+	return_report = event_dispatcher.processEvent( event, event_dispatcher.snapshotDispatch_Unsafe() );
+	//Return
+	return return_report;
+}
+
+/**
+* @fn ProcessEvents
+* @brief Processes all of the events in the queue.
+* @struct event_dispatcher *EventDispatcher_struct
+* @return ( return_report error_report.ErrorReport_struct ) 
+* @retval 0 Success
 * @retval 1 Not Supported
 * @retval >1 Error
 */
 
 // ProcessEvents processes all of the events in the queue.
-func (event_dispatcher EventDispatcher_struct) ProcessEvents() ( return_report error_report.ErrorReport_struct ){
+func (event_dispatcher *EventDispatcher_struct) ProcessEvents() ( return_report error_report.ErrorReport_struct ){
 	//Variables
 	var function_return error_report.ErrorReport_struct;
 	var event Event_struct;
+	var processed int;
+	var errors int;
+	var error_reports []error_report.ErrorReport_struct;
 	//Parametres
 	//Function
-	function_return = event_dispatcher.ShiftEvent();
-	if( function_return.NoError() == true ){
+	for function_return = event_dispatcher.ShiftEvent(); function_return.NoError() == true; function_return = event_dispatcher.ShiftEvent() {
 		event = function_return.Data["event"].(Event_struct);
 		function_return = event_dispatcher.ProcessEvent( event );
-		if( function_return.NoError() == true ){
-			return_report = error_report.New( 0, map[string]interface{}{ "event": event }, nil );
-		} else{
-			return_report = error_report.New( ERROR_CODE_EVENT_PROCESSING_ERROR, map[string]interface{}{}, &function_return );
+		processed++;
+		if( function_return.IsError() == true ){
+			errors++;
+			error_reports = append(error_reports, function_return);
 		}
+	}
+	if( errors == 0 ){
+		return_report = error_report.New( 0, map[string]interface{}{ "processed": processed, "errors": errors }, nil );
 	} else{
-		return_report = error_report.New( ERROR_CODE_SUBORDINATE_FUNCTION_ERROR, map[string]interface{}{}, &function_return );
+		function_return = error_reports[(errors - 1)];
+		return_report = error_report.New( ERROR_CODE_EVENT_PROCESSING_ERROR, map[string]interface{}{ "processed": processed, "errors": errors, "error_reports": error_reports }, &function_return );
 	}
 	//Return
 	return return_report;
 }
 
+/**
+* @fn SetErrorSink
+* @brief Sets the function which receives error reports nobody else can: errors returned by asynchronous listeners and processing errors in the run loop.
+* @struct event_dispatcher *EventDispatcher_struct
+* @param error_sink func( report error_report.ErrorReport_struct ) [in] The function to receive the reports; nil discards them.
+* @return ( return_report error_report.ErrorReport_struct ) 
+* @retval 0 Success
+* @retval 1 Not Supported
+* @retval >1 Error
+*/
+
+// SetErrorSink sets the function which receives error reports nobody else can: errors returned by asynchronous listeners and processing errors in the run loop.
+func (event_dispatcher *EventDispatcher_struct) SetErrorSink( error_sink func( report error_report.ErrorReport_struct ) ) ( return_report error_report.ErrorReport_struct ){
+	//Variables
+	//Parametres
+	//Function
+	event_dispatcher.mutex.Lock();
+	event_dispatcher.error_sink = error_sink;
+	event_dispatcher.mutex.Unlock();
+	return_report = error_report.New( 0, map[string]interface{}{}, nil );
+	//Return
+	return return_report;
+}
+
+/**
+* @fn SetPanicHook
+* @brief Sets the function which receives an error report, with the stack trace, listener ID, and event name, whenever a listener panics.
+* @struct event_dispatcher *EventDispatcher_struct
+* @param panic_hook func( report error_report.ErrorReport_struct ) [in] The function to receive the reports; if nil, they go to the error sink instead.
+* @return ( return_report error_report.ErrorReport_struct ) 
+* @retval 0 Success
+* @retval 1 Not Supported
+* @retval >1 Error
+*/
+
+// SetPanicHook sets the function which receives an error report, with the stack trace, listener ID, and event name, whenever a listener panics.
+func (event_dispatcher *EventDispatcher_struct) SetPanicHook( panic_hook func( report error_report.ErrorReport_struct ) ) ( return_report error_report.ErrorReport_struct ){
+	//Variables
+	//Parametres
+	//Function
+	event_dispatcher.mutex.Lock();
+	event_dispatcher.panic_hook = panic_hook;
+	event_dispatcher.mutex.Unlock();
+	return_report = error_report.New( 0, map[string]interface{}{}, nil );
+	//Return
+	return return_report;
+}
+
+/**
+* @fn SetDrainOnStop
+* @brief Sets whether the run loop processes or drops the events still queued when it is stopped.
+* @struct event_dispatcher *EventDispatcher_struct
+* @param drain_on_stop bool [in] `true` to process the remaining events before the run loop exits; `false` to drop them.
+* @return ( return_report error_report.ErrorReport_struct ) 
+* @retval 0 Success
+* @retval 1 Not Supported
+* @retval >1 Error
+*/
+
+// SetDrainOnStop sets whether the run loop processes or drops the events still queued when it is stopped.
+func (event_dispatcher *EventDispatcher_struct) SetDrainOnStop( drain_on_stop bool ) ( return_report error_report.ErrorReport_struct ){
+	//Variables
+	//Parametres
+	//Function
+	event_dispatcher.run_mutex.Lock();
+	event_dispatcher.drain_on_stop = drain_on_stop;
+	event_dispatcher.run_mutex.Unlock();
+	return_report = error_report.New( 0, map[string]interface{}{ "drain_on_stop": drain_on_stop }, nil );
+	//Return
+	return return_report;
+}
+
+/**
+* @fn Start
+* @brief Starts a goroutine which processes queued events as they arrive until `Stop` is called or `ctx` is done.
+* @struct event_dispatcher *EventDispatcher_struct
+* @param ctx context.Context [in] Cancelling this context stops the run loop as if `Stop` had been called.
+* @return ( return_report error_report.ErrorReport_struct ) 
+* @retval 0 Success
+* @retval 1 Not Supported
+* @retval >1 Error
+*/
+
+// Start starts a goroutine which processes queued events as they arrive until `Stop` is called or `ctx` is done.
+func (event_dispatcher *EventDispatcher_struct) Start( ctx context.Context ) ( return_report error_report.ErrorReport_struct ){
+	//Variables
+	var wake_channel chan struct{};
+	//Parametres
+	if( ctx == nil ){
+		ctx = context.Background();
+	}
+	//Function
+	event_dispatcher.run_mutex.Lock();
+	if( event_dispatcher.running == true && event_dispatcher.runLoopDone_Unsafe() == false ){
+		return_report = error_report.New( ERROR_CODE_ALREADY_RUNNING, map[string]interface{}{ "message": "The event dispatcher's run loop is already running." }, nil );
+	} else{
+		event_dispatcher.mutex.Lock();
+		wake_channel = event_dispatcher.getWakeChannel_Unsafe();
+		event_dispatcher.mutex.Unlock();
+		event_dispatcher.running = true;
+		event_dispatcher.stop_channel = make(chan struct{});
+		event_dispatcher.done_channel = make(chan struct{});
+		event_dispatcher.run_report = error_report.ERROR_REPORT_NIL_VALUE;
+		go event_dispatcher.runLoop( ctx, wake_channel, event_dispatcher.stop_channel, event_dispatcher.done_channel, event_dispatcher.drain_on_stop );
+		return_report = error_report.New( 0, map[string]interface{}{}, nil );
+	}
+	event_dispatcher.run_mutex.Unlock();
+	//Return
+	return return_report;
+}
+
+/**
+* @fn Stop
+* @brief Stops the run loop started by `Start` and waits for it to exit, draining or dropping the remaining events as set by `SetDrainOnStop`.
+* @struct event_dispatcher *EventDispatcher_struct
+* @return ( return_report error_report.ErrorReport_struct ) 
+* @retval 0 Success
+* @retval 1 Not Supported
+* @retval >1 Error
+*/
+
+// Stop stops the run loop started by `Start` and waits for it to exit, draining or dropping the remaining events as set by `SetDrainOnStop`. Retries still waiting out their backoff are cancelled. `run_mutex` isn't held while waiting, so the loop's listeners may call `IsRunning`, `SetDrainOnStop`, or `Publish`.
+func (event_dispatcher *EventDispatcher_struct) Stop() ( return_report error_report.ErrorReport_struct ){
+	//Variables
+	var done_channel chan struct{};
+	//Parametres
+	//Function
+	event_dispatcher.cancelRetries();
+	event_dispatcher.run_mutex.Lock();
+	if( event_dispatcher.running == false ){
+		event_dispatcher.run_mutex.Unlock();
+		return error_report.New( ERROR_CODE_NOT_RUNNING, map[string]interface{}{ "message": "The event dispatcher's run loop is not running." }, nil );
+	}
+	done_channel = event_dispatcher.done_channel;
+	///Another `Stop` may have closed it already.
+	select{
+		case <-event_dispatcher.stop_channel:
+		default:
+			close(event_dispatcher.stop_channel);
+	}
+	event_dispatcher.run_mutex.Unlock();
+	<-done_channel;
+	event_dispatcher.run_mutex.Lock();
+	///Unless `Start` began another run loop meanwhile.
+	if( event_dispatcher.done_channel == done_channel ){
+		event_dispatcher.running = false;
+		return_report = event_dispatcher.run_report;
+	} else{
+		return_report = error_report.New( 0, map[string]interface{}{}, nil );
+	}
+	event_dispatcher.run_mutex.Unlock();
+	//Return
+	return return_report;
+}
+
+/**
+* @fn IsRunning
+* @brief Returns true if the run loop started by `Start` is still processing events.
+* @struct event_dispatcher *EventDispatcher_struct
+* @return bool
+* @retval true The run loop is running.
+* @retval false The run loop was never started, has been stopped, or exited because its context was done.
+*/
+
+// IsRunning returns true if the run loop started by `Start` is still processing events.
+func (event_dispatcher *EventDispatcher_struct) IsRunning() bool{
+	//Variables
+	var _return bool;
+	//Parametres
+	//Function
+	event_dispatcher.run_mutex.Lock();
+	_return = ( event_dispatcher.running == true && event_dispatcher.runLoopDone_Unsafe() == false );
+	event_dispatcher.run_mutex.Unlock();
+	//Return
+	return _return;
+}
+
+/**
+* @fn runLoop
+* @brief The body of the goroutine started by `Start`: processes events whenever it is woken until it is stopped.
+* @struct event_dispatcher *EventDispatcher_struct
+* @param ctx context.Context [in] The context given to `Start`.
+* @param wake_channel chan struct{} [in] Signalled whenever an event is queued.
+* @param stop_channel chan struct{} [in] Closed by `Stop`.
+* @param done_channel chan struct{} [in] Closed by this function once it has finished.
+* @param drain_on_stop bool [in] Whether to process or drop the remaining events on exit.
+*/
+
+// runLoop is the body of the goroutine started by `Start`: processes events whenever it is woken until it is stopped.
+func (event_dispatcher *EventDispatcher_struct) runLoop( ctx context.Context, wake_channel chan struct{}, stop_channel chan struct{}, done_channel chan struct{}, drain_on_stop bool ){
+	//Variables
+	var processed int;
+	var dropped int;
+	var function_return error_report.ErrorReport_struct;
+	var stopping bool;
+	//Parametres
+	//Function
+	for stopping == false {
+		function_return = event_dispatcher.ProcessEvents();
+		processed += function_return.Data["processed"].(int);
+		if( function_return.IsError() == true ){
+			event_dispatcher.sinkError( function_return );
+		}
+		select{
+			case <-wake_channel:
+			case <-stop_channel:
+				stopping = true;
+			case <-ctx.Done():
+				stopping = true;
+		}
+	}
+	if( drain_on_stop == true ){
+		function_return = event_dispatcher.ProcessEvents();
+		processed += function_return.Data["processed"].(int);
+		if( function_return.IsError() == true ){
+			event_dispatcher.sinkError( function_return );
+		}
+	} else{
+		event_dispatcher.mutex.Lock();
+		dropped = event_dispatcher.getEventsQueue_Unsafe().Length();
+		event_dispatcher.events_queue.Clear();
+		event_dispatcher.signalSpace_Unsafe();
+		event_dispatcher.mutex.Unlock();
+	}
+	event_dispatcher.run_report = error_report.New( 0, map[string]interface{}{ "processed": processed, "dropped": dropped }, nil );
+	close(done_channel);
+	//Return
+}
+
+/**
+* @fn runLoopDone_Unsafe
+* @brief Returns true if the most recently started run loop has exited; the caller must hold `run_mutex`.
+* @struct event_dispatcher *EventDispatcher_struct
+* @return bool
+*/
+
+// runLoopDone_Unsafe returns true if the most recently started run loop has exited; the caller must hold `run_mutex`.
+func (event_dispatcher *EventDispatcher_struct) runLoopDone_Unsafe() bool{
+	//Variables
+	var _return bool;
+	//Parametres
+	//Function
+	select{
+		case <-event_dispatcher.done_channel:
+			_return = true;
+		default:
+			_return = false;
+	}
+	//Return
+	return _return;
+}
+
+/**
+* @fn getEventsQueue_Unsafe
+* @brief Returns the event queue, creating it if needed; the caller must hold `mutex`.
+* @struct event_dispatcher *EventDispatcher_struct
+* @return event_queue_interface
+*/
+
+// getEventsQueue_Unsafe returns the event queue, creating it if needed; the caller must hold `mutex`.
+func (event_dispatcher *EventDispatcher_struct) getEventsQueue_Unsafe() event_queue_interface{
+	//Variables
+	//Parametres
+	//Function
+	if( event_dispatcher.events_queue == nil ){
+		event_dispatcher.events_queue = newRingBuffer();
+	}
+	//Return
+	return event_dispatcher.events_queue;
+}
+
+/**
+* @fn getWakeChannel_Unsafe
+* @brief Returns the channel used to wake the run loop, creating it if needed; the caller must hold `mutex`.
+* @struct event_dispatcher *EventDispatcher_struct
+* @return chan struct{}
+*/
+
+// getWakeChannel_Unsafe returns the channel used to wake the run loop, creating it if needed; the caller must hold `mutex`.
+func (event_dispatcher *EventDispatcher_struct) getWakeChannel_Unsafe() chan struct{}{
+	//Variables
+	//Parametres
+	//Function
+	if( event_dispatcher.wake_channel == nil ){
+		event_dispatcher.wake_channel = make(chan struct{}, 1);
+	}
+	//Return
+	return event_dispatcher.wake_channel;
+}
+
+/**
+* @fn wake_Unsafe
+* @brief Signals the run loop, if any, that there are events to process without ever blocking; the caller must hold `mutex`.
+* @struct event_dispatcher *EventDispatcher_struct
+*/
+
+// wake_Unsafe signals the run loop, if any, that there are events to process without ever blocking; the caller must hold `mutex`.
+func (event_dispatcher *EventDispatcher_struct) wake_Unsafe(){
+	//Variables
+	//Parametres
+	//Function
+	select{
+		case event_dispatcher.getWakeChannel_Unsafe() <- struct{}{}:
+		default:
+	}
+	//Return
+}
+
 
 /*type EventEmitter_struct struct{
 	events_slice []Event_struct
@@ -534,21 +1325,22 @@ var(
 * @fn NewEvent
 * @brief Creates a new event.
 * @param name string [in] The name of the event.
-* @param data map[string]interface{} [in] A string-keyed map of extra data contained in the event.
+* @param data map[string]interface{} [in] A string-keyed map of extra data contained in the event; copied, so later changes to it don't affect the event.
 * @return ( return_report error_report.ErrorReport_struct ) 
 * @retval 0 Success
 * @retval 1 Not Supported
 * @retval >1 Error
 */
 
-// NewEvent creates a new event.
+// NewEvent creates a new event with a copy of the given data.
 func NewEvent( name string, data map[string]interface{} ) ( return_report error_report.ErrorReport_struct ){
 	//Variables
 	var event Event_struct;
 	//Parametres
 	//Function
+	event.id = newEventID();
 	event.name = name;
-	event.data = data;
+	event.data = copyData( data );
 	event.data["creation_time"] = time.Now();
 	return_report = error_report.New( 0, map[string]interface{}{ "event": event }, nil );
 	//Return
@@ -559,7 +1351,7 @@ func NewEvent( name string, data map[string]interface{} ) ( return_report error_
 * @fn NewEventListener
 * @brief Creates a new event listener.
 * @param key matchkey.Matchkey_struct [in] The Matchkey_struct to trigger the event listener.
-* @param async bool [in] A boolean expressing whether the event listner function should be called in its own go routine.
+* @param async bool [in] A boolean expressing whether the event listner function should be called in its own go routine, or on the worker pool if `SetWorkerPoolSize` has set one.
 * @param function func( event Event_struct, args ...interface{}) [in] The function to be called when the event matches the matchkey.
 * @return ( return_report error_report.ErrorReport_struct ) 
 * @retval 0 Success
@@ -569,6 +1361,77 @@ func NewEvent( name string, data map[string]interface{} ) ( return_report error_
 
 // NewEventListener creates a new event listener.
 func NewEventListener( key matchkey.MatchKey_struct, async bool, function func( event Event_struct, args ...interface{}) ) ( return_report error_report.ErrorReport_struct ){
+	//Variables
+	//Parametres
+	//Function
+	return_report = NewPriorityEventListener( key, async, 0, function );
+	//Return
+	return return_report;
+}
+
+/**
+* @fn NewPriorityEventListener
+* @brief Creates a new event listener with the given priority: listeners with a higher priority are called first, and listeners with equal priority are called in the order they were added.
+* @param key matchkey.Matchkey_struct [in] The Matchkey_struct to trigger the event listener.
+* @param async bool [in] A boolean expressing whether the event listner function should be called in its own go routine, or on the worker pool if `SetWorkerPoolSize` has set one.
+* @param priority int64 [in] The listener's priority; `NewEventListener` uses 0.
+* @param function func( event Event_struct, args ...interface{}) [in] The function to be called when the event matches the matchkey.
+* @return ( return_report error_report.ErrorReport_struct ) 
+* @retval 0 Success
+* @retval 1 Not Supported
+* @retval >1 Error
+*/
+
+// NewPriorityEventListener creates a new event listener with the given priority: listeners with a higher priority are called first, and listeners with equal priority are called in the order they were added.
+func NewPriorityEventListener( key matchkey.MatchKey_struct, async bool, priority int64, function func( event Event_struct, args ...interface{}) ) ( return_report error_report.ErrorReport_struct ){
+	//Variables
+	//Parametres
+	//Function
+	return_report = NewFallibleEventListener( key, async, priority, AdaptEventListenerFunction( function ) );
+	//Return
+	return return_report;
+}
+
+/**
+* @fn NewFallibleEventListener
+* @brief Creates a new event listener whose function returns an error; errors from synchronous listeners are collected into `ProcessEvent`'s report and errors from asynchronous listeners are sent to the dispatcher's error sink.
+* @param key matchkey.Matchkey_struct [in] The Matchkey_struct to trigger the event listener.
+* @param async bool [in] A boolean expressing whether the event listner function should be called in its own go routine, or on the worker pool if `SetWorkerPoolSize` has set one.
+* @param priority int64 [in] The listener's priority; see `NewPriorityEventListener`.
+* @param function func( event Event_struct, args ...interface{}) error [in] The function to be called when the event matches the matchkey.
+* @return ( return_report error_report.ErrorReport_struct ) 
+* @retval 0 Success
+* @retval 1 Not Supported
+* @retval >1 Error
+*/
+
+// NewFallibleEventListener creates a new event listener whose function returns an error; errors from synchronous listeners are collected into `ProcessEvent`'s report and errors from asynchronous listeners are sent to the dispatcher's error sink.
+func NewFallibleEventListener( key matchkey.MatchKey_struct, async bool, priority int64, function func( event Event_struct, args ...interface{}) error ) ( return_report error_report.ErrorReport_struct ){
+	//Variables
+	//Parametres
+	//Function
+	return_report = NewContextEventListener( key, async, priority, func( ctx context.Context, event Event_struct, args ...interface{} ) error{
+		return function( event, args... );
+	} );
+	//Return
+	return return_report;
+}
+
+/**
+* @fn NewContextEventListener
+* @brief Creates a new event listener whose function also receives the context the event was published with; see `ProcessEventCtx` and `PushEventCtx`.
+* @param key matchkey.Matchkey_struct [in] The Matchkey_struct to trigger the event listener.
+* @param async bool [in] A boolean expressing whether the event listner function should be called in its own go routine, or on the worker pool if `SetWorkerPoolSize` has set one.
+* @param priority int64 [in] The listener's priority; see `NewPriorityEventListener`.
+* @param function func( ctx context.Context, event Event_struct, args ...interface{}) error [in] The function to be called when the event matches the matchkey; `ctx` is the same as `event.Context()`.
+* @return ( return_report error_report.ErrorReport_struct ) 
+* @retval 0 Success
+* @retval 1 Not Supported
+* @retval >1 Error
+*/
+
+// NewContextEventListener creates a new event listener whose function also receives the context the event was published with; see `ProcessEventCtx` and `PushEventCtx`. Events the function publishes with that context inherit the correlation ID of the event being handled. Errors are handled as for `NewFallibleEventListener`.
+func NewContextEventListener( key matchkey.MatchKey_struct, async bool, priority int64, function func( ctx context.Context, event Event_struct, args ...interface{}) error ) ( return_report error_report.ErrorReport_struct ){
 	//Variables
 	var event_listener EventListener_struct;
 	//Parametres
@@ -576,6 +1439,7 @@ func NewEventListener( key matchkey.MatchKey_struct, async bool, function func(
 	if( (key.Matchkey_type > 0) && (key.Matchkey_type <= 3) ){
 		event_listener.key = key;
 		event_listener.async = async;
+		event_listener.priority = priority;
 		event_listener.function = function;
 		return_report = error_report.New( 0, map[string]interface{}{ "event_listener": event_listener }, nil );
 	} else{
@@ -585,11 +1449,30 @@ func NewEventListener( key matchkey.MatchKey_struct, async bool, function func(
 	return return_report;
 }
 
+/**
+* @fn AdaptEventListenerFunction
+* @brief Wraps a listener function which doesn't return anything so it can be used where a function returning an error is expected.
+* @param function func( event Event_struct, args ...interface{}) [in] The function to be wrapped.
+* @return func( event Event_struct, args ...interface{}) error A function which calls `function` and always returns nil.
+*/
+
+// AdaptEventListenerFunction wraps a listener function which doesn't return anything so it can be used where a function returning an error is expected.
+func AdaptEventListenerFunction( function func( event Event_struct, args ...interface{}) ) func( event Event_struct, args ...interface{}) error{
+	//Variables
+	//Parametres
+	//Function
+	//Return
+	return func( event Event_struct, args ...interface{} ) error{
+		function( event, args... );
+		return nil;
+	};
+}
+
 /**
 * @fn NewEventDispatcher
 * @brief Creates a new event dispatcher.
 * @param add_times bool [in] A boolean value representing whether to add submission and transmission times to events.
-* @param buffered bool [in] A boolean value representing whther to queue events and only actually transmit them when `ProcessEvents` is called manually.
+* @param buffered bool [in] A boolean value representing whther to queue events and only actually transmit them when `ProcessEvents` is called manually or the run loop is started with `Start`.
 * @return ( return_report error_report.ErrorReport_struct ) 
 * @retval 0 Success
 * @retval 1 Not Supported
@@ -602,12 +1485,398 @@ func NewEventDispatcher( add_times bool, buffered bool ) ( return_report error_r
 	var event_dispatcher EventDispatcher_struct;
 	//Parametres
 	//Function
-	event_dispatcher.add_times = add_times;
-	event_dispatcher.buffered = buffered;
+	event_dispatcher = *newEventDispatcher( add_times, buffered );
 	return_report = error_report.New( 0, map[string]interface{}{ "event_dispatcher": event_dispatcher }, nil );
 	//Return
 	return return_report;
 }
 
 //Private Functions
+/**
+* @fn newEventDispatcher
+* @brief Creates a new event dispatcher and returns a pointer to it, for the constructors which hand out a pointer.
+* @param add_times bool [in] A boolean value representing whether to add submission and transmission times to events.
+* @param buffered bool [in] A boolean value representing whether to queue events.
+* @return *EventDispatcher_struct
+*/
+
+// newEventDispatcher creates a new event dispatcher and returns a pointer to it, for the constructors which hand out a pointer.
+func newEventDispatcher( add_times bool, buffered bool ) *EventDispatcher_struct{
+	//Variables
+	var event_dispatcher *EventDispatcher_struct = &EventDispatcher_struct{};
+	//Parametres
+	//Function
+	event_dispatcher.mutex = &sync.Mutex{};
+	event_dispatcher.run_mutex = &sync.Mutex{};
+	event_dispatcher.in_flight_mutex = &sync.Mutex{};
+	event_dispatcher.dead_letter_mutex = &sync.Mutex{};
+	event_dispatcher.retry_mutex = &sync.Mutex{};
+	event_dispatcher.retry_timers_map = map[*time.Timer]struct{}{};
+	event_dispatcher.add_times = add_times;
+	event_dispatcher.buffered = buffered;
+	event_dispatcher.wake_channel = make(chan struct{}, 1);
+	//Return
+	return event_dispatcher;
+}
+
+/**
+* @fn copyData
+* @brief Returns a shallow copy of the given event data; never nil.
+* @param data map[string]interface{} [in] The data to copy; may be nil.
+* @return map[string]interface{}
+*/
+
+// copyData returns a shallow copy of the given event data; never nil.
+func copyData( data map[string]interface{} ) map[string]interface{}{
+	//Variables
+	var _return map[string]interface{} = make(map[string]interface{}, len(data) + 3);
+	var key string;
+	var value interface{};
+	//Parametres
+	//Function
+	for key, value = range data {
+		_return[key] = value;
+	}
+	//Return
+	return _return;
+}
+
+/**
+* @fn removeEventListeners_Unsafe
+* @brief Replaces the event listeners slice with a copy lacking every listener for which `remove_function` returns true; the caller must hold `mutex`.
+* @struct event_dispatcher *EventDispatcher_struct
+* @param remove_function func( event_listener EventListener_struct ) bool [in] Returns true for the listeners to be removed.
+* @return int The number of listeners removed.
+*/
+
+// removeEventListeners_Unsafe replaces the event listeners slice with a copy lacking every listener for which `remove_function` returns true; the caller must hold `mutex`.
+func (event_dispatcher *EventDispatcher_struct) removeEventListeners_Unsafe( remove_function func( event_listener EventListener_struct ) bool ) int{
+	//Variables
+	var i int;
+	var event_listeners_slice []EventListener_struct;
+	//Parametres
+	//Function
+	event_listeners_slice = make([]EventListener_struct, 0, len(event_dispatcher.event_listeners_slice));
+	for i = 0; i < len(event_dispatcher.event_listeners_slice); i++ {
+		if( remove_function( event_dispatcher.event_listeners_slice[i] ) == false ){
+			event_listeners_slice = append(event_listeners_slice, event_dispatcher.event_listeners_slice[i]);
+		}
+	}
+	i = len(event_dispatcher.event_listeners_slice) - len(event_listeners_slice);
+	if( i > 0 ){
+		event_dispatcher.event_listeners_slice = event_listeners_slice;
+	}
+	//Return
+	return i;
+}
+
+/**
+* @fn autoDrain
+* @brief Processes queued events until the queue is empty, unless another call is already doing so.
+* @struct event_dispatcher *EventDispatcher_struct
+* @return ( return_report error_report.ErrorReport_struct ) 
+* @retval 0 Success
+* @retval 1 Not Supported
+* @retval >1 Error
+*/
+
+// autoDrain processes queued events until the queue is empty, unless another call is already doing so, in which case that call will process any events queued meanwhile. The report is as for `ProcessEvents`.
+func (event_dispatcher *EventDispatcher_struct) autoDrain() ( return_report error_report.ErrorReport_struct ){
+	//Variables
+	var function_return error_report.ErrorReport_struct;
+	var processed int;
+	var errors int;
+	var error_reports []error_report.ErrorReport_struct;
+	var empty bool;
+	//Parametres
+	event_dispatcher.mutex.Lock();
+	if( event_dispatcher.draining == true ){
+		event_dispatcher.mutex.Unlock();
+		return error_report.New( 0, map[string]interface{}{ "processed": 0, "errors": 0 }, nil );
+	}
+	event_dispatcher.draining = true;
+	event_dispatcher.mutex.Unlock();
+	//Function
+	for empty == false {
+		function_return = event_dispatcher.ProcessEvents();
+		processed += function_return.Data["processed"].(int);
+		errors += function_return.Data["errors"].(int);
+		if( function_return.IsError() == true ){
+			error_reports = append(error_reports, function_return.Data["error_reports"].([]error_report.ErrorReport_struct)...);
+		}
+		event_dispatcher.mutex.Lock();
+		empty = ( event_dispatcher.getEventsQueue_Unsafe().Length() == 0 );
+		if( empty == true ){
+			event_dispatcher.draining = false;
+		}
+		event_dispatcher.mutex.Unlock();
+	}
+	if( errors == 0 ){
+		return_report = error_report.New( 0, map[string]interface{}{ "processed": processed, "errors": errors }, nil );
+	} else{
+		function_return = error_reports[(errors - 1)];
+		return_report = error_report.New( ERROR_CODE_EVENT_PROCESSING_ERROR, map[string]interface{}{ "processed": processed, "errors": errors, "error_reports": error_reports }, &function_return );
+	}
+	//Return
+	return return_report;
+}
 
+/**
+* @fn snapshotDispatch_Unsafe
+* @brief Copies the listeners, options, hooks, and worker pool a dispatch uses; the caller must hold `mutex`.
+* @struct event_dispatcher *EventDispatcher_struct
+* @return dispatch_snapshot_struct
+*/
+
+// snapshotDispatch_Unsafe copies the listeners, options, hooks, and worker pool a dispatch uses; the caller must hold `mutex`.
+func (event_dispatcher *EventDispatcher_struct) snapshotDispatch_Unsafe() dispatch_snapshot_struct{
+	//Variables
+	//Parametres
+	//Function
+	//Return
+	return dispatch_snapshot_struct{ event_listeners_slice: event_dispatcher.event_listeners_slice, add_times: event_dispatcher.add_times, recorder: event_dispatcher.recorder, error_sink: event_dispatcher.error_sink, panic_hook: event_dispatcher.panic_hook, worker_pool: event_dispatcher.worker_pool, partition_key_function: event_dispatcher.partition_key_function };
+}
+
+/**
+* @fn processEvent
+* @brief Records the event, transmits it, dead-letters it if it wasn't delivered, and acknowledges it; shared by `ProcessEvent` and `ProcessEvent_Unsafe`.
+* @struct event_dispatcher *EventDispatcher_struct
+* @param event Event_struct [in] The event to be processed.
+* @param dispatch_snapshot dispatch_snapshot_struct [in] The listeners and hooks to use, as returned by `snapshotDispatch_Unsafe`.
+* @return ( return_report error_report.ErrorReport_struct ) 
+* @retval 0 Success
+* @retval 1 Not Supported
+* @retval >1 Error
+*/
+
+// processEvent records the event, transmits it, dead-letters it if it wasn't delivered, and acknowledges it once the asynchronous listeners it matched have returned too; shared by `ProcessEvent` and `ProcessEvent_Unsafe`. Returns the report from `transmitEvent`.
+func (event_dispatcher *EventDispatcher_struct) processEvent( event Event_struct, dispatch_snapshot dispatch_snapshot_struct ) ( return_report error_report.ErrorReport_struct ){
+	//Variables
+	var acknowledgement *acknowledgement_struct;
+	//Parametres
+	//Function
+	acknowledgement = event_dispatcher.holdAcknowledgement( event );
+	if( dispatch_snapshot.recorder != nil ){
+		dispatch_snapshot.recorder.record( event );
+	}
+	return_report = event_dispatcher.transmitEvent( event, dispatch_snapshot, acknowledgement );
+	event_dispatcher.deadLetterIfFailed( event, return_report );
+	acknowledgement.release();
+	//Return
+	return return_report;
+}
+
+/**
+* @fn transmitEvent
+* @brief Calls every listener in the snapshot matching the event.
+* @struct event_dispatcher *EventDispatcher_struct
+* @param event Event_struct [in] The event to be transmitted.
+* @param dispatch_snapshot dispatch_snapshot_struct [in] The listeners and hooks to use, as returned by `snapshotDispatch_Unsafe`.
+* @param acknowledgement *acknowledgement_struct [in] Held for each asynchronous listener call until it returns; may be nil.
+* @return ( return_report error_report.ErrorReport_struct ) 
+* @retval 0 Success
+* @retval 1 Not Supported
+* @retval >1 Error
+*/
+
+// transmitEvent calls every listener in `event_listeners_slice` matching the event, stopping early if a synchronous listener calls `StopPropagation`. Match errors and synchronous listener errors are keyed in the returned report by the listener's ID.
+func (event_dispatcher *EventDispatcher_struct) transmitEvent( event Event_struct, dispatch_snapshot dispatch_snapshot_struct, acknowledgement *acknowledgement_struct ) ( return_report error_report.ErrorReport_struct ){
+	//Variables
+	var event_listeners_slice []EventListener_struct = dispatch_snapshot.event_listeners_slice;
+	var i int; //Event listener index
+	var match bool;
+	var id_string string;
+	var function_return error_report.ErrorReport_struct;
+	var async_event Event_struct;
+	var stopped_by uint64;
+	var match_errors int;
+	var listener_errors int;
+	var panics int;
+	var matched int;
+	var retried int;
+	var data map[string]interface{} = map[string]interface{}{};
+	//Parametres
+	if( dispatch_snapshot.add_times == true ){
+		event = event.withDatum( "transmission_time", time.Now() );
+	}
+	async_event = event;
+	async_event.propagation = nil;
+	async_event.ctx = detachContext( event.ctx );
+	event.propagation = &propagation_struct{};
+	//Function
+	for i = 0; i < len(event_listeners_slice) && stopped_by == 0; i++ {
+		id_string = strconv.FormatUint( event_listeners_slice[i].id, 10 );
+		if( event.retry_event_listener_id != 0 ){
+			match = ( event_listeners_slice[i].id == event.retry_event_listener_id );
+			function_return = error_report.ERROR_REPORT_NIL_VALUE;
+		} else{
+			match, function_return = event_listeners_slice[i].key.Match( event.name );
+		}
+		if( function_return.NoError() == true ){
+			if( match == true ){
+				matched++;
+				if( event_listeners_slice[i].async == true ){
+					acknowledgement.hold();
+					event_dispatcher.submitAsyncEventListener( event_listeners_slice[i], async_event, dispatch_snapshot, acknowledgement );
+				} else{
+					function_return = event_dispatcher.callEventListener( event_listeners_slice[i], event, dispatch_snapshot );
+					if( function_return.IsError() == true ){
+						listener_errors++;
+						if( function_return.CodeEqual( ERROR_CODE_EVENT_LISTENER_PANIC ) == true ){
+							panics++;
+						}
+						if( event_dispatcher.retryOrGiveUp( event_listeners_slice[i], event, function_return ) == true ){
+							retried++;
+						}
+						data[id_string] = function_return;
+					}
+					if( event.IsPropagationStopped() == true ){
+						stopped_by = event_listeners_slice[i].id;
+					}
+				}
+			}
+		} else{
+			match_errors++;
+			data[id_string] = function_return;
+		}
+	}
+	data["errors"] = match_errors + listener_errors;
+	data["matched"] = matched;
+	data["match_errors"] = match_errors;
+	data["listener_errors"] = listener_errors;
+	data["panics"] = panics;
+	data["retried"] = retried;
+	data["propagation_stopped"] = ( stopped_by != 0 );
+	data["stopped_by"] = stopped_by;
+	data["default_prevented"] = event.IsDefaultPrevented();
+	if( match_errors > 0 ){
+		data["message"] = "Matching against an event listener returned an error.";
+		return_report = error_report.New( ERROR_CODE_EVENT_LISTENER_MATCH, data, nil );
+	} else if( listener_errors > 0 ){
+		data["message"] = "An event listener returned an error.";
+		return_report = error_report.New( ERROR_CODE_EVENT_LISTENER_ERROR, data, nil );
+	} else{
+		return_report = error_report.New( 0, data, nil );
+	}
+	//Return
+	return return_report;
+}
+
+/**
+* @fn callEventListener
+* @brief Calls the listener's function with the event and turns any error it returns, or any panic, into an error report.
+* @struct event_dispatcher *EventDispatcher_struct
+* @param event_listener EventListener_struct [in] The listener to call.
+* @param event Event_struct [in] The event to pass to it.
+* @param dispatch_snapshot dispatch_snapshot_struct [in] The snapshot holding the panic hook and error sink.
+* @return ( return_report error_report.ErrorReport_struct ) 
+* @retval 0 Success
+* @retval 1 Not Supported
+* @retval >1 Error
+*/
+
+// callEventListener calls the listener's function with the event and turns any error it returns, or any panic, into an error report. Panics are also sent to the panic hook.
+func (event_dispatcher *EventDispatcher_struct) callEventListener( event_listener EventListener_struct, event Event_struct, dispatch_snapshot dispatch_snapshot_struct ) ( return_report error_report.ErrorReport_struct ){
+	//Variables
+	var function_error error;
+	//Parametres
+	//Function
+	defer func(){
+		var recovered interface{} = recover();
+		if( recovered != nil ){
+			return_report = error_report.New( ERROR_CODE_EVENT_LISTENER_PANIC, map[string]interface{}{ "message": fmt.Sprintf( "Event listener %d panicked while handling \"%s\": %v", event_listener.id, event.name, recovered ), "panic": recovered, "stack": string(debug.Stack()), "event_listener_id": event_listener.id, "event_name": event.name }, nil );
+			dispatch_snapshot.hookPanic( return_report );
+		}
+	}();
+	event.ctx = withParentEvent( event.Context(), event );
+	function_error = event_listener.function( event.ctx, event );
+	if( function_error == nil ){
+		return_report = error_report.New( 0, map[string]interface{}{}, nil );
+	} else{
+		return_report = error_report.New( ERROR_CODE_EVENT_LISTENER_ERROR, map[string]interface{}{ "message": function_error.Error(), "error": function_error, "event_listener_id": event_listener.id, "event_name": event.name }, nil );
+	}
+	//Return
+	return return_report;
+}
+
+/**
+* @fn callAsyncEventListener
+* @brief Calls the listener in the current goroutine and, unless its retry policy takes care of it, sends any resulting error report to the error sink; panics have already gone to the panic hook.
+* @struct event_dispatcher *EventDispatcher_struct
+* @param event_listener EventListener_struct [in] The listener to call.
+* @param event Event_struct [in] The event to pass to it.
+* @param dispatch_snapshot dispatch_snapshot_struct [in] The snapshot taken by the dispatch which submitted the call.
+*/
+
+// callAsyncEventListener calls the listener in the current goroutine and, unless its retry policy takes care of it, sends any resulting error report to the error sink; panics have already gone to the panic hook.
+func (event_dispatcher *EventDispatcher_struct) callAsyncEventListener( event_listener EventListener_struct, event Event_struct, dispatch_snapshot dispatch_snapshot_struct ){
+	//Variables
+	var function_return error_report.ErrorReport_struct;
+	//Parametres
+	//Function
+	function_return = event_dispatcher.callEventListener( event_listener, event, dispatch_snapshot );
+	if( function_return.IsError() == true && event_dispatcher.retryOrGiveUp( event_listener, event, function_return ) == false && function_return.CodeEqual( ERROR_CODE_EVENT_LISTENER_ERROR ) == true ){
+		dispatch_snapshot.sinkError( function_return );
+	}
+	//Return
+}
+
+/**
+* @fn sinkError
+* @brief Sends the error report to the error sink, if one is set.
+* @struct event_dispatcher *EventDispatcher_struct
+* @param report error_report.ErrorReport_struct [in] The report to send.
+*/
+
+// sinkError sends the error report to the error sink, if one is set.
+func (event_dispatcher *EventDispatcher_struct) sinkError( report error_report.ErrorReport_struct ){
+	//Variables
+	var error_sink func( report error_report.ErrorReport_struct );
+	//Parametres
+	//Function
+	event_dispatcher.mutex.Lock();
+	error_sink = event_dispatcher.error_sink;
+	event_dispatcher.mutex.Unlock();
+	if( error_sink != nil ){
+		error_sink( report );
+	}
+	//Return
+}
+
+/**
+* @fn sinkError
+* @brief Sends the error report to the snapshot's error sink, if one was set.
+* @struct dispatch_snapshot dispatch_snapshot_struct
+* @param report error_report.ErrorReport_struct [in] The report to send.
+*/
+
+// sinkError sends the error report to the snapshot's error sink, if one was set.
+func (dispatch_snapshot dispatch_snapshot_struct) sinkError( report error_report.ErrorReport_struct ){
+	//Variables
+	//Parametres
+	//Function
+	if( dispatch_snapshot.error_sink != nil ){
+		dispatch_snapshot.error_sink( report );
+	}
+	//Return
+}
+
+/**
+* @fn hookPanic
+* @brief Sends the report of a recovered listener panic to the snapshot's panic hook, or to its error sink if no panic hook was set.
+* @struct dispatch_snapshot dispatch_snapshot_struct
+* @param report error_report.ErrorReport_struct [in] The report to send.
+*/
+
+// hookPanic sends the report of a recovered listener panic to the snapshot's panic hook, or to its error sink if no panic hook was set.
+func (dispatch_snapshot dispatch_snapshot_struct) hookPanic( report error_report.ErrorReport_struct ){
+	//Variables
+	//Parametres
+	//Function
+	if( dispatch_snapshot.panic_hook != nil ){
+		dispatch_snapshot.panic_hook( report );
+	} else{
+		dispatch_snapshot.sinkError( report );
+	}
+	//Return
+}
@@ -28,6 +28,10 @@ import(
 	//## Standard
 	"testing"
 	"log"
+	"context"
+	"time"
+	"errors"
+	"strconv"
 	//## External
 	error_report "github.com/Anadian/error_report/source"
 	matchkey "github.com/Anadian/matchkey/source"
@@ -347,6 +351,633 @@ func TestEventDispatcher( t *testing.T ){
 	//Return
 }
 
+/**
+* @fn TestEventDispatcherRunLoop
+* @brief Tests `Start`, `Stop`, and `SetDrainOnStop`.
+* @param t *testing.T [in] Go stdlib testing object.
+*/
+
+// TestEventDispatcherRunLoop tests `Start`, `Stop`, and `SetDrainOnStop`.
+func TestEventDispatcherRunLoop( t *testing.T ){
+	//Variables
+	var event_dispatcher *EventDispatcher_struct;
+	var key matchkey.MatchKey_struct;
+	var event_listener EventListener_struct;
+	var event Event_struct;
+	var received_channel chan string = make(chan string, 16);
+	var release_channel chan struct{} = make(chan struct{});
+	var stopped_channel chan error_report.ErrorReport_struct = make(chan error_report.ErrorReport_struct, 1);
+	var function_return error_report.ErrorReport_struct;
+	var ctx context.Context;
+	var cancel context.CancelFunc;
+	//Parametres
+	//Function
+	key, _ = matchkey.New( matchkey.MATCHKEY_TYPE_PATH, "run_loop:*" );
+	event_dispatcher = newEventDispatcher( true, true );
+	event_listener = NewEventListener( key, false, func( event Event_struct, args ...interface{} ){
+		received_channel <- event.name;
+	} ).Data["event_listener"].(EventListener_struct);
+	event_dispatcher.AddEventListener( event_listener );
+	///Events pushed while running are delivered without calling ProcessEvents.
+	function_return = event_dispatcher.Start( context.Background() );
+	if( function_return.NoError() == true ){
+		log.Printf("Success: Start returned no errors.\n");
+	} else{
+		t.Fail();
+		log.Printf("Failure: event_dispatcher.Start returned an error: %v\n", function_return);
+	}
+	function_return = event_dispatcher.Start( context.Background() );
+	if( function_return.CodeEqual( ERROR_CODE_ALREADY_RUNNING ) == true ){
+		log.Printf("Success: Start refused to start a second run loop.\n");
+	} else{
+		t.Fail();
+		log.Printf("Failure: Didn't get ERROR_CODE_ALREADY_RUNNING: %v\n", function_return);
+	}
+	event = NewEvent( "run_loop:first", map[string]interface{}{} ).Data["event"].(Event_struct);
+	event_dispatcher.PushEvent( event );
+	select{
+		case name := <-received_channel:
+			log.Printf("Success: Run loop delivered %s.\n", name);
+		case <-time.After( 5 * time.Second ):
+			t.Fail();
+			log.Printf("Failure: Run loop didn't deliver the pushed event.\n");
+	}
+	function_return = event_dispatcher.Stop();
+	if( function_return.NoError() == true && function_return.Data["processed"].(int) == 1 ){
+		log.Printf("Success: Stop returned: %v\n", function_return);
+	} else{
+		t.Fail();
+		log.Printf("Failure: event_dispatcher.Stop returned an unexpected report: %v\n", function_return);
+	}
+	function_return = event_dispatcher.Stop();
+	if( function_return.CodeEqual( ERROR_CODE_NOT_RUNNING ) == true ){
+		log.Printf("Success: Stop reported the run loop wasn't running.\n");
+	} else{
+		t.Fail();
+		log.Printf("Failure: Didn't get ERROR_CODE_NOT_RUNNING: %v\n", function_return);
+	}
+	///Cancelling the context drops whatever is still queued by default.
+	ctx, cancel = context.WithCancel( context.Background() );
+	event_dispatcher.mutex.Lock();
+	event_dispatcher.getEventsQueue_Unsafe().PushBack( event );
+	event_dispatcher.events_queue.PushBack( event );
+	event_dispatcher.mutex.Unlock();
+	cancel();
+	event_dispatcher.Start( ctx );
+	for event_dispatcher.IsRunning() == true {
+		time.Sleep( time.Millisecond );
+	}
+	function_return = event_dispatcher.Stop();
+	if( function_return.NoError() == true && function_return.Data["processed"].(int) + function_return.Data["dropped"].(int) == 2 ){
+		log.Printf("Success: Cancelled run loop returned: %v\n", function_return);
+	} else{
+		t.Fail();
+		log.Printf("Failure: Cancelled run loop returned an unexpected report: %v\n", function_return);
+	}
+	///With SetDrainOnStop(true) the remaining events are processed on the way out.
+	event_dispatcher.SetDrainOnStop( true );
+	ctx, cancel = context.WithCancel( context.Background() );
+	event_dispatcher.mutex.Lock();
+	event_dispatcher.getEventsQueue_Unsafe().PushBack( event );
+	event_dispatcher.events_queue.PushBack( event );
+	event_dispatcher.mutex.Unlock();
+	cancel();
+	event_dispatcher.Start( ctx );
+	function_return = event_dispatcher.Stop();
+	if( function_return.NoError() == true && function_return.Data["processed"].(int) == 2 && function_return.Data["dropped"].(int) == 0 ){
+		log.Printf("Success: Draining run loop returned: %v\n", function_return);
+	} else{
+		t.Fail();
+		log.Printf("Failure: Draining run loop returned an unexpected report: %v\n", function_return);
+	}
+	///A listener on the run loop may call IsRunning while Stop waits for the loop.
+	event_dispatcher = newEventDispatcher( false, true );
+	event_dispatcher.AddEventListener( NewEventListener( key, false, func( event Event_struct, args ...interface{} ){
+		received_channel <- event.name;
+		<-release_channel;
+		event_dispatcher.IsRunning();
+	} ).Data["event_listener"].(EventListener_struct) );
+	event_dispatcher.Start( context.Background() );
+	event_dispatcher.PushEvent( NewEvent( "run_loop:stopping", map[string]interface{}{} ).Data["event"].(Event_struct) );
+	<-received_channel;
+	go func(){
+		stopped_channel <- event_dispatcher.Stop();
+	}();
+	time.Sleep( 20 * time.Millisecond );
+	close(release_channel);
+	select{
+		case function_return = <-stopped_channel:
+			log.Printf("Success: Stop returned while a listener called IsRunning: %v\n", function_return);
+		case <-time.After( 5 * time.Second ):
+			t.Fail();
+			log.Printf("Failure: Stop deadlocked with a listener calling IsRunning.\n");
+	}
+	//Return
+}
+/**
+* @fn TestEventListenerSubscription
+* @brief Tests `Subscription_struct`, `RemoveEventListenerByID`, and removing listeners during a dispatch.
+* @param t *testing.T [in] Go stdlib testing object.
+*/
+
+// TestEventListenerSubscription tests `Subscription_struct`, `RemoveEventListenerByID`, and removing listeners during a dispatch.
+func TestEventListenerSubscription( t *testing.T ){
+	//Variables
+	var event_dispatcher *EventDispatcher_struct;
+	var key matchkey.MatchKey_struct;
+	var event Event_struct;
+	var first_calls, second_calls, self_removing_calls int;
+	var first_subscription, second_subscription, self_removing_subscription Subscription_struct;
+	var function_return error_report.ErrorReport_struct;
+	//Parametres
+	//Function
+	key, _ = matchkey.New( matchkey.MATCHKEY_TYPE_STRING, "subscription:test" );
+	event_dispatcher = newEventDispatcher( false, false );
+	function_return = event_dispatcher.AddEventListener( NewEventListener( key, false, func( event Event_struct, args ...interface{} ){
+		first_calls++;
+	} ).Data["event_listener"].(EventListener_struct) );
+	first_subscription = function_return.Data["subscription"].(Subscription_struct);
+	function_return = event_dispatcher.AddEventListener( NewEventListener( key, false, func( event Event_struct, args ...interface{} ){
+		second_calls++;
+	} ).Data["event_listener"].(EventListener_struct) );
+	second_subscription = function_return.Data["subscription"].(Subscription_struct);
+	function_return = event_dispatcher.AddEventListener( NewEventListener( key, false, func( event Event_struct, args ...interface{} ){
+		self_removing_calls++;
+		self_removing_subscription.Unsubscribe();
+	} ).Data["event_listener"].(EventListener_struct) );
+	self_removing_subscription = function_return.Data["subscription"].(Subscription_struct);
+	if( first_subscription.ID() != second_subscription.ID() && second_subscription.ID() != self_removing_subscription.ID() ){
+		log.Printf("Success: Listeners sharing a pattern got distinct IDs.\n");
+	} else{
+		t.Fail();
+		log.Printf("Failure: Listener IDs aren't unique: %d %d %d\n", first_subscription.ID(), second_subscription.ID(), self_removing_subscription.ID());
+	}
+	function_return = first_subscription.Unsubscribe();
+	if( function_return.NoError() == true ){
+		log.Printf("Success: Unsubscribed the first listener.\n");
+	} else{
+		t.Fail();
+		log.Printf("Failure: first_subscription.Unsubscribe returned an error: %v\n", function_return);
+	}
+	function_return = event_dispatcher.RemoveEventListenerByID( first_subscription.ID() );
+	if( function_return.CodeEqual( ERROR_CODE_EVENT_LISTENER_NOT_FOUND ) == true ){
+		log.Printf("Success: Removing an already removed listener returned ERROR_CODE_EVENT_LISTENER_NOT_FOUND.\n");
+	} else{
+		t.Fail();
+		log.Printf("Failure: Didn't get ERROR_CODE_EVENT_LISTENER_NOT_FOUND: %v\n", function_return);
+	}
+	event = NewEvent( "subscription:test", map[string]interface{}{} ).Data["event"].(Event_struct);
+	event_dispatcher.ProcessEvent( event );
+	event_dispatcher.ProcessEvent( event );
+	if( first_calls == 0 && second_calls == 2 && self_removing_calls == 1 ){
+		log.Printf("Success: Only the remaining listeners were called.\n");
+	} else{
+		t.Fail();
+		log.Printf("Failure: Unexpected listener calls: first: %d second: %d self-removing: %d\n", first_calls, second_calls, self_removing_calls);
+	}
+	//Return
+}
+/**
+* @fn TestEventListenerPriority
+* @brief Tests that listeners are called by descending priority and then by insertion order.
+* @param t *testing.T [in] Go stdlib testing object.
+*/
+
+// TestEventListenerPriority tests that listeners are called by descending priority and then by insertion order.
+func TestEventListenerPriority( t *testing.T ){
+	//Variables
+	var event_dispatcher *EventDispatcher_struct;
+	var key matchkey.MatchKey_struct;
+	var order string;
+	var priorities []int64 = []int64{ 0, 10, -5, 10, 0 };
+	var names []string = []string{ "a", "b", "c", "d", "e" };
+	var i int;
+	//Parametres
+	//Function
+	key, _ = matchkey.New( matchkey.MATCHKEY_TYPE_STRING, "priority:test" );
+	event_dispatcher = newEventDispatcher( false, false );
+	for i = 0; i < len(names); i++ {
+		name := names[i];
+		event_dispatcher.AddEventListener( NewPriorityEventListener( key, false, priorities[i], func( event Event_struct, args ...interface{} ){
+			order += name;
+		} ).Data["event_listener"].(EventListener_struct) );
+	}
+	event_dispatcher.ProcessEvent( NewEvent( "priority:test", map[string]interface{}{} ).Data["event"].(Event_struct) );
+	if( order == "bdaec" ){
+		log.Printf("Success: Listeners called in priority order: %s\n", order);
+	} else{
+		t.Fail();
+		log.Printf("Failure: Listeners called in the wrong order: %s\n", order);
+	}
+	//Return
+}
+/**
+* @fn TestEventPropagation
+* @brief Tests `StopPropagation` and `PreventDefault` and how `ProcessEvent` reports them.
+* @param t *testing.T [in] Go stdlib testing object.
+*/
+
+// TestEventPropagation tests `StopPropagation` and `PreventDefault` and how `ProcessEvent` reports them.
+func TestEventPropagation( t *testing.T ){
+	//Variables
+	var event_dispatcher *EventDispatcher_struct;
+	var key matchkey.MatchKey_struct;
+	var validator_id uint64;
+	var handler_calls int;
+	var valid bool;
+	var event Event_struct;
+	var function_return error_report.ErrorReport_struct;
+	//Parametres
+	//Function
+	key, _ = matchkey.New( matchkey.MATCHKEY_TYPE_STRING, "before_save" );
+	event_dispatcher = newEventDispatcher( false, false );
+	function_return = event_dispatcher.AddEventListener( NewPriorityEventListener( key, false, 100, func( event Event_struct, args ...interface{} ){
+		if( valid == false ){
+			event.PreventDefault();
+			event.StopPropagation();
+		}
+	} ).Data["event_listener"].(EventListener_struct) );
+	validator_id = function_return.Data["event_listener_id"].(uint64);
+	event_dispatcher.AddEventListener( NewEventListener( key, false, func( event Event_struct, args ...interface{} ){
+		handler_calls++;
+	} ).Data["event_listener"].(EventListener_struct) );
+	event = NewEvent( "before_save", map[string]interface{}{} ).Data["event"].(Event_struct);
+	function_return = event_dispatcher.ProcessEvent( event );
+	if( function_return.NoError() == true && function_return.Data["propagation_stopped"] == true && function_return.Data["stopped_by"] == validator_id && function_return.Data["default_prevented"] == true && handler_calls == 0 ){
+		log.Printf("Success: Validator vetoed the event: %v\n", function_return.Data);
+	} else{
+		t.Fail();
+		log.Printf("Failure: Unexpected veto outcome: %v handler_calls: %d\n", function_return, handler_calls);
+	}
+	valid = true;
+	function_return = event_dispatcher.ProcessEvent( event );
+	if( function_return.NoError() == true && function_return.Data["propagation_stopped"] == false && function_return.Data["default_prevented"] == false && handler_calls == 1 ){
+		log.Printf("Success: Valid event reached every listener.\n");
+	} else{
+		t.Fail();
+		log.Printf("Failure: Unexpected outcome for a valid event: %v handler_calls: %d\n", function_return, handler_calls);
+	}
+	//Return
+}
+/**
+* @fn TestEventListenerErrors
+* @brief Tests that listener errors are collected by `ProcessEvent`/`ProcessEvents` and sent to the error sink.
+* @param t *testing.T [in] Go stdlib testing object.
+*/
+
+// TestEventListenerErrors tests that listener errors are collected by `ProcessEvent`/`ProcessEvents` and sent to the error sink.
+func TestEventListenerErrors( t *testing.T ){
+	//Variables
+	var event_dispatcher *EventDispatcher_struct;
+	var key matchkey.MatchKey_struct;
+	var failing_id uint64;
+	var event Event_struct;
+	var sink_channel chan error_report.ErrorReport_struct = make(chan error_report.ErrorReport_struct, 1);
+	var function_return error_report.ErrorReport_struct;
+	var listener_report error_report.ErrorReport_struct;
+	var listener_error error = errors.New( "handler failed" );
+	//Parametres
+	//Function
+	key, _ = matchkey.New( matchkey.MATCHKEY_TYPE_STRING, "errors:test" );
+	event_dispatcher = newEventDispatcher( false, true );
+	event_dispatcher.SetErrorSink( func( report error_report.ErrorReport_struct ){
+		sink_channel <- report;
+	} );
+	function_return = event_dispatcher.AddEventListener( NewFallibleEventListener( key, false, 0, func( event Event_struct, args ...interface{} ) error{
+		return listener_error;
+	} ).Data["event_listener"].(EventListener_struct) );
+	failing_id = function_return.Data["event_listener_id"].(uint64);
+	event_dispatcher.AddEventListener( NewFallibleEventListener( key, true, 0, func( event Event_struct, args ...interface{} ) error{
+		return listener_error;
+	} ).Data["event_listener"].(EventListener_struct) );
+	event = NewEvent( "errors:test", map[string]interface{}{} ).Data["event"].(Event_struct);
+	function_return = event_dispatcher.ProcessEvent( event );
+	if( function_return.CodeEqual( ERROR_CODE_EVENT_PROCESSING_ERROR ) == true ){
+		listener_report, _ = function_return.GetWrapped().Data[strconv.FormatUint( failing_id, 10 )].(error_report.ErrorReport_struct);
+		if( listener_report.CodeEqual( ERROR_CODE_EVENT_LISTENER_ERROR ) == true && listener_report.Data["error"] == listener_error ){
+			log.Printf("Success: ProcessEvent reported the listener error keyed by listener ID.\n");
+		} else{
+			t.Fail();
+			log.Printf("Failure: Listener error wasn't keyed by listener ID: %v\n", function_return.GetWrapped());
+		}
+	} else{
+		t.Fail();
+		log.Printf("Failure: Didn't get ERROR_CODE_EVENT_PROCESSING_ERROR: %v\n", function_return);
+	}
+	select{
+		case listener_report = <-sink_channel:
+			if( listener_report.CodeEqual( ERROR_CODE_EVENT_LISTENER_ERROR ) == true ){
+				log.Printf("Success: Asynchronous listener error reached the error sink.\n");
+			} else{
+				t.Fail();
+				log.Printf("Failure: Error sink received an unexpected report: %v\n", listener_report);
+			}
+		case <-time.After( 5 * time.Second ):
+			t.Fail();
+			log.Printf("Failure: Asynchronous listener error never reached the error sink.\n");
+	}
+	event_dispatcher.PushEvent( event );
+	event_dispatcher.PushEvent( event );
+	function_return = event_dispatcher.ProcessEvents();
+	if( function_return.CodeEqual( ERROR_CODE_EVENT_PROCESSING_ERROR ) == true && function_return.Data["processed"] == 2 && function_return.Data["errors"] == 2 ){
+		log.Printf("Success: ProcessEvents collected both failures.\n");
+	} else{
+		t.Fail();
+		log.Printf("Failure: ProcessEvents returned an unexpected report: %v\n", function_return);
+	}
+	//Return
+}
+/**
+* @fn TestEventListenerPanic
+* @brief Tests that panicking listeners are recovered, reported to the panic hook, and leave the dispatcher usable.
+* @param t *testing.T [in] Go stdlib testing object.
+*/
+
+// TestEventListenerPanic tests that panicking listeners are recovered, reported to the panic hook, and leave the dispatcher usable.
+func TestEventListenerPanic( t *testing.T ){
+	//Variables
+	var event_dispatcher *EventDispatcher_struct;
+	var unsafe_event_dispatcher *EventDispatcher_struct;
+	var key matchkey.MatchKey_struct;
+	var panic_channel chan error_report.ErrorReport_struct = make(chan error_report.ErrorReport_struct, 4);
+	var event Event_struct;
+	var function_return error_report.ErrorReport_struct;
+	var panic_report error_report.ErrorReport_struct;
+	var done_channel chan error_report.ErrorReport_struct = make(chan error_report.ErrorReport_struct, 1);
+	var i int;
+	//Parametres
+	//Function
+	key, _ = matchkey.New( matchkey.MATCHKEY_TYPE_STRING, "panic:test" );
+	event_dispatcher = newEventDispatcher( false, false );
+	event_dispatcher.SetPanicHook( func( report error_report.ErrorReport_struct ){
+		panic_channel <- report;
+	} );
+	event_dispatcher.AddEventListener( NewEventListener( key, false, func( event Event_struct, args ...interface{} ){
+		panic( "synchronous" );
+	} ).Data["event_listener"].(EventListener_struct) );
+	event_dispatcher.AddEventListener( NewEventListener( key, true, func( event Event_struct, args ...interface{} ){
+		panic( "asynchronous" );
+	} ).Data["event_listener"].(EventListener_struct) );
+	event = NewEvent( "panic:test", map[string]interface{}{} ).Data["event"].(Event_struct);
+	function_return = event_dispatcher.ProcessEvent( event );
+	if( function_return.CodeEqual( ERROR_CODE_EVENT_PROCESSING_ERROR ) == true && function_return.GetWrapped().CodeEqual( ERROR_CODE_EVENT_LISTENER_ERROR ) == true ){
+		log.Printf("Success: ProcessEvent reported the synchronous panic.\n");
+	} else{
+		t.Fail();
+		log.Printf("Failure: ProcessEvent returned an unexpected report: %v\n", function_return);
+	}
+	for i = 0; i < 2; i++ {
+		select{
+			case panic_report = <-panic_channel:
+				if( panic_report.CodeEqual( ERROR_CODE_EVENT_LISTENER_PANIC ) == true && panic_report.Data["event_name"] == "panic:test" && panic_report.Data["stack"].(string) != "" ){
+					log.Printf("Success: Panic hook received: %v\n", panic_report.Data["message"]);
+				} else{
+					t.Fail();
+					log.Printf("Failure: Panic hook received an unexpected report: %v\n", panic_report);
+				}
+			case <-time.After( 5 * time.Second ):
+				t.Fail();
+				log.Printf("Failure: Panic hook wasn't called.\n");
+		}
+	}
+	///The panic hook is taken from a snapshot, so a panic doesn't deadlock a caller of ProcessEvent_Unsafe holding the lock.
+	unsafe_event_dispatcher = newEventDispatcher( false, false );
+	unsafe_event_dispatcher.SetPanicHook( func( report error_report.ErrorReport_struct ){
+		panic_channel <- report;
+	} );
+	unsafe_event_dispatcher.AddEventListener( NewEventListener( key, false, func( event Event_struct, args ...interface{} ){
+		panic( "synchronous" );
+	} ).Data["event_listener"].(EventListener_struct) );
+	unsafe_event_dispatcher.mutex.Lock();
+	go func(){
+		done_channel <- unsafe_event_dispatcher.ProcessEvent_Unsafe( event );
+	}();
+	select{
+		case function_return = <-done_channel:
+			if( function_return.CodeEqual( ERROR_CODE_EVENT_LISTENER_ERROR ) == true ){
+				log.Printf("Success: ProcessEvent_Unsafe reported the synchronous panic with the lock held.\n");
+			} else{
+				t.Fail();
+				log.Printf("Failure: ProcessEvent_Unsafe returned an unexpected report: %v\n", function_return);
+			}
+		case <-time.After( 5 * time.Second ):
+			t.Fail();
+			log.Printf("Failure: ProcessEvent_Unsafe deadlocked on the panic hook.\n");
+	}
+	unsafe_event_dispatcher.mutex.Unlock();
+	select{
+		case <-panic_channel:
+		case <-time.After( 5 * time.Second ):
+			t.Fail();
+			log.Printf("Failure: Panic hook wasn't called for ProcessEvent_Unsafe.\n");
+	}
+	///The dispatcher must still be usable afterwards.
+	function_return = event_dispatcher.AddEventListener( NewEventListener( key, false, func( event Event_struct, args ...interface{} ){} ).Data["event_listener"].(EventListener_struct) );
+	if( function_return.NoError() == true ){
+		log.Printf("Success: Dispatcher still usable after the panics.\n");
+	} else{
+		t.Fail();
+		log.Printf("Failure: AddEventListener returned an error after the panics: %v\n", function_return);
+	}
+	//Return
+}
+
+/**
+* @fn TestProcessEventUnsafe
+* @brief Tests that `ProcessEvent_Unsafe` can be called with the dispatcher's lock held and still dead-letters undelivered events and reports asynchronous errors.
+* @param t *testing.T [in] Go stdlib testing object.
+*/
+
+// TestProcessEventUnsafe tests that `ProcessEvent_Unsafe` can be called with the dispatcher's lock held and still dead-letters undelivered events and reports asynchronous errors.
+func TestProcessEventUnsafe( t *testing.T ){
+	//Variables
+	var event_dispatcher *EventDispatcher_struct;
+	var failing_key matchkey.MatchKey_struct;
+	var async_key matchkey.MatchKey_struct;
+	var error_channel chan error_report.ErrorReport_struct = make(chan error_report.ErrorReport_struct, 4);
+	var done_channel chan []error_report.ErrorReport_struct = make(chan []error_report.ErrorReport_struct, 1);
+	var reports_slice []error_report.ErrorReport_struct;
+	var dead_letters_slice []DeadLetter_struct;
+	var error_report_value error_report.ErrorReport_struct;
+	//Parametres
+	//Function
+	failing_key, _ = matchkey.New( matchkey.MATCHKEY_TYPE_STRING, "unsafe:failing" );
+	async_key, _ = matchkey.New( matchkey.MATCHKEY_TYPE_STRING, "unsafe:async" );
+	event_dispatcher = newEventDispatcher( false, false );
+	event_dispatcher.EnableDeadLetterQueue( 0 );
+	event_dispatcher.SetErrorSink( func( report error_report.ErrorReport_struct ){
+		error_channel <- report;
+	} );
+	event_dispatcher.AddEventListener( NewFallibleEventListener( failing_key, false, 0, func( event Event_struct, args ...interface{} ) error{
+		return errors.New( "failed" );
+	} ).Data["event_listener"].(EventListener_struct) );
+	event_dispatcher.AddEventListener( NewFallibleEventListener( async_key, true, 0, func( event Event_struct, args ...interface{} ) error{
+		return errors.New( "failed asynchronously" );
+	} ).Data["event_listener"].(EventListener_struct) );
+	event_dispatcher.mutex.Lock();
+	go func(){
+		done_channel <- []error_report.ErrorReport_struct{
+			event_dispatcher.ProcessEvent_Unsafe( NewEvent( "unsafe:failing", map[string]interface{}{} ).Data["event"].(Event_struct) ),
+			event_dispatcher.ProcessEvent_Unsafe( NewEvent( "unsafe:unmatched", map[string]interface{}{} ).Data["event"].(Event_struct) ),
+			event_dispatcher.ProcessEvent_Unsafe( NewEvent( "unsafe:async", map[string]interface{}{} ).Data["event"].(Event_struct) ),
+		};
+	}();
+	select{
+		case reports_slice = <-done_channel:
+			if( reports_slice[0].CodeEqual( ERROR_CODE_EVENT_LISTENER_ERROR ) == true && reports_slice[1].NoError() == true && reports_slice[1].Data["matched"] == 0 && reports_slice[2].NoError() == true ){
+				log.Printf("Success: ProcessEvent_Unsafe returned the transmission reports with the lock held.\n");
+			} else{
+				t.Fail();
+				log.Printf("Failure: ProcessEvent_Unsafe returned unexpected reports: %v\n", reports_slice);
+			}
+		case <-time.After( 5 * time.Second ):
+			t.Fail();
+			log.Printf("Failure: ProcessEvent_Unsafe deadlocked with the lock held.\n");
+	}
+	event_dispatcher.mutex.Unlock();
+	event_dispatcher.Wait();
+	dead_letters_slice = event_dispatcher.GetDeadLetters().Data["dead_letters"].([]DeadLetter_struct);
+	if( len(dead_letters_slice) == 2 && dead_letters_slice[0].Reason() == DEAD_LETTER_REASON_LISTENER_ERROR && dead_letters_slice[1].Reason() == DEAD_LETTER_REASON_UNMATCHED ){
+		log.Printf("Success: ProcessEvent_Unsafe dead-lettered the undelivered events.\n");
+	} else{
+		t.Fail();
+		log.Printf("Failure: Unexpected dead letters: %v\n", dead_letters_slice);
+	}
+	select{
+		case error_report_value = <-error_channel:
+			if( error_report_value.CodeEqual( ERROR_CODE_EVENT_LISTENER_ERROR ) == true && error_report_value.Data["event_name"] == "unsafe:async" ){
+				log.Printf("Success: The asynchronous listener's error reached the error sink.\n");
+			} else{
+				t.Fail();
+				log.Printf("Failure: The error sink received an unexpected report: %v\n", error_report_value);
+			}
+		case <-time.After( 5 * time.Second ):
+			t.Fail();
+			log.Printf("Failure: The asynchronous listener's error didn't reach the error sink.\n");
+	}
+	//Return
+}
 
 //# Private Functions
 
+
+/**
+* @fn TestPublish
+* @brief Tests that `Publish` dispatches unbuffered events immediately, queues buffered ones, and drains when auto-drain is on.
+* @param t *testing.T [in] Go stdlib testing object.
+*/
+
+// TestPublish tests that `Publish` dispatches unbuffered events immediately, queues buffered ones, and drains when auto-drain is on.
+func TestPublish( t *testing.T ){
+	//Variables
+	var unbuffered_event_dispatcher *EventDispatcher_struct;
+	var buffered_event_dispatcher *EventDispatcher_struct;
+	var event_listener EventListener_struct;
+	var key matchkey.MatchKey_struct;
+	var event Event_struct;
+	var function_return error_report.ErrorReport_struct;
+	var calls int;
+	//Parametres
+	//Function
+	key, _ = matchkey.New( matchkey.MATCHKEY_TYPE_STRING, "publish:test" );
+	event_listener = NewFallibleEventListener( key, false, 0, func( event Event_struct, args ...interface{} ) error{
+		calls++;
+		if( event.data["fail"] == true ){
+			return errors.New( "failed" );
+		}
+		return nil;
+	} ).Data["event_listener"].(EventListener_struct);
+	event = NewEvent( "publish:test", map[string]interface{}{} ).Data["event"].(Event_struct);
+	unbuffered_event_dispatcher = newEventDispatcher( false, false );
+	unbuffered_event_dispatcher.AddEventListener( event_listener );
+	function_return = unbuffered_event_dispatcher.Publish( event );
+	if( function_return.NoError() == true && calls == 1 && function_return.Data["queued"] == false && function_return.Data["errors"] == 0 ){
+		log.Printf("Success: An unbuffered dispatcher transmitted the event immediately.\n");
+	} else{
+		t.Fail();
+		log.Printf("Failure: Unbuffered Publish returned %v after %d calls\n", function_return, calls);
+	}
+	function_return = unbuffered_event_dispatcher.Publish( NewEvent( "publish:test", map[string]interface{}{ "fail": true } ).Data["event"].(Event_struct) );
+	if( function_return.CodeEqual( ERROR_CODE_EVENT_PROCESSING_ERROR ) == true && function_return.Data["errors"] == 1 ){
+		log.Printf("Success: Unbuffered Publish counted the listener error.\n");
+	} else{
+		t.Fail();
+		log.Printf("Failure: Unbuffered Publish returned %v\n", function_return);
+	}
+	calls = 0;
+	buffered_event_dispatcher = newEventDispatcher( false, true );
+	buffered_event_dispatcher.AddEventListener( event_listener );
+	function_return = buffered_event_dispatcher.Publish( event );
+	if( function_return.NoError() == true && calls == 0 && function_return.Data["queued"] == true && buffered_event_dispatcher.GetEventByIndex( 0 ).NoError() == true ){
+		log.Printf("Success: A buffered dispatcher queued the event.\n");
+	} else{
+		t.Fail();
+		log.Printf("Failure: Buffered Publish returned %v after %d calls\n", function_return, calls);
+	}
+	buffered_event_dispatcher.SetAutoDrain( true );
+	function_return = buffered_event_dispatcher.Publish( NewEvent( "publish:test", map[string]interface{}{ "fail": true } ).Data["event"].(Event_struct) );
+	if( function_return.CodeEqual( ERROR_CODE_EVENT_PROCESSING_ERROR ) == true && calls == 2 && function_return.Data["processed"] == 2 && function_return.Data["errors"] == 1 && function_return.Data["queued"] == true ){
+		log.Printf("Success: Auto-drain processed both queued events.\n");
+	} else{
+		t.Fail();
+		log.Printf("Failure: Auto-draining Publish returned %v after %d calls\n", function_return, calls);
+	}
+	//Return
+}
+
+/**
+* @fn TestEventAccessors
+* @brief Tests the event accessors and that listeners can read an event's data while the dispatcher stamps it again.
+* @param t *testing.T [in] Go stdlib testing object.
+*/
+
+// TestEventAccessors tests the event accessors and that listeners can read an event's data while the dispatcher stamps it again; run with `-race`.
+func TestEventAccessors( t *testing.T ){
+	//Variables
+	var event_dispatcher *EventDispatcher_struct;
+	var key matchkey.MatchKey_struct;
+	var data map[string]interface{} = map[string]interface{}{ "order_id": "order-1" };
+	var event Event_struct;
+	var value interface{};
+	var ok bool;
+	var i int;
+	//Parametres
+	//Function
+	event = NewEvent( "accessors:test", data ).Data["event"].(Event_struct);
+	data["order_id"] = "changed";
+	value, ok = event.Get( "order_id" );
+	if( event.Name() == "accessors:test" && ok == true && value == "order-1" ){
+		log.Printf("Success: Name and Get returned the event's name and data.\n");
+	} else{
+		t.Fail();
+		log.Printf("Failure: Name returned %q and Get returned %v, %v\n", event.Name(), value, ok);
+	}
+	event.Data()["order_id"] = "changed";
+	_, ok = event.Get( "missing" );
+	if( event.data["order_id"] == "order-1" && ok == false ){
+		log.Printf("Success: Data returned a copy.\n");
+	} else{
+		t.Fail();
+		log.Printf("Failure: The event's data was changed through Data: %v\n", event.data);
+	}
+	event_dispatcher = newEventDispatcher( true, false );
+	key, _ = matchkey.New( matchkey.MATCHKEY_TYPE_STRING, "accessors:test" );
+	event_dispatcher.AddEventListener( NewEventListener( key, true, func( event Event_struct, args ...interface{} ){
+		var key string;
+		for key = range event.Data() {
+			_, _ = event.Get( key );
+		}
+	} ).Data["event_listener"].(EventListener_struct) );
+	for i = 0; i < 10; i++ {
+		event_dispatcher.ProcessEvent( event );
+	}
+	event_dispatcher.Wait();
+	_, ok = event.Get( "transmission_time" );
+	if( ok == false ){
+		log.Printf("Success: Transmitting the event didn't modify the caller's copy.\n");
+	} else{
+		t.Fail();
+		log.Printf("Failure: The caller's event was stamped: %v\n", event.data);
+	}
+	//Return
+}
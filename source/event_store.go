@@ -0,0 +1,359 @@
+/**
+* @file event_store.go
+* @brief An event store: named streams of events with optimistic concurrency, read back and replayed through listeners.
+* @author Anadian
+* @copyright 	Copyright 2019 Canosw
+	Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+software and associated documentation files (the "Software"), to deal in the Software 
+without restriction, including without limitation the rights to use, copy, modify, 
+merge, publish, distribute, sublicense, and/or sell copies of the Software, and to 
+permit persons to whom the Software is furnished to do so, subject to the following 
+conditions:
+	The above copyright notice and this permission notice shall be included in all copies 
+or substantial portions of the Software.
+	THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A 
+PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT 
+HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF 
+CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE 
+OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+package event_dispatcher;
+
+//# Dependencies
+import(
+	//## Internal
+	//## Standard
+	"context"
+	"strconv"
+	"sync"
+	//## External
+	error_report "github.com/Anadian/error_report/source"
+);
+
+//# Constants
+const(
+	//## Exported Constants
+	//### Errors
+	ERROR_CODE_WRONG_EXPECTED_VERSION int64 = 39;
+	ERROR_CODE_INVALID_STREAM int64 = 40;
+	ERROR_CODE_REPLAY_ERROR int64 = 41;
+	//### Expected Versions
+	EXPECTED_VERSION_ANY int64 = -1 //Append regardless of the stream's version.
+	EXPECTED_VERSION_NO_STREAM int64 = 0 //Append only if the stream has no events yet.
+	//### Headers
+	EVENT_STORE_STREAM_HEADER string = "stream" //The stream a stored event was appended to.
+	EVENT_STORE_VERSION_HEADER string = "streamversion" //A stored event's version within its stream, in decimal.
+	//## Private Constants
+);
+
+//# Types
+//## Structs
+// EventStore_struct keeps events in named streams. A stream's version is the number of events in it, so its first event has version 1; every event also has a position in the store as a whole, in the order appended. Stored events carry their stream and version as headers. The store is safe for concurrent use.
+type EventStore_struct struct{
+	mutex sync.RWMutex
+	event_dispatcher *EventDispatcher_struct
+	//Every stored event in the order appended.
+	events_slice []Event_struct
+	//The indices into `events_slice` of each stream's events.
+	streams_map map[string][]int
+	//Stored events waiting to be published, in the order appended, and whether an `Append` is publishing them.
+	publish_queue []Event_struct
+	publishing bool
+}
+//### Methods
+/**
+* @fn Append
+* @brief Appends the events to the stream if its version is `expected_version`, then publishes them through the store's dispatcher.
+* @struct event_store *EventStore_struct
+* @param stream string [in] The stream's name; not empty.
+* @param expected_version int64 [in] The version the stream must be at; EXPECTED_VERSION_ANY or EXPECTED_VERSION_NO_STREAM.
+* @param events ...Event_struct [in] The events to append, in order.
+* @return ( return_report error_report.ErrorReport_struct ) 
+* @retval 0 Success
+* @retval 1 Not Supported
+* @retval >1 Error
+*/
+
+// Append appends the events to the stream, atomically, if its version is `expected_version`, returning its new "version" and the stored "events" (with their stream and version headers). Otherwise nothing is appended and ERROR_CODE_WRONG_EXPECTED_VERSION gives the "current_version", so the caller can reload and retry. Once stored, the events are published through the store's dispatcher to its listeners' matching keys as any other event; failures there go to the dispatcher's error sink since the append has already succeeded. Events are published in the order they were stored, even across concurrent appends: an `Append` made while another is publishing, including one made by a listener, leaves its events to be published by that one, so it can return before they are.
+func (event_store *EventStore_struct) Append( stream string, expected_version int64, events ...Event_struct ) ( return_report error_report.ErrorReport_struct ){
+	//Variables
+	var current_version int64;
+	var stored_events []Event_struct = make([]Event_struct, len(events));
+	var publish bool;
+	var i int;
+	//Parametres
+	if( stream == "" ){
+		return error_report.New( ERROR_CODE_INVALID_STREAM, map[string]interface{}{ "message": "The stream's name is empty." }, nil );
+	}
+	if( expected_version < EXPECTED_VERSION_ANY ){
+		return error_report.New( ERROR_CODE_WRONG_EXPECTED_VERSION, map[string]interface{}{ "message": "Invalid expected version.", "expected_version": expected_version }, nil );
+	}
+	//Function
+	event_store.mutex.Lock();
+	current_version = int64(len(event_store.streams_map[stream]));
+	if( expected_version != EXPECTED_VERSION_ANY && expected_version != current_version ){
+		event_store.mutex.Unlock();
+		return error_report.New( ERROR_CODE_WRONG_EXPECTED_VERSION, map[string]interface{}{ "message": "The stream isn't at the expected version.", "stream": stream, "expected_version": expected_version, "current_version": current_version }, nil );
+	}
+	for i = 0; i < len(events); i++ {
+		stored_events[i] = events[i].WithHeader( EVENT_STORE_STREAM_HEADER, stream ).WithHeader( EVENT_STORE_VERSION_HEADER, strconv.FormatInt( current_version + int64(i) + 1, 10 ) );
+		event_store.streams_map[stream] = append(event_store.streams_map[stream], len(event_store.events_slice));
+		event_store.events_slice = append(event_store.events_slice, stored_events[i]);
+	}
+	current_version += int64(len(events));
+	if( event_store.event_dispatcher != nil ){
+		event_store.publish_queue = append(event_store.publish_queue, stored_events...);
+		publish = !event_store.publishing;
+		event_store.publishing = true;
+	}
+	event_store.mutex.Unlock();
+	if( publish == true ){
+		event_store.publishQueued();
+	}
+	return_report = error_report.New( 0, map[string]interface{}{ "version": current_version, "events": stored_events }, nil );
+	//Return
+	return return_report;
+}
+
+/**
+* @fn Read
+* @brief Returns the stream's events from `from_version` to `to_version` inclusive.
+* @struct event_store *EventStore_struct
+* @param stream string [in] The stream's name; empty for every stream.
+* @param from_version uint64 [in] The first version to return; 0 or 1 for the start.
+* @param to_version uint64 [in] The last version to return; 0 for the end.
+* @return ( return_report error_report.ErrorReport_struct ) 
+* @retval 0 Success
+* @retval 1 Not Supported
+* @retval >1 Error
+*/
+
+// Read returns the stream's events from `from_version` to `to_version` inclusive as the "events" datum, with the stream's current "version". A `to_version` of 0 reads to the end, and a stream with no events is empty rather than an error. An empty stream name reads every stream in the order appended, with positions in the store in place of versions.
+func (event_store *EventStore_struct) Read( stream string, from_version uint64, to_version uint64 ) ( return_report error_report.ErrorReport_struct ){
+	//Variables
+	var events []Event_struct;
+	var version uint64;
+	//Parametres
+	//Function
+	event_store.mutex.RLock();
+	events, version = event_store.read_Unsafe( stream, from_version, to_version );
+	event_store.mutex.RUnlock();
+	return_report = error_report.New( 0, map[string]interface{}{ "events": events, "version": version }, nil );
+	//Return
+	return return_report;
+}
+
+/**
+* @fn Version
+* @brief Returns the stream's current version.
+* @struct event_store *EventStore_struct
+* @param stream string [in] The stream's name; empty for the number of events in the store.
+* @return uint64 0 if the stream has no events.
+*/
+
+// Version returns the stream's current version, the number of events in it; 0 if it has none. An empty stream name gives the number of events in the store.
+func (event_store *EventStore_struct) Version( stream string ) uint64{
+	//Variables
+	var version uint64;
+	//Parametres
+	//Function
+	event_store.mutex.RLock();
+	if( stream == "" ){
+		version = uint64(len(event_store.events_slice));
+	} else{
+		version = uint64(len(event_store.streams_map[stream]));
+	}
+	event_store.mutex.RUnlock();
+	//Return
+	return version;
+}
+
+/**
+* @fn Replay
+* @brief Processes the stream's events from `from_version` on through the given dispatcher's listeners, in order.
+* @struct event_store *EventStore_struct
+* @param ctx context.Context [in] Given to the listeners; replay stops when it's done.
+* @param event_dispatcher *EventDispatcher_struct [in] Whose listeners to replay the events to; the store's dispatcher if nil.
+* @param stream string [in] The stream's name; empty for every stream.
+* @param from_version uint64 [in] The first version to replay; 0 or 1 for the start.
+* @return ( return_report error_report.ErrorReport_struct ) 
+* @retval 0 Success
+* @retval 1 Not Supported
+* @retval >1 Error
+*/
+
+// Replay processes the stream's events from `from_version` on, as `Read` returns them, through the given dispatcher's listeners, synchronously and in order, to rebuild projections. Replaying to a dispatcher holding only the projections' listeners avoids repeating the side effects of live ones. The "replayed" and "errors" data count the events processed and those whose processing failed; replay stops early, with the context's error, if `ctx` is done.
+func (event_store *EventStore_struct) Replay( ctx context.Context, event_dispatcher *EventDispatcher_struct, stream string, from_version uint64 ) ( return_report error_report.ErrorReport_struct ){
+	//Variables
+	var events []Event_struct;
+	var function_return error_report.ErrorReport_struct;
+	var replayed int;
+	var errors int;
+	var i int;
+	//Parametres
+	if( event_dispatcher == nil ){
+		event_store.mutex.RLock();
+		event_dispatcher = event_store.event_dispatcher;
+		event_store.mutex.RUnlock();
+	}
+	if( event_dispatcher == nil ){
+		return error_report.New( ERROR_CODE_REPLAY_ERROR, map[string]interface{}{ "message": "No dispatcher to replay through." }, nil );
+	}
+	if( ctx == nil ){
+		ctx = context.Background();
+	}
+	//Function
+	events = event_store.Read( stream, from_version, 0 ).Data["events"].([]Event_struct);
+	for i = 0; i < len(events) && ctx.Err() == nil; i++ {
+		function_return = event_dispatcher.ProcessEventCtx( ctx, events[i] );
+		replayed++;
+		if( function_return.IsError() == true ){
+			errors++;
+		}
+	}
+	if( ctx.Err() == nil ){
+		return_report = error_report.New( 0, map[string]interface{}{ "replayed": replayed, "errors": errors }, nil );
+	} else{
+		return_report = error_report.New( ERROR_CODE_REPLAY_ERROR, map[string]interface{}{ "message": ctx.Err().Error(), "context_error": ctx.Err(), "replayed": replayed, "errors": errors }, nil );
+	}
+	//Return
+	return return_report;
+}
+
+/**
+* @fn publishQueued
+* @brief Publishes the queued events through the store's dispatcher until none are left.
+* @struct event_store *EventStore_struct
+*/
+
+// publishQueued publishes the queued events through the store's dispatcher, one at a time and without holding `mutex`, until none are left; only the `Append` which set `publishing` calls it, so events are published in the order they were stored.
+func (event_store *EventStore_struct) publishQueued(){
+	//Variables
+	var event Event_struct;
+	var function_return error_report.ErrorReport_struct;
+	//Parametres
+	//Function
+	event_store.mutex.Lock();
+	for len(event_store.publish_queue) > 0 {
+		event = event_store.publish_queue[0];
+		event_store.publish_queue = event_store.publish_queue[1:];
+		event_store.mutex.Unlock();
+		function_return = event_store.event_dispatcher.Publish( event );
+		if( function_return.IsError() == true ){
+			event_store.event_dispatcher.sinkError( function_return );
+		}
+		event_store.mutex.Lock();
+	}
+	event_store.publish_queue = nil;
+	event_store.publishing = false;
+	event_store.mutex.Unlock();
+	//Return
+}
+
+/**
+* @fn read_Unsafe
+* @brief Returns the stream's events in the version range and its current version; the caller must hold `mutex`.
+* @struct event_store *EventStore_struct
+* @param stream string [in] The stream's name; empty for every stream.
+* @param from_version uint64 [in] The first version; 0 or 1 for the start.
+* @param to_version uint64 [in] The last version; 0 for the end.
+* @return ( events []Event_struct, version uint64 )
+*/
+
+// read_Unsafe returns the stream's events in the version range and its current version; the caller must hold `mutex`.
+func (event_store *EventStore_struct) read_Unsafe( stream string, from_version uint64, to_version uint64 ) ( events []Event_struct, version uint64 ){
+	//Variables
+	var indices []int;
+	var i uint64;
+	//Parametres
+	//Function
+	if( stream == "" ){
+		version = uint64(len(event_store.events_slice));
+	} else{
+		indices = event_store.streams_map[stream];
+		version = uint64(len(indices));
+	}
+	if( from_version == 0 ){
+		from_version = 1;
+	}
+	if( to_version == 0 || to_version > version ){
+		to_version = version;
+	}
+	events = []Event_struct{};
+	for i = from_version; i <= to_version; i++ {
+		if( stream == "" ){
+			events = append(events, event_store.events_slice[i - 1]);
+		} else{
+			events = append(events, event_store.events_slice[indices[i - 1]]);
+		}
+	}
+	//Return
+	return events, version;
+}
+
+/**
+* @fn Stream
+* @brief Returns the stream the event was stored in.
+* @struct event Event_struct
+* @return string Empty for events not from an event store.
+*/
+
+// Stream returns the stream the event was stored in; empty for events not from an event store.
+func (event Event_struct) Stream() string{
+	//Variables
+	var stream string;
+	//Parametres
+	//Function
+	stream, _ = event.Header( EVENT_STORE_STREAM_HEADER );
+	//Return
+	return stream;
+}
+
+/**
+* @fn StreamVersion
+* @brief Returns the event's version within the stream it was stored in.
+* @struct event Event_struct
+* @return uint64 0 for events not from an event store.
+*/
+
+// StreamVersion returns the event's version within the stream it was stored in; 0 for events not from an event store.
+func (event Event_struct) StreamVersion() uint64{
+	//Variables
+	var value string;
+	var version uint64;
+	//Parametres
+	//Function
+	value, _ = event.Header( EVENT_STORE_VERSION_HEADER );
+	version, _ = strconv.ParseUint( value, 10, 64 );
+	//Return
+	return version;
+}
+
+//# Global Variables
+var(
+	//## Exported Variables
+	//## Private Variables
+);
+
+//# Exported Functions
+/**
+* @fn NewEventStore
+* @brief Creates an empty event store publishing appended events through the given dispatcher.
+* @param event_dispatcher *EventDispatcher_struct [in] Where appended events are published, and the default for `Replay`; nil to only store them.
+* @return ( return_report error_report.ErrorReport_struct ) 
+* @retval 0 Success
+* @retval 1 Not Supported
+* @retval >1 Error
+*/
+
+// NewEventStore creates an empty, in-memory event store, the "event_store" datum, publishing appended events through the given dispatcher; nil to only store them.
+func NewEventStore( event_dispatcher *EventDispatcher_struct ) ( return_report error_report.ErrorReport_struct ){
+	//Variables
+	var event_store *EventStore_struct = &EventStore_struct{ event_dispatcher: event_dispatcher, streams_map: map[string][]int{} };
+	//Parametres
+	//Function
+	return_report = error_report.New( 0, map[string]interface{}{ "event_store": event_store }, nil );
+	//Return
+	return return_report;
+}
@@ -0,0 +1,173 @@
+/**
+* @file event_store_test.go
+* @brief Contains test functions for `event_store.go`.
+* @author Anadian
+* @copyright 	Copyright 2019 Canosw
+	Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+software and associated documentation files (the "Software"), to deal in the Software 
+without restriction, including without limitation the rights to use, copy, modify, 
+merge, publish, distribute, sublicense, and/or sell copies of the Software, and to 
+permit persons to whom the Software is furnished to do so, subject to the following 
+conditions:
+	The above copyright notice and this permission notice shall be included in all copies 
+or substantial portions of the Software.
+	THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A 
+PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT 
+HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF 
+CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE 
+OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+// event_store_test contains test functions for `event_store.go`.
+package event_dispatcher;
+
+//# Dependencies
+import(
+	//## Internal
+	//## Standard
+	"testing"
+	"log"
+	"context"
+	"sync"
+	"time"
+	//## External
+	error_report "github.com/Anadian/error_report/source"
+	matchkey "github.com/Anadian/matchkey/source"
+);
+
+//# Exported Functions
+/**
+* @fn TestEventStore
+* @brief Tests appending with expected versions, reading version ranges, live dispatch, and replay.
+* @param t *testing.T [in] Go stdlib testing object.
+*/
+
+// TestEventStore tests appending with expected versions, reading version ranges, live dispatch, and replay.
+func TestEventStore( t *testing.T ){
+	//Variables
+	var event_dispatcher *EventDispatcher_struct;
+	var projection_dispatcher *EventDispatcher_struct;
+	var event_store *EventStore_struct;
+	var function_return error_report.ErrorReport_struct;
+	var key matchkey.MatchKey_struct;
+	var live_versions []uint64;
+	var total int;
+	var events []Event_struct;
+	var ctx context.Context;
+	var cancel context.CancelFunc;
+	var wait_group sync.WaitGroup;
+	var published_events []Event_struct;
+	var ordered bool = true;
+	var i int;
+	//Parametres
+	//Function
+	event_dispatcher = newEventDispatcher( false, false );
+	key, _ = matchkey.New( matchkey.MATCHKEY_TYPE_STRING, "order:placed" );
+	event_dispatcher.AddEventListener( NewEventListener( key, false, func( event Event_struct, args ...interface{} ){
+		if( event.Stream() == "order-1" ){
+			live_versions = append(live_versions, event.StreamVersion());
+		}
+	} ).Data["event_listener"].(EventListener_struct) );
+	event_store = NewEventStore( event_dispatcher ).Data["event_store"].(*EventStore_struct);
+	function_return = event_store.Append( "order-1", EXPECTED_VERSION_NO_STREAM, NewEvent( "order:placed", map[string]interface{}{ "total": 5 } ).Data["event"].(Event_struct), NewEvent( "order:placed", map[string]interface{}{ "total": 7 } ).Data["event"].(Event_struct) );
+	if( function_return.NoError() == true && function_return.Data["version"] == int64(2) && len(live_versions) == 2 && live_versions[0] == 1 && live_versions[1] == 2 ){
+		log.Printf("Success: Appended events were versioned and dispatched to live listeners.\n");
+	} else{
+		t.Fail();
+		log.Printf("Failure: Append returned %v; live versions: %v\n", function_return, live_versions);
+	}
+	function_return = event_store.Append( "order-1", EXPECTED_VERSION_NO_STREAM, NewEvent( "order:placed", map[string]interface{}{ "total": 1 } ).Data["event"].(Event_struct) );
+	if( function_return.CodeEqual( ERROR_CODE_WRONG_EXPECTED_VERSION ) == true && function_return.Data["current_version"] == int64(2) && event_store.Version( "order-1" ) == 2 && len(live_versions) == 2 ){
+		log.Printf("Success: An append at the wrong expected version was refused.\n");
+	} else{
+		t.Fail();
+		log.Printf("Failure: Append returned: %v\n", function_return);
+	}
+	if( event_store.Append( "", EXPECTED_VERSION_ANY ).CodeEqual( ERROR_CODE_INVALID_STREAM ) == false ){
+		t.Fail();
+		log.Printf("Failure: Append accepted an empty stream name.\n");
+	}
+	event_store.Append( "order-2", EXPECTED_VERSION_ANY, NewEvent( "order:placed", map[string]interface{}{ "total": 11 } ).Data["event"].(Event_struct) );
+	events = event_store.Read( "order-1", 2, 0 ).Data["events"].([]Event_struct);
+	if( len(events) == 1 && events[0].StreamVersion() == 2 && len(event_store.Read( "", 0, 0 ).Data["events"].([]Event_struct)) == 3 && len(event_store.Read( "missing", 0, 0 ).Data["events"].([]Event_struct)) == 0 ){
+		log.Printf("Success: Read returned the requested version ranges.\n");
+	} else{
+		t.Fail();
+		log.Printf("Failure: Read returned: %v\n", events);
+	}
+	///Rebuild a projection from every stream.
+	projection_dispatcher = newEventDispatcher( false, false );
+	projection_dispatcher.AddEventListener( NewEventListener( key, false, func( event Event_struct, args ...interface{} ){
+		value, _ := event.Get( "total" );
+		total += value.(int);
+	} ).Data["event_listener"].(EventListener_struct) );
+	function_return = event_store.Replay( context.Background(), projection_dispatcher, "", 0 );
+	if( function_return.NoError() == true && function_return.Data["replayed"] == 3 && total == 23 && len(live_versions) == 2 ){
+		log.Printf("Success: Replay rebuilt the projection without reaching live listeners.\n");
+	} else{
+		t.Fail();
+		log.Printf("Failure: Replay returned %v; total: %d\n", function_return, total);
+	}
+	ctx, cancel = context.WithCancel( context.Background() );
+	cancel();
+	function_return = event_store.Replay( ctx, projection_dispatcher, "order-1", 0 );
+	if( function_return.CodeEqual( ERROR_CODE_REPLAY_ERROR ) == true && function_return.Data["replayed"] == 0 ){
+		log.Printf("Success: Replay stopped with its context.\n");
+	} else{
+		t.Fail();
+		log.Printf("Failure: Replay returned: %v\n", function_return);
+	}
+	///Concurrent writers retrying on conflicts never lose an append.
+	for i = 0; i < 8; i++ {
+		wait_group.Add( 1 );
+		go func(){
+			defer wait_group.Done();
+			for event_store.Append( "counter", int64(event_store.Version( "counter" )), NewEvent( "counter:incremented", map[string]interface{}{} ).Data["event"].(Event_struct) ).IsError() == true {
+			}
+		}();
+	}
+	wait_group.Wait();
+	if( event_store.Version( "counter" ) == 8 ){
+		log.Printf("Success: Optimistic concurrency serialised concurrent appends.\n");
+	} else{
+		t.Fail();
+		log.Printf("Failure: The counter stream is at version %d\n", event_store.Version( "counter" ));
+	}
+	///Concurrent appends are published in version order, and a listener appending doesn't deadlock.
+	event_dispatcher = newEventDispatcher( false, false );
+	key, _ = matchkey.New( matchkey.MATCHKEY_TYPE_REGEX, "^ledger:" );
+	event_dispatcher.AddEventListener( NewEventListener( key, false, func( event Event_struct, args ...interface{} ){
+		///Gives concurrent appends time to overtake an event being published.
+		time.Sleep( 50 * time.Microsecond );
+		published_events = append(published_events, event);
+		if( event.Name() == "ledger:opened" ){
+			event_store.Append( "audit", EXPECTED_VERSION_ANY, NewEvent( "ledger:audited", map[string]interface{}{} ).Data["event"].(Event_struct) );
+		}
+	} ).Data["event_listener"].(EventListener_struct) );
+	event_store = NewEventStore( event_dispatcher ).Data["event_store"].(*EventStore_struct);
+	event_store.Append( "ledger", EXPECTED_VERSION_NO_STREAM, NewEvent( "ledger:opened", map[string]interface{}{} ).Data["event"].(Event_struct) );
+	for i = 0; i < 8; i++ {
+		wait_group.Add( 1 );
+		go func(){
+			var j int;
+			defer wait_group.Done();
+			for j = 0; j < 10; j++ {
+				event_store.Append( "ledger", EXPECTED_VERSION_ANY, NewEvent( "ledger:posted", map[string]interface{}{} ).Data["event"].(Event_struct) );
+			}
+		}();
+	}
+	wait_group.Wait();
+	for i = 2; i < len(published_events); i++ {
+		if( published_events[i].StreamVersion() != uint64(i) ){
+			ordered = false;
+		}
+	}
+	if( len(published_events) == 82 && published_events[0].Name() == "ledger:opened" && published_events[1].Stream() == "audit" && ordered == true ){
+		log.Printf("Success: Appended events were published in the order they were stored.\n");
+	} else{
+		t.Fail();
+		log.Printf("Failure: %d events were published, in order: %v\n", len(published_events), ordered);
+	}
+	//Return
+}
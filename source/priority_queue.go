@@ -0,0 +1,395 @@
+/**
+* @file priority_queue.go
+* @brief A heap-based event queue which always yields the highest-priority event next, with optional aging.
+* @author Anadian
+* @copyright 	Copyright 2019 Canosw
+	Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+software and associated documentation files (the "Software"), to deal in the Software 
+without restriction, including without limitation the rights to use, copy, modify, 
+merge, publish, distribute, sublicense, and/or sell copies of the Software, and to 
+permit persons to whom the Software is furnished to do so, subject to the following 
+conditions:
+	The above copyright notice and this permission notice shall be included in all copies 
+or substantial portions of the Software.
+	THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A 
+PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT 
+HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF 
+CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE 
+OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+package event_dispatcher;
+
+//# Dependencies
+import(
+	//## Internal
+	//## Standard
+	"container/heap"
+	"time"
+	//## External
+	error_report "github.com/Anadian/error_report/source"
+);
+
+//# Constants
+const(
+	//## Exported Constants
+	//### Errors
+	ERROR_CODE_INVALID_QUEUE_MODE int64 = 30;
+	//### Queue Modes
+	QUEUE_MODE_FIFO uint8 = 1 //Events are dispatched in the order they were queued; the default.
+	QUEUE_MODE_PRIORITY uint8 = 2 //The highest-priority event is dispatched next; equal priorities are dispatched in the order they were queued.
+	//## Private Constants
+);
+
+//# Types
+//## Structs
+// priority_queue_entry_struct is an event in a `priority_queue_struct` along with its ordering key.
+type priority_queue_entry_struct struct{
+	event Event_struct
+	key float64
+	sequence uint64
+}
+// priority_queue_struct is a binary max-heap of events implementing `event_queue_interface`. Index 0 is always the next event to be dispatched; other indices are in heap order.
+type priority_queue_struct struct{
+	entries []priority_queue_entry_struct
+	sequence uint64
+	aging time.Duration
+	epoch time.Time
+}
+//### Methods
+/**
+* @fn Len
+* @brief Implements `heap.Interface`.
+* @struct priority_queue *priority_queue_struct
+* @return int
+*/
+
+// Len implements `heap.Interface`.
+func (priority_queue *priority_queue_struct) Len() int{
+	//Variables
+	//Parametres
+	//Function
+	//Return
+	return len(priority_queue.entries);
+}
+
+/**
+* @fn Less
+* @brief Implements `heap.Interface`: the higher key comes first, then the lower sequence number.
+* @struct priority_queue *priority_queue_struct
+* @param i int [in] The first entry's index.
+* @param j int [in] The second entry's index.
+* @return bool
+*/
+
+// Less implements `heap.Interface`: the higher key comes first, then the lower sequence number.
+func (priority_queue *priority_queue_struct) Less( i int, j int ) bool{
+	//Variables
+	var _return bool;
+	//Parametres
+	//Function
+	if( priority_queue.entries[i].key != priority_queue.entries[j].key ){
+		_return = ( priority_queue.entries[i].key > priority_queue.entries[j].key );
+	} else{
+		_return = ( priority_queue.entries[i].sequence < priority_queue.entries[j].sequence );
+	}
+	//Return
+	return _return;
+}
+
+/**
+* @fn Swap
+* @brief Implements `heap.Interface`.
+* @struct priority_queue *priority_queue_struct
+* @param i int [in] The first entry's index.
+* @param j int [in] The second entry's index.
+*/
+
+// Swap implements `heap.Interface`.
+func (priority_queue *priority_queue_struct) Swap( i int, j int ){
+	//Variables
+	//Parametres
+	//Function
+	priority_queue.entries[i], priority_queue.entries[j] = priority_queue.entries[j], priority_queue.entries[i];
+	//Return
+}
+
+/**
+* @fn Push
+* @brief Implements `heap.Interface`; use `PushBack` instead.
+* @struct priority_queue *priority_queue_struct
+* @param entry interface{} [in] A `priority_queue_entry_struct`.
+*/
+
+// Push implements `heap.Interface`; use `PushBack` instead.
+func (priority_queue *priority_queue_struct) Push( entry interface{} ){
+	//Variables
+	//Parametres
+	//Function
+	priority_queue.entries = append(priority_queue.entries, entry.(priority_queue_entry_struct));
+	//Return
+}
+
+/**
+* @fn Pop
+* @brief Implements `heap.Interface`; use `PopFront` instead.
+* @struct priority_queue *priority_queue_struct
+* @return interface{} The removed `priority_queue_entry_struct`.
+*/
+
+// Pop implements `heap.Interface`; use `PopFront` instead.
+func (priority_queue *priority_queue_struct) Pop() interface{}{
+	//Variables
+	var entry priority_queue_entry_struct;
+	var last int = len(priority_queue.entries) - 1;
+	//Parametres
+	//Function
+	entry = priority_queue.entries[last];
+	priority_queue.entries[last] = priority_queue_entry_struct{};
+	priority_queue.entries = priority_queue.entries[:last];
+	//Return
+	return entry;
+}
+
+/**
+* @fn Length
+* @brief Returns the number of queued events.
+* @struct priority_queue *priority_queue_struct
+* @return int
+*/
+
+// Length returns the number of queued events.
+func (priority_queue *priority_queue_struct) Length() int{
+	//Variables
+	//Parametres
+	//Function
+	//Return
+	return len(priority_queue.entries);
+}
+
+/**
+* @fn Get
+* @brief Returns the event at the given heap index; index 0 is the next event to be dispatched.
+* @struct priority_queue *priority_queue_struct
+* @param index int [in] From 0 to `Length() - 1`.
+* @return Event_struct
+*/
+
+// Get returns the event at the given heap index; index 0 is the next event to be dispatched.
+func (priority_queue *priority_queue_struct) Get( index int ) Event_struct{
+	//Variables
+	//Parametres
+	//Function
+	//Return
+	return priority_queue.entries[index].event;
+}
+
+/**
+* @fn Insert
+* @brief Queues the event by its priority; the index is ignored since the heap decides the order.
+* @struct priority_queue *priority_queue_struct
+* @param index int [in] Ignored.
+* @param event Event_struct [in] The event to queue.
+*/
+
+// Insert queues the event by its priority; the index is ignored since the heap decides the order.
+func (priority_queue *priority_queue_struct) Insert( index int, event Event_struct ){
+	//Variables
+	//Parametres
+	//Function
+	priority_queue.PushBack( event );
+	//Return
+}
+
+/**
+* @fn Remove
+* @brief Removes and returns the event at the given heap index.
+* @struct priority_queue *priority_queue_struct
+* @param index int [in] From 0 to `Length() - 1`.
+* @return Event_struct
+*/
+
+// Remove removes and returns the event at the given heap index.
+func (priority_queue *priority_queue_struct) Remove( index int ) Event_struct{
+	//Variables
+	//Parametres
+	//Function
+	//Return
+	return heap.Remove( priority_queue, index ).(priority_queue_entry_struct).event;
+}
+
+/**
+* @fn PushBack
+* @brief Queues the event by its priority, aged from now if aging is enabled.
+* @struct priority_queue *priority_queue_struct
+* @param event Event_struct [in] The event to queue.
+*/
+
+// PushBack queues the event by its priority, aged from now if aging is enabled.
+func (priority_queue *priority_queue_struct) PushBack( event Event_struct ){
+	//Variables
+	var entry priority_queue_entry_struct;
+	//Parametres
+	//Function
+	///An event waiting `aging` gains one priority level. Since every queued event ages at the same rate, comparing `priority + (now - queued) / aging` reduces to comparing `priority - queued / aging`, which doesn't change while the event waits.
+	entry.event = event;
+	entry.key = float64(event.priority);
+	if( priority_queue.aging > 0 ){
+		entry.key -= float64(time.Since( priority_queue.epoch )) / float64(priority_queue.aging);
+	}
+	entry.sequence = priority_queue.sequence;
+	priority_queue.sequence++;
+	heap.Push( priority_queue, entry );
+	//Return
+}
+
+/**
+* @fn PopFront
+* @brief Removes and returns the next event to be dispatched; the queue must not be empty.
+* @struct priority_queue *priority_queue_struct
+* @return Event_struct
+*/
+
+// PopFront removes and returns the next event to be dispatched; the queue must not be empty.
+func (priority_queue *priority_queue_struct) PopFront() Event_struct{
+	//Variables
+	//Parametres
+	//Function
+	//Return
+	return heap.Pop( priority_queue ).(priority_queue_entry_struct).event;
+}
+
+/**
+* @fn PopBack
+* @brief Removes and returns the event which would be dispatched last; the queue must not be empty.
+* @struct priority_queue *priority_queue_struct
+* @return Event_struct
+*/
+
+// PopBack removes and returns the event which would be dispatched last; the queue must not be empty.
+func (priority_queue *priority_queue_struct) PopBack() Event_struct{
+	//Variables
+	var last int;
+	var i int;
+	//Parametres
+	//Function
+	///The last event is always a leaf, and the leaves are the second half of the heap.
+	last = len(priority_queue.entries) / 2;
+	for i = last + 1; i < len(priority_queue.entries); i++ {
+		if( priority_queue.Less( last, i ) == true ){
+			last = i;
+		}
+	}
+	//Return
+	return priority_queue.Remove( last );
+}
+
+/**
+* @fn Clear
+* @brief Removes every event.
+* @struct priority_queue *priority_queue_struct
+*/
+
+// Clear removes every event.
+func (priority_queue *priority_queue_struct) Clear(){
+	//Variables
+	//Parametres
+	//Function
+	priority_queue.entries = nil;
+	//Return
+}
+
+/**
+* @fn Priority
+* @brief Returns the event's priority, used when the dispatcher is in `QUEUE_MODE_PRIORITY`.
+* @struct event Event_struct
+* @return int64
+*/
+
+// Priority returns the event's priority, used when the dispatcher is in `QUEUE_MODE_PRIORITY`.
+func (event Event_struct) Priority() int64{
+	//Variables
+	//Parametres
+	//Function
+	//Return
+	return event.priority;
+}
+
+/**
+* @fn SetQueueMode
+* @brief Switches between FIFO and priority ordering of queued events; events already queued are carried over.
+* @struct event_dispatcher *EventDispatcher_struct
+* @param queue_mode uint8 [in] One of the `QUEUE_MODE_*` constants.
+* @param aging time.Duration [in] With `QUEUE_MODE_PRIORITY`, how long an event must wait to gain one priority level so low-priority events aren't starved; 0 disables aging.
+* @return ( return_report error_report.ErrorReport_struct ) 
+* @retval 0 Success
+* @retval 1 Not Supported
+* @retval >1 Error
+*/
+
+// SetQueueMode switches between FIFO and priority ordering of queued events; events already queued are carried over. Not supported by queue backends.
+func (event_dispatcher *EventDispatcher_struct) SetQueueMode( queue_mode uint8, aging time.Duration ) ( return_report error_report.ErrorReport_struct ){
+	//Variables
+	var old_events_queue event_queue_interface;
+	var new_events_queue event_queue_interface;
+	//Parametres
+	if( event_dispatcher.queue_backend != nil ){
+		return error_report.New( 1, map[string]interface{}{ "message": "Queue backends can't reorder the queue." }, nil );
+	}
+	//Function
+	if( ( queue_mode == QUEUE_MODE_FIFO || queue_mode == QUEUE_MODE_PRIORITY ) && aging >= 0 ){
+		if( queue_mode == QUEUE_MODE_PRIORITY ){
+			new_events_queue = &priority_queue_struct{ aging: aging, epoch: time.Now() };
+		} else{
+			new_events_queue = newRingBuffer();
+		}
+		event_dispatcher.mutex.Lock();
+		old_events_queue = event_dispatcher.getEventsQueue_Unsafe();
+		for old_events_queue.Length() > 0 {
+			new_events_queue.PushBack( old_events_queue.PopFront() );
+		}
+		event_dispatcher.events_queue = new_events_queue;
+		event_dispatcher.queue_mode = queue_mode;
+		event_dispatcher.mutex.Unlock();
+		return_report = error_report.New( 0, map[string]interface{}{ "queue_mode": queue_mode, "aging": aging }, nil );
+	} else{
+		return_report = error_report.New( ERROR_CODE_INVALID_QUEUE_MODE, map[string]interface{}{ "message": "Invalid queue mode or negative aging.", "queue_mode": queue_mode }, nil );
+	}
+	//Return
+	return return_report;
+}
+
+//# Global Variables
+var(
+	//## Exported Variables
+	//## Private Variables
+);
+
+//# Exported Functions
+/**
+* @fn NewPriorityEvent
+* @brief Creates a new event with the given priority; see `SetQueueMode`.
+* @param name string [in] The name of the event.
+* @param data map[string]interface{} [in] A string-keyed map of extra data contained in the event.
+* @param priority int64 [in] The event's priority; `NewEvent` uses 0.
+* @return ( return_report error_report.ErrorReport_struct ) 
+* @retval 0 Success
+* @retval 1 Not Supported
+* @retval >1 Error
+*/
+
+// NewPriorityEvent creates a new event with the given priority; see `SetQueueMode`.
+func NewPriorityEvent( name string, data map[string]interface{}, priority int64 ) ( return_report error_report.ErrorReport_struct ){
+	//Variables
+	var event Event_struct;
+	//Parametres
+	//Function
+	return_report = NewEvent( name, data );
+	if( return_report.NoError() == true ){
+		event = return_report.Data["event"].(Event_struct);
+		event.priority = priority;
+		return_report.Data["event"] = event;
+	}
+	//Return
+	return return_report;
+}
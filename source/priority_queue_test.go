@@ -0,0 +1,121 @@
+/**
+* @file priority_queue_test.go
+* @brief Contains test functions for `priority_queue.go`.
+* @author Anadian
+* @copyright 	Copyright 2019 Canosw
+	Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+software and associated documentation files (the "Software"), to deal in the Software 
+without restriction, including without limitation the rights to use, copy, modify, 
+merge, publish, distribute, sublicense, and/or sell copies of the Software, and to 
+permit persons to whom the Software is furnished to do so, subject to the following 
+conditions:
+	The above copyright notice and this permission notice shall be included in all copies 
+or substantial portions of the Software.
+	THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A 
+PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT 
+HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF 
+CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE 
+OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+// priority_queue_test contains test functions for `priority_queue.go`.
+package event_dispatcher;
+
+//# Dependencies
+import(
+	//## Internal
+	//## Standard
+	"testing"
+	"log"
+	"time"
+	//## External
+	matchkey "github.com/Anadian/matchkey/source"
+	error_report "github.com/Anadian/error_report/source"
+);
+
+//# Exported Functions
+/**
+* @fn TestPriorityQueueMode
+* @brief Tests priority ordering, stability within a priority level, migration between modes, and aging.
+* @param t *testing.T [in] Go stdlib testing object.
+*/
+
+// TestPriorityQueueMode tests priority ordering, stability within a priority level, migration between modes, and aging.
+func TestPriorityQueueMode( t *testing.T ){
+	//Variables
+	var event_dispatcher *EventDispatcher_struct;
+	var function_return error_report.ErrorReport_struct;
+	var names []string;
+	var expected []string;
+	var i int;
+	var matches bool;
+	var key matchkey.MatchKey_struct;
+	//Parametres
+	//Function
+	event_dispatcher = newEventDispatcher( false, true );
+	function_return = event_dispatcher.SetQueueMode( 0, 0 );
+	if( function_return.CodeEqual( ERROR_CODE_INVALID_QUEUE_MODE ) == true ){
+		log.Printf("Success: SetQueueMode rejected an invalid mode.\n");
+	} else{
+		t.Fail();
+		log.Printf("Failure: Didn't get ERROR_CODE_INVALID_QUEUE_MODE: %v\n", function_return);
+	}
+	key, _ = matchkey.New( matchkey.MATCHKEY_TYPE_REGEX, ".*" );
+	event_dispatcher.AddEventListener( NewEventListener( key, false, func( event Event_struct, args ...interface{} ){
+		names = append(names, event.name);
+	} ).Data["event_listener"].(EventListener_struct) );
+	///Queued in FIFO mode then migrated.
+	event_dispatcher.PushEvent( NewPriorityEvent( "telemetry:1", map[string]interface{}{}, 0 ).Data["event"].(Event_struct) );
+	event_dispatcher.PushEvent( NewPriorityEvent( "reload", map[string]interface{}{}, 5 ).Data["event"].(Event_struct) );
+	event_dispatcher.SetQueueMode( QUEUE_MODE_PRIORITY, 0 );
+	event_dispatcher.PushEvent( NewPriorityEvent( "telemetry:2", map[string]interface{}{}, 0 ).Data["event"].(Event_struct) );
+	event_dispatcher.PushEvent( NewPriorityEvent( "shutdown", map[string]interface{}{}, 10 ).Data["event"].(Event_struct) );
+	event_dispatcher.PushEvent( NewPriorityEvent( "telemetry:3", map[string]interface{}{}, 0 ).Data["event"].(Event_struct) );
+	event_dispatcher.PushEvent( NewPriorityEvent( "debug", map[string]interface{}{}, -1 ).Data["event"].(Event_struct) );
+	function_return = event_dispatcher.PopEvent();
+	if( function_return.NoError() == true && function_return.Data["event"].(Event_struct).name == "debug" ){
+		log.Printf("Success: PopEvent removed the lowest-priority event.\n");
+	} else{
+		t.Fail();
+		log.Printf("Failure: PopEvent returned: %v\n", function_return);
+	}
+	event_dispatcher.ProcessEvents();
+	expected = []string{ "shutdown", "reload", "telemetry:1", "telemetry:2", "telemetry:3" };
+	matches = ( len(names) == len(expected) );
+	for i = 0; matches == true && i < len(expected); i++ {
+		matches = ( names[i] == expected[i] );
+	}
+	if( matches == true ){
+		log.Printf("Success: Events were dispatched by priority, stably: %v\n", names);
+	} else{
+		t.Fail();
+		log.Printf("Failure: Events were dispatched as %v; expected %v\n", names, expected);
+	}
+	///Aging: a low-priority event which has waited long enough overtakes a newer, higher-priority one.
+	names = nil;
+	event_dispatcher.SetQueueMode( QUEUE_MODE_PRIORITY, time.Millisecond );
+	event_dispatcher.PushEvent( NewPriorityEvent( "old", map[string]interface{}{}, 0 ).Data["event"].(Event_struct) );
+	time.Sleep( 20 * time.Millisecond );
+	event_dispatcher.PushEvent( NewPriorityEvent( "new", map[string]interface{}{}, 5 ).Data["event"].(Event_struct) );
+	event_dispatcher.ProcessEvents();
+	if( len(names) == 2 && names[0] == "old" ){
+		log.Printf("Success: Aging let the older event go first: %v\n", names);
+	} else{
+		t.Fail();
+		log.Printf("Failure: Aging didn't apply: %v\n", names);
+	}
+	///Back to FIFO.
+	names = nil;
+	event_dispatcher.SetQueueMode( QUEUE_MODE_FIFO, 0 );
+	event_dispatcher.PushEvent( NewPriorityEvent( "low", map[string]interface{}{}, 0 ).Data["event"].(Event_struct) );
+	event_dispatcher.PushEvent( NewPriorityEvent( "high", map[string]interface{}{}, 5 ).Data["event"].(Event_struct) );
+	event_dispatcher.ProcessEvents();
+	if( len(names) == 2 && names[0] == "low" ){
+		log.Printf("Success: QUEUE_MODE_FIFO ignored priorities.\n");
+	} else{
+		t.Fail();
+		log.Printf("Failure: QUEUE_MODE_FIFO dispatched as %v\n", names);
+	}
+	//Return
+}
@@ -0,0 +1,344 @@
+/**
+* @file queue.go
+* @brief The internal event queue interface and its growable ring-buffer implementation.
+* @author Anadian
+* @copyright 	Copyright 2019 Canosw
+	Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+software and associated documentation files (the "Software"), to deal in the Software 
+without restriction, including without limitation the rights to use, copy, modify, 
+merge, publish, distribute, sublicense, and/or sell copies of the Software, and to 
+permit persons to whom the Software is furnished to do so, subject to the following 
+conditions:
+	The above copyright notice and this permission notice shall be included in all copies 
+or substantial portions of the Software.
+	THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A 
+PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT 
+HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF 
+CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE 
+OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+package event_dispatcher;
+
+//# Dependencies
+import(
+	//## Internal
+	//## Standard
+	//## External
+);
+
+//# Constants
+const(
+	//## Exported Constants
+	//## Private Constants
+	ring_buffer_minimum_capacity int = 16
+);
+
+//# Types
+//## Interfaces
+// event_queue_interface is what `EventDispatcher_struct` needs from its event queue. Indices count from the event which `PopFront` would return; callers check them against `Length` first.
+type event_queue_interface interface{
+	Length() int
+	Get( index int ) Event_struct
+	Insert( index int, event Event_struct )
+	Remove( index int ) Event_struct
+	PushBack( event Event_struct )
+	PopFront() Event_struct
+	PopBack() Event_struct
+	Clear()
+}
+//## Structs
+// ring_buffer_struct is a growable circular buffer giving O(1) `PushBack`, `PopFront`, and `PopBack`, and O(min(index, length - index)) `Insert` and `Remove`.
+type ring_buffer_struct struct{
+	buffer []Event_struct
+	head int
+	length int
+}
+//### Methods
+/**
+* @fn Length
+* @brief Returns the number of queued events.
+* @struct ring_buffer *ring_buffer_struct
+* @return int
+*/
+
+// Length returns the number of queued events.
+func (ring_buffer *ring_buffer_struct) Length() int{
+	//Variables
+	//Parametres
+	//Function
+	//Return
+	return ring_buffer.length;
+}
+
+/**
+* @fn Get
+* @brief Returns the event at the given index without removing it.
+* @struct ring_buffer *ring_buffer_struct
+* @param index int [in] From 0 to `Length() - 1`.
+* @return Event_struct
+*/
+
+// Get returns the event at the given index without removing it.
+func (ring_buffer *ring_buffer_struct) Get( index int ) Event_struct{
+	//Variables
+	//Parametres
+	//Function
+	//Return
+	return ring_buffer.buffer[ring_buffer.position( index )];
+}
+
+/**
+* @fn Insert
+* @brief Inserts the event before the one at the given index, moving whichever side of the buffer is shorter.
+* @struct ring_buffer *ring_buffer_struct
+* @param index int [in] From 0 to `Length()`.
+* @param event Event_struct [in] The event to insert.
+*/
+
+// Insert inserts the event before the one at the given index, moving whichever side of the buffer is shorter.
+func (ring_buffer *ring_buffer_struct) Insert( index int, event Event_struct ){
+	//Variables
+	var i int;
+	//Parametres
+	//Function
+	ring_buffer.grow();
+	if( index < ring_buffer.length / 2 ){
+		ring_buffer.head = ring_buffer.position( -1 );
+		ring_buffer.length++;
+		for i = 0; i < index; i++ {
+			ring_buffer.buffer[ring_buffer.position( i )] = ring_buffer.buffer[ring_buffer.position( i + 1 )];
+		}
+	} else{
+		ring_buffer.length++;
+		for i = ring_buffer.length - 1; i > index; i-- {
+			ring_buffer.buffer[ring_buffer.position( i )] = ring_buffer.buffer[ring_buffer.position( i - 1 )];
+		}
+	}
+	ring_buffer.buffer[ring_buffer.position( index )] = event;
+	//Return
+}
+
+/**
+* @fn Remove
+* @brief Removes and returns the event at the given index, moving whichever side of the buffer is shorter.
+* @struct ring_buffer *ring_buffer_struct
+* @param index int [in] From 0 to `Length() - 1`.
+* @return Event_struct
+*/
+
+// Remove removes and returns the event at the given index, moving whichever side of the buffer is shorter.
+func (ring_buffer *ring_buffer_struct) Remove( index int ) Event_struct{
+	//Variables
+	var event Event_struct;
+	var i int;
+	//Parametres
+	//Function
+	event = ring_buffer.buffer[ring_buffer.position( index )];
+	if( index < ring_buffer.length / 2 ){
+		for i = index; i > 0; i-- {
+			ring_buffer.buffer[ring_buffer.position( i )] = ring_buffer.buffer[ring_buffer.position( i - 1 )];
+		}
+		ring_buffer.buffer[ring_buffer.head] = Event_struct{};
+		ring_buffer.head = ring_buffer.position( 1 );
+	} else{
+		for i = index; i < ring_buffer.length - 1; i++ {
+			ring_buffer.buffer[ring_buffer.position( i )] = ring_buffer.buffer[ring_buffer.position( i + 1 )];
+		}
+		ring_buffer.buffer[ring_buffer.position( ring_buffer.length - 1 )] = Event_struct{};
+	}
+	ring_buffer.length--;
+	ring_buffer.shrink();
+	//Return
+	return event;
+}
+
+/**
+* @fn PushBack
+* @brief Adds the event to the end of the queue.
+* @struct ring_buffer *ring_buffer_struct
+* @param event Event_struct [in] The event to add.
+*/
+
+// PushBack adds the event to the end of the queue.
+func (ring_buffer *ring_buffer_struct) PushBack( event Event_struct ){
+	//Variables
+	//Parametres
+	//Function
+	ring_buffer.grow();
+	ring_buffer.buffer[ring_buffer.position( ring_buffer.length )] = event;
+	ring_buffer.length++;
+	//Return
+}
+
+/**
+* @fn PopFront
+* @brief Removes and returns the event at the front of the queue; the queue must not be empty.
+* @struct ring_buffer *ring_buffer_struct
+* @return Event_struct
+*/
+
+// PopFront removes and returns the event at the front of the queue; the queue must not be empty.
+func (ring_buffer *ring_buffer_struct) PopFront() Event_struct{
+	//Variables
+	var event Event_struct;
+	//Parametres
+	//Function
+	event = ring_buffer.buffer[ring_buffer.head];
+	ring_buffer.buffer[ring_buffer.head] = Event_struct{};
+	ring_buffer.head = ring_buffer.position( 1 );
+	ring_buffer.length--;
+	ring_buffer.shrink();
+	//Return
+	return event;
+}
+
+/**
+* @fn PopBack
+* @brief Removes and returns the event at the end of the queue; the queue must not be empty.
+* @struct ring_buffer *ring_buffer_struct
+* @return Event_struct
+*/
+
+// PopBack removes and returns the event at the end of the queue; the queue must not be empty.
+func (ring_buffer *ring_buffer_struct) PopBack() Event_struct{
+	//Variables
+	var event Event_struct;
+	var position int;
+	//Parametres
+	//Function
+	position = ring_buffer.position( ring_buffer.length - 1 );
+	event = ring_buffer.buffer[position];
+	ring_buffer.buffer[position] = Event_struct{};
+	ring_buffer.length--;
+	ring_buffer.shrink();
+	//Return
+	return event;
+}
+
+/**
+* @fn Clear
+* @brief Removes every event and releases the buffer.
+* @struct ring_buffer *ring_buffer_struct
+*/
+
+// Clear removes every event and releases the buffer.
+func (ring_buffer *ring_buffer_struct) Clear(){
+	//Variables
+	//Parametres
+	//Function
+	ring_buffer.buffer = nil;
+	ring_buffer.head = 0;
+	ring_buffer.length = 0;
+	//Return
+}
+
+/**
+* @fn position
+* @brief Returns the buffer position of the given queue index, which may be -1 for the slot before the head.
+* @struct ring_buffer *ring_buffer_struct
+* @param index int [in] The queue index.
+* @return int
+*/
+
+// position returns the buffer position of the given queue index, which may be -1 for the slot before the head.
+func (ring_buffer *ring_buffer_struct) position( index int ) int{
+	//Variables
+	var position int;
+	//Parametres
+	//Function
+	position = ( ring_buffer.head + index ) % len(ring_buffer.buffer);
+	if( position < 0 ){
+		position += len(ring_buffer.buffer);
+	}
+	//Return
+	return position;
+}
+
+/**
+* @fn grow
+* @brief Doubles the buffer, unwrapping it, if it has no free slot.
+* @struct ring_buffer *ring_buffer_struct
+*/
+
+// grow doubles the buffer, unwrapping it, if it has no free slot.
+func (ring_buffer *ring_buffer_struct) grow(){
+	//Variables
+	var capacity int;
+	//Parametres
+	//Function
+	if( ring_buffer.length == len(ring_buffer.buffer) ){
+		capacity = 2 * len(ring_buffer.buffer);
+		if( capacity < ring_buffer_minimum_capacity ){
+			capacity = ring_buffer_minimum_capacity;
+		}
+		ring_buffer.resize( capacity );
+	}
+	//Return
+}
+
+/**
+* @fn shrink
+* @brief Halves the buffer when it's at most a quarter full so a drained burst doesn't keep its memory.
+* @struct ring_buffer *ring_buffer_struct
+*/
+
+// shrink halves the buffer when it's at most a quarter full so a drained burst doesn't keep its memory.
+func (ring_buffer *ring_buffer_struct) shrink(){
+	//Variables
+	//Parametres
+	//Function
+	if( len(ring_buffer.buffer) > ring_buffer_minimum_capacity && ring_buffer.length <= len(ring_buffer.buffer) / 4 ){
+		ring_buffer.resize( len(ring_buffer.buffer) / 2 );
+	}
+	//Return
+}
+
+/**
+* @fn resize
+* @brief Copies the queued events, in order, into a new buffer of the given capacity.
+* @struct ring_buffer *ring_buffer_struct
+* @param capacity int [in] The new capacity; at least `length`.
+*/
+
+// resize copies the queued events, in order, into a new buffer of the given capacity.
+func (ring_buffer *ring_buffer_struct) resize( capacity int ){
+	//Variables
+	var buffer []Event_struct = make([]Event_struct, capacity);
+	var first_part int;
+	//Parametres
+	//Function
+	if( ring_buffer.length > 0 ){
+		first_part = len(ring_buffer.buffer) - ring_buffer.head;
+		if( first_part > ring_buffer.length ){
+			first_part = ring_buffer.length;
+		}
+		copy(buffer, ring_buffer.buffer[ring_buffer.head:(ring_buffer.head + first_part)]);
+		copy(buffer[first_part:], ring_buffer.buffer[:(ring_buffer.length - first_part)]);
+	}
+	ring_buffer.buffer = buffer;
+	ring_buffer.head = 0;
+	//Return
+}
+
+//# Global Variables
+var(
+	//## Exported Variables
+	//## Private Variables
+);
+
+//# Private Functions
+/**
+* @fn newRingBuffer
+* @brief Creates an empty ring buffer.
+* @return *ring_buffer_struct
+*/
+
+// newRingBuffer creates an empty ring buffer.
+func newRingBuffer() *ring_buffer_struct{
+	//Variables
+	//Parametres
+	//Function
+	//Return
+	return &ring_buffer_struct{};
+}
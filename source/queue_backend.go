@@ -0,0 +1,211 @@
+/**
+* @file queue_backend.go
+* @brief Lets a buffered dispatcher keep its queue in durable storage, with visibility timeouts and acknowledgement.
+* @author Anadian
+* @copyright 	Copyright 2019 Canosw
+	Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+software and associated documentation files (the "Software"), to deal in the Software 
+without restriction, including without limitation the rights to use, copy, modify, 
+merge, publish, distribute, sublicense, and/or sell copies of the Software, and to 
+permit persons to whom the Software is furnished to do so, subject to the following 
+conditions:
+	The above copyright notice and this permission notice shall be included in all copies 
+or substantial portions of the Software.
+	THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A 
+PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT 
+HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF 
+CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE 
+OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+package event_dispatcher;
+
+//# Dependencies
+import(
+	//## Internal
+	//## Standard
+	"context"
+	"time"
+	//## External
+	error_report "github.com/Anadian/error_report/source"
+);
+
+//# Constants
+const(
+	//## Exported Constants
+	//### Errors
+	ERROR_CODE_QUEUE_BACKEND_ERROR int64 = 38;
+	ERROR_CODE_UNDECODABLE_QUEUED_EVENT int64 = 45;
+	//## Private Constants
+	queue_backend_default_visibility_timeout time.Duration = 30 * time.Second;
+);
+
+//# Types
+//## Interfaces
+// QueueBackend_interface is durable storage for a dispatcher's queue, as given to `NewEventDispatcherWithQueueBackend`. Each operation is its own transaction. Events are kept in the order they were enqueued; a dequeued event is hidden for its visibility timeout and then becomes visible again, in its original place, unless it's acknowledged first, so a crashed or slow consumer's events are redelivered. Indices count visible events only. An event which can't be decoded is reported with ERROR_CODE_UNDECODABLE_QUEUED_EVENT and its "id" datum by `Dequeue`, `Get`, and `Remove`, having been hidden or deleted as usual, so the caller can acknowledge it rather than have it redelivered forever. Every method must be safe for concurrent use. The `bolt_queue` and `sqlite_queue` packages implement it.
+type QueueBackend_interface interface{
+	// Enqueue stores the event at the end of the queue, returning the "id" datum (uint64, never 0) to acknowledge it by.
+	Enqueue( event Event_struct ) error_report.ErrorReport_struct
+	// Dequeue hides the first visible event for `visibility_timeout` and returns it as the "event" datum along with its "id"; ERROR_CODE_INDEX_OUT_OF_RANGE if none is visible.
+	Dequeue( visibility_timeout time.Duration ) error_report.ErrorReport_struct
+	// Acknowledge deletes a dequeued event; unknown IDs are ignored.
+	Acknowledge( id uint64 ) error_report.ErrorReport_struct
+	// Get returns the visible event at the index as the "event" datum without changing the queue; ERROR_CODE_INDEX_OUT_OF_RANGE past the end.
+	Get( index uint ) error_report.ErrorReport_struct
+	// Remove deletes the visible event at the index, returning it as the "event" datum; ERROR_CODE_INDEX_OUT_OF_RANGE past the end.
+	Remove( index uint ) error_report.ErrorReport_struct
+	// Length returns the number of visible events as the "length" datum (int).
+	Length() error_report.ErrorReport_struct
+}
+
+//# Global Variables
+var(
+	//## Exported Variables
+	//## Private Variables
+);
+
+//# Exported Functions
+/**
+* @fn NewEventDispatcherWithQueueBackend
+* @brief Creates a buffered event dispatcher whose queue is kept in the given backend.
+* @param add_times bool [in] As for `NewEventDispatcher`.
+* @param queue_backend QueueBackend_interface [in] Where to keep the queue; e.g. from `bolt_queue.New` or `sqlite_queue.New`.
+* @param visibility_timeout time.Duration [in] How long an event taken from the queue stays hidden before it's redelivered unless acknowledged; 30 seconds if 0.
+* @return ( return_report error_report.ErrorReport_struct ) 
+* @retval 0 Success
+* @retval 1 Not Supported
+* @retval >1 Error
+*/
+
+// NewEventDispatcherWithQueueBackend creates a buffered event dispatcher whose queue is kept in the given backend instead of memory. `PushEvent` and `Publish` enqueue in the backend; `ShiftEvent` dequeues with `visibility_timeout`, and `ProcessEvent`, and so `ProcessEvents` and the run loop, acknowledge events once their listeners, asynchronous ones included, have returned, so events left by a crash, or whose processing outlasts the timeout, are delivered again: listeners should be idempotent. Events still in the backend from a previous process are dispatched as soon as they're visible; the run loop picks up redelivered events when next woken. `GetEventByIndex`, `RemoveEventByIndex`, and `ExtractEventByIndex` work on the visible events; `InsertEventAtIndex` only appends, and `PopEvent`, queue capacities, and queue modes aren't supported. Events keep their envelope and data but not their context. Events the backend can't decode are deleted by `ShiftEvent`, and so `ProcessEvents` and the run loop, with the report sent to the error sink.
+func NewEventDispatcherWithQueueBackend( add_times bool, queue_backend QueueBackend_interface, visibility_timeout time.Duration ) ( return_report error_report.ErrorReport_struct ){
+	//Variables
+	var event_dispatcher *EventDispatcher_struct;
+	//Parametres
+	if( queue_backend == nil ){
+		return error_report.New( ERROR_CODE_QUEUE_BACKEND_ERROR, map[string]interface{}{ "message": "queue_backend is nil." }, nil );
+	}
+	if( visibility_timeout <= 0 ){
+		visibility_timeout = queue_backend_default_visibility_timeout;
+	}
+	//Function
+	event_dispatcher = newEventDispatcher( add_times, true );
+	event_dispatcher.queue_backend = queue_backend;
+	event_dispatcher.visibility_timeout = visibility_timeout;
+	return_report = error_report.New( 0, map[string]interface{}{ "event_dispatcher": event_dispatcher }, nil );
+	//Return
+	return return_report;
+}
+
+/**
+* @fn DecodeQueuedEvent
+* @brief Decodes an event stored by a queue backend with the named codec.
+* @param codec_name string [in] The name of the codec it was encoded with.
+* @param encoded []byte [in] The encoded event.
+* @return ( return_report error_report.ErrorReport_struct ) 
+* @retval 0 Success
+* @retval 1 Not Supported
+* @retval >1 Error
+*/
+
+// DecodeQueuedEvent decodes an event stored by a queue backend with the named codec, returning it as the "event" datum, or ERROR_CODE_UNDECODABLE_QUEUED_EVENT; storing the codec's name with each event lets a backend's codec be changed while events are queued.
+func DecodeQueuedEvent( codec_name string, encoded []byte ) ( return_report error_report.ErrorReport_struct ){
+	//Variables
+	var function_return error_report.ErrorReport_struct;
+	//Parametres
+	//Function
+	function_return = GetCodec( codec_name );
+	if( function_return.NoError() == true ){
+		return_report = function_return.Data["codec"].(Codec_interface).Decode( encoded );
+	} else{
+		return_report = function_return;
+	}
+	if( return_report.IsError() == true ){
+		return_report = error_report.New( ERROR_CODE_UNDECODABLE_QUEUED_EVENT, map[string]interface{}{ "message": "Couldn't decode a queued event.", "codec": codec_name }, &return_report );
+	}
+	//Return
+	return return_report;
+}
+
+//# Private Functions
+/**
+* @fn enqueueBackendEvent
+* @brief `PushEventCtx` and `InsertEventAtIndexCtx` for a dispatcher with a queue backend.
+* @struct event_dispatcher *EventDispatcher_struct
+* @param ctx context.Context [in] The publisher's context, for the event's envelope.
+* @param event Event_struct [in] The event to enqueue.
+* @param index int [in] Where to insert it; -1 to append.
+* @return ( return_report error_report.ErrorReport_struct ) 
+* @retval 0 Success
+* @retval 1 Not Supported
+* @retval >1 Error
+*/
+
+// enqueueBackendEvent is `PushEventCtx` and `InsertEventAtIndexCtx` for a dispatcher with a queue backend; backends only append, so replacing a queued event isn't supported.
+func (event_dispatcher *EventDispatcher_struct) enqueueBackendEvent( ctx context.Context, event Event_struct, index int ) ( return_report error_report.ErrorReport_struct ){
+	//Variables
+	var function_return error_report.ErrorReport_struct;
+	//Parametres
+	if( index >= 0 ){
+		function_return = event_dispatcher.queue_backend.Length();
+		if( function_return.IsError() == true ){
+			return error_report.New( ERROR_CODE_SUBORDINATE_FUNCTION_ERROR, map[string]interface{}{ "message": "queue_backend.Length() returned an error." }, &function_return );
+		}
+		if( index < function_return.Data["length"].(int) ){
+			return error_report.New( 1, map[string]interface{}{ "message": "Queue backends can only append events.", "index": index }, nil );
+		}
+	}
+	//Function
+	if( event_dispatcher.add_times == true ){
+		event = event.withDatum( "submission_time", time.Now() );
+	}
+	event = inheritEnvelope( ctx, event );
+	function_return = event_dispatcher.queue_backend.Enqueue( event );
+	if( function_return.IsError() == true ){
+		return error_report.New( ERROR_CODE_SUBORDINATE_FUNCTION_ERROR, map[string]interface{}{ "message": "queue_backend.Enqueue() returned an error.", "event": event }, &function_return );
+	}
+	event_dispatcher.mutex.Lock();
+	event_dispatcher.wake_Unsafe();
+	event_dispatcher.mutex.Unlock();
+	return_report = error_report.New( 0, map[string]interface{}{ "id": function_return.Data["id"], "dropped": false }, nil );
+	//Return
+	return return_report;
+}
+
+/**
+* @fn dequeueBackendEvent
+* @brief `ShiftEvent` for a dispatcher with a queue backend.
+* @struct event_dispatcher *EventDispatcher_struct
+* @return ( return_report error_report.ErrorReport_struct ) 
+* @retval 0 Success
+* @retval 1 Not Supported
+* @retval >1 Error
+*/
+
+// dequeueBackendEvent is `ShiftEvent` for a dispatcher with a queue backend: the event is hidden for the visibility timeout until acknowledged. Events which can't be decoded are acknowledged straight away, with the report sent to the error sink, and the next event dequeued instead.
+func (event_dispatcher *EventDispatcher_struct) dequeueBackendEvent() ( return_report error_report.ErrorReport_struct ){
+	//Variables
+	var function_return error_report.ErrorReport_struct;
+	var acknowledge_return error_report.ErrorReport_struct;
+	var event Event_struct;
+	//Parametres
+	//Function
+	function_return = event_dispatcher.queue_backend.Dequeue( event_dispatcher.visibility_timeout );
+	for function_return.CodeEqual( ERROR_CODE_UNDECODABLE_QUEUED_EVENT ) == true {
+		acknowledge_return = event_dispatcher.queue_backend.Acknowledge( function_return.Data["id"].(uint64) );
+		if( acknowledge_return.IsError() == true ){
+			return error_report.New( ERROR_CODE_SUBORDINATE_FUNCTION_ERROR, map[string]interface{}{ "message": "queue_backend.Acknowledge() returned an error for an undecodable event.", "id": function_return.Data["id"] }, &acknowledge_return );
+		}
+		event_dispatcher.sinkError( function_return );
+		function_return = event_dispatcher.queue_backend.Dequeue( event_dispatcher.visibility_timeout );
+	}
+	if( function_return.NoError() == true ){
+		event = function_return.Data["event"].(Event_struct);
+		event.acknowledgement_id = function_return.Data["id"].(uint64);
+		return_report = error_report.New( 0, map[string]interface{}{ "event": event }, nil );
+	} else{
+		return_report = error_report.New( ERROR_CODE_SUBORDINATE_FUNCTION_ERROR, map[string]interface{}{ "message": "queue_backend.Dequeue() returned an error." }, &function_return );
+	}
+	//Return
+	return return_report;
+}
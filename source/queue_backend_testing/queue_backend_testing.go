@@ -0,0 +1,288 @@
+/**
+* @file queue_backend_testing.go
+* @brief Tests shared by the queue backends.
+* @author Anadian
+* @copyright 	Copyright 2019 Canosw
+	Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+software and associated documentation files (the "Software"), to deal in the Software 
+without restriction, including without limitation the rights to use, copy, modify, 
+merge, publish, distribute, sublicense, and/or sell copies of the Software, and to 
+permit persons to whom the Software is furnished to do so, subject to the following 
+conditions:
+	The above copyright notice and this permission notice shall be included in all copies 
+or substantial portions of the Software.
+	THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A 
+PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT 
+HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF 
+CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE 
+OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+// queue_backend_testing contains tests shared by implementations of `event_dispatcher.QueueBackend_interface`, to be called from their own test functions.
+package queue_backend_testing;
+
+//# Dependencies
+import(
+	//## Internal
+	event_dispatcher "github.com/Anadian/event_dispatcher/source"
+	//## Standard
+	"testing"
+	"log"
+	"time"
+	//## External
+	error_report "github.com/Anadian/error_report/source"
+	matchkey "github.com/Anadian/matchkey/source"
+);
+
+//# Constants
+const(
+	//## Exported Constants
+	//## Private Constants
+	///Long enough not to expire while a test runs under the race detector.
+	visibility_timeout time.Duration = 200 * time.Millisecond;
+);
+
+//# Types
+//## Structs
+// undecodable_codec_struct encodes events as JSON under a name `GetCodec` doesn't know, so backends store events they can't decode.
+type undecodable_codec_struct struct{}
+//### Methods
+/**
+* @fn Name
+* @brief Implements `event_dispatcher.Codec_interface`.
+* @struct undecodable_codec undecodable_codec_struct
+* @return string
+*/
+
+// Name implements `event_dispatcher.Codec_interface` with an unregistered name.
+func (undecodable_codec undecodable_codec_struct) Name() string{
+	//Variables
+	//Parametres
+	//Function
+	//Return
+	return "queue_backend_testing:unregistered";
+}
+
+/**
+* @fn Encode
+* @brief Implements `event_dispatcher.Codec_interface`.
+* @struct undecodable_codec undecodable_codec_struct
+* @param event event_dispatcher.Event_struct [in] The event to encode.
+* @return ( return_report error_report.ErrorReport_struct ) 
+* @retval 0 Success
+* @retval 1 Not Supported
+* @retval >1 Error
+*/
+
+// Encode implements `event_dispatcher.Codec_interface` with the JSON codec.
+func (undecodable_codec undecodable_codec_struct) Encode( event event_dispatcher.Event_struct ) ( return_report error_report.ErrorReport_struct ){
+	//Variables
+	//Parametres
+	//Function
+	return_report = event_dispatcher.JSONCodec.Encode( event );
+	//Return
+	return return_report;
+}
+
+/**
+* @fn Decode
+* @brief Implements `event_dispatcher.Codec_interface`.
+* @struct undecodable_codec undecodable_codec_struct
+* @param encoded []byte [in] The encoded event.
+* @return ( return_report error_report.ErrorReport_struct ) 
+* @retval 0 Success
+* @retval 1 Not Supported
+* @retval >1 Error
+*/
+
+// Decode implements `event_dispatcher.Codec_interface` with the JSON codec; backends never get this far, as they find codecs by name.
+func (undecodable_codec undecodable_codec_struct) Decode( encoded []byte ) ( return_report error_report.ErrorReport_struct ){
+	//Variables
+	//Parametres
+	//Function
+	return_report = event_dispatcher.JSONCodec.Decode( encoded );
+	//Return
+	return return_report;
+}
+
+//# Global Variables
+var(
+	//## Exported Variables
+	//## Private Variables
+);
+
+//# Exported Functions
+/**
+* @fn TestQueueBackend
+* @brief Tests ordering, visibility timeouts, acknowledgement, removal, and undecodable events against a queue backend.
+* @param t *testing.T [in] Go stdlib testing object.
+* @param new_queue_backend func( codec event_dispatcher.Codec_interface ) event_dispatcher.QueueBackend_interface [in] Returns a new, empty backend using the codec.
+*/
+
+// TestQueueBackend tests ordering, visibility timeouts, acknowledgement, removal, and undecodable events against a queue backend; `new_queue_backend` must return a new, empty backend each time it's called.
+func TestQueueBackend( t *testing.T, new_queue_backend func( codec event_dispatcher.Codec_interface ) event_dispatcher.QueueBackend_interface ){
+	//Variables
+	var queue_backend event_dispatcher.QueueBackend_interface = new_queue_backend( nil );
+	var function_return error_report.ErrorReport_struct;
+	var name string;
+	var first_id uint64;
+	var undecodable_id uint64;
+	//Parametres
+	//Function
+	for _, name = range []string{ "queue:a", "queue:b", "queue:c" } {
+		function_return = queue_backend.Enqueue( event_dispatcher.NewEvent( name, map[string]interface{}{ "count": 1 } ).Data["event"].(event_dispatcher.Event_struct).WithCorrelationID( "queue-test" ) );
+		if( function_return.IsError() == true || function_return.Data["id"].(uint64) == 0 ){
+			t.Fail();
+			log.Printf("Failure: Enqueue returned: %v\n", function_return);
+		}
+	}
+	function_return = queue_backend.Get( 1 );
+	if( queue_backend.Length().Data["length"] == 3 && function_return.NoError() == true && function_return.Data["event"].(event_dispatcher.Event_struct).Name() == "queue:b" && function_return.Data["event"].(event_dispatcher.Event_struct).CorrelationID() == "queue-test" ){
+		log.Printf("Success: Get returned the event at the index.\n");
+	} else{
+		t.Fail();
+		log.Printf("Failure: Get returned: %v\n", function_return);
+	}
+	function_return = queue_backend.Dequeue( visibility_timeout );
+	if( function_return.NoError() == true && function_return.Data["event"].(event_dispatcher.Event_struct).Name() == "queue:a" && queue_backend.Length().Data["length"] == 2 && queue_backend.Get( 0 ).Data["event"].(event_dispatcher.Event_struct).Name() == "queue:b" ){
+		log.Printf("Success: Dequeue hid the first event.\n");
+	} else{
+		t.Fail();
+		log.Printf("Failure: Dequeue returned: %v\n", function_return);
+	}
+	function_return = queue_backend.Remove( 0 );
+	if( function_return.NoError() == true && function_return.Data["event"].(event_dispatcher.Event_struct).Name() == "queue:b" && queue_backend.Length().Data["length"] == 1 ){
+		log.Printf("Success: Remove deleted the first visible event.\n");
+	} else{
+		t.Fail();
+		log.Printf("Failure: Remove returned: %v\n", function_return);
+	}
+	time.Sleep( 2 * visibility_timeout );
+	if( queue_backend.Length().Data["length"] == 2 && queue_backend.Get( 0 ).Data["event"].(event_dispatcher.Event_struct).Name() == "queue:a" ){
+		log.Printf("Success: The unacknowledged event reappeared in its place.\n");
+	} else{
+		t.Fail();
+		log.Printf("Failure: The unacknowledged event didn't reappear.\n");
+	}
+	function_return = queue_backend.Dequeue( time.Hour );
+	first_id = function_return.Data["id"].(uint64);
+	queue_backend.Acknowledge( first_id );
+	queue_backend.Acknowledge( first_id );
+	function_return = queue_backend.Dequeue( time.Hour );
+	if( function_return.NoError() == true && function_return.Data["event"].(event_dispatcher.Event_struct).Name() == "queue:c" && queue_backend.Dequeue( time.Hour ).CodeEqual( event_dispatcher.ERROR_CODE_INDEX_OUT_OF_RANGE ) == true && queue_backend.Remove( 0 ).CodeEqual( event_dispatcher.ERROR_CODE_INDEX_OUT_OF_RANGE ) == true ){
+		log.Printf("Success: Acknowledged events are gone and an empty queue reports ERROR_CODE_INDEX_OUT_OF_RANGE.\n");
+	} else{
+		t.Fail();
+		log.Printf("Failure: Dequeue returned: %v\n", function_return);
+	}
+	///Undecodable events are reported with their ID, so they can be acknowledged.
+	queue_backend = new_queue_backend( undecodable_codec_struct{} );
+	undecodable_id = queue_backend.Enqueue( event_dispatcher.NewEvent( "queue:undecodable", map[string]interface{}{} ).Data["event"].(event_dispatcher.Event_struct) ).Data["id"].(uint64);
+	function_return = queue_backend.Get( 0 );
+	if( function_return.CodeEqual( event_dispatcher.ERROR_CODE_UNDECODABLE_QUEUED_EVENT ) == true && function_return.Data["id"] == undecodable_id ){
+		log.Printf("Success: Get reported the undecodable event's ID.\n");
+	} else{
+		t.Fail();
+		log.Printf("Failure: Get returned: %v\n", function_return);
+	}
+	function_return = queue_backend.Dequeue( time.Hour );
+	if( function_return.CodeEqual( event_dispatcher.ERROR_CODE_UNDECODABLE_QUEUED_EVENT ) == true && function_return.Data["id"] == undecodable_id ){
+		queue_backend.Acknowledge( undecodable_id );
+	}
+	if( function_return.Data["id"] == undecodable_id && queue_backend.Length().Data["length"] == 0 && queue_backend.Dequeue( 0 ).CodeEqual( event_dispatcher.ERROR_CODE_INDEX_OUT_OF_RANGE ) == true ){
+		log.Printf("Success: Dequeue reported the undecodable event's ID, which acknowledged it.\n");
+	} else{
+		t.Fail();
+		log.Printf("Failure: Dequeue returned: %v\n", function_return);
+	}
+	//Return
+}
+
+/**
+* @fn TestEventDispatcher
+* @brief Tests a dispatcher keeping its queue in a backend: acknowledgement after processing, redelivery, undecodable events, and the unsupported operations.
+* @param t *testing.T [in] Go stdlib testing object.
+* @param new_queue_backend func( codec event_dispatcher.Codec_interface ) event_dispatcher.QueueBackend_interface [in] Returns a new, empty backend using the codec.
+*/
+
+// TestEventDispatcher tests a dispatcher keeping its queue in a backend: acknowledgement after processing, redelivery, undecodable events, and the unsupported operations; `new_queue_backend` must return a new, empty backend each time it's called.
+func TestEventDispatcher( t *testing.T, new_queue_backend func( codec event_dispatcher.Codec_interface ) event_dispatcher.QueueBackend_interface ){
+	//Variables
+	var queue_backend event_dispatcher.QueueBackend_interface = new_queue_backend( nil );
+	var dispatcher *event_dispatcher.EventDispatcher_struct;
+	var function_return error_report.ErrorReport_struct;
+	var key matchkey.MatchKey_struct;
+	var names []string;
+	var sunk_reports []error_report.ErrorReport_struct;
+	//Parametres
+	//Function
+	dispatcher = event_dispatcher.NewEventDispatcherWithQueueBackend( false, queue_backend, visibility_timeout ).Data["event_dispatcher"].(*event_dispatcher.EventDispatcher_struct);
+	key, _ = matchkey.New( matchkey.MATCHKEY_TYPE_STRING, "backend:test" );
+	dispatcher.AddEventListener( event_dispatcher.NewEventListener( key, false, func( event event_dispatcher.Event_struct, args ...interface{} ){
+		value, _ := event.Get( "name" );
+		names = append(names, value.(string));
+	} ).Data["event_listener"].(event_dispatcher.EventListener_struct) );
+	dispatcher.PushEvent( event_dispatcher.NewEvent( "backend:test", map[string]interface{}{ "name": "first" } ).Data["event"].(event_dispatcher.Event_struct) );
+	dispatcher.InsertEventAtIndex( event_dispatcher.NewEvent( "backend:test", map[string]interface{}{ "name": "second" } ).Data["event"].(event_dispatcher.Event_struct), 1 );
+	if( dispatcher.InsertEventAtIndex( event_dispatcher.NewEvent( "backend:test", map[string]interface{}{} ).Data["event"].(event_dispatcher.Event_struct), 0 ).CodeEqual( 1 ) == true && dispatcher.PopEvent().CodeEqual( 1 ) == true ){
+		log.Printf("Success: Inserting before the end and popping aren't supported.\n");
+	} else{
+		t.Fail();
+		log.Printf("Failure: InsertEventAtIndex or PopEvent didn't report Not Supported.\n");
+	}
+	if( dispatcher.SetQueueCapacity( 1, event_dispatcher.OVERFLOW_STRATEGY_ERROR ).CodeEqual( 1 ) == true && dispatcher.SetQueueMode( event_dispatcher.QUEUE_MODE_PRIORITY, 0 ).CodeEqual( 1 ) == true ){
+		log.Printf("Success: Queue capacities and queue modes aren't supported.\n");
+	} else{
+		t.Fail();
+		log.Printf("Failure: SetQueueCapacity or SetQueueMode didn't report Not Supported.\n");
+	}
+	if( dispatcher.GetEventByIndex( 1 ).Data["event"].(event_dispatcher.Event_struct).Name() == "backend:test" && dispatcher.GetEventByIndex( 2 ).CodeEqual( event_dispatcher.ERROR_CODE_INDEX_OUT_OF_RANGE ) == true ){
+		log.Printf("Success: GetEventByIndex reads from the backend.\n");
+	} else{
+		t.Fail();
+		log.Printf("Failure: GetEventByIndex didn't read from the backend.\n");
+	}
+	///Taken but never processed, as if the process crashed.
+	dispatcher.ShiftEvent();
+	function_return = dispatcher.ProcessEvents();
+	if( function_return.Data["processed"] == 1 && len(names) == 1 && names[0] == "second" ){
+		log.Printf("Success: Only the visible event was processed.\n");
+	} else{
+		t.Fail();
+		log.Printf("Failure: ProcessEvents returned %v; names: %v\n", function_return, names);
+	}
+	time.Sleep( 2 * visibility_timeout );
+	dispatcher.ProcessEvents();
+	function_return = queue_backend.Length();
+	if( len(names) == 2 && names[1] == "first" && function_return.Data["length"] == 0 ){
+		log.Printf("Success: The unacknowledged event was redelivered after its visibility timeout, and processed events were acknowledged.\n");
+	} else{
+		t.Fail();
+		log.Printf("Failure: names: %v; length: %v\n", names, function_return.Data["length"]);
+	}
+	///Undecodable events are deleted rather than redelivered forever.
+	dispatcher = event_dispatcher.NewEventDispatcherWithQueueBackend( false, new_queue_backend( undecodable_codec_struct{} ), visibility_timeout ).Data["event_dispatcher"].(*event_dispatcher.EventDispatcher_struct);
+	dispatcher.SetErrorSink( func( report error_report.ErrorReport_struct ){
+		sunk_reports = append(sunk_reports, report);
+	} );
+	dispatcher.PushEvent( event_dispatcher.NewEvent( "backend:test", map[string]interface{}{ "name": "undecodable" } ).Data["event"].(event_dispatcher.Event_struct) );
+	dispatcher.PushEvent( event_dispatcher.NewEvent( "backend:test", map[string]interface{}{ "name": "undecodable" } ).Data["event"].(event_dispatcher.Event_struct) );
+	function_return = dispatcher.ProcessEvents();
+	if( function_return.Data["processed"] == 0 && len(sunk_reports) == 2 && sunk_reports[0].CodeEqual( event_dispatcher.ERROR_CODE_UNDECODABLE_QUEUED_EVENT ) == true && dispatcher.GetEventByIndex( 0 ).CodeEqual( event_dispatcher.ERROR_CODE_INDEX_OUT_OF_RANGE ) == true ){
+		log.Printf("Success: Undecodable events were deleted and reported to the error sink.\n");
+	} else{
+		t.Fail();
+		log.Printf("Failure: ProcessEvents returned %v; error sink received: %v\n", function_return, sunk_reports);
+	}
+	time.Sleep( 2 * visibility_timeout );
+	if( dispatcher.ShiftEvent().IsError() == true && len(sunk_reports) == 2 ){
+		log.Printf("Success: Undecodable events weren't redelivered.\n");
+	} else{
+		t.Fail();
+		log.Printf("Failure: Undecodable events were redelivered.\n");
+	}
+	//Return
+}
+
+//# Private Functions
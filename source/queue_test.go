@@ -0,0 +1,194 @@
+/**
+* @file queue_test.go
+* @brief Contains test and benchmark functions for `queue.go`.
+* @author Anadian
+* @copyright 	Copyright 2019 Canosw
+	Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+software and associated documentation files (the "Software"), to deal in the Software 
+without restriction, including without limitation the rights to use, copy, modify, 
+merge, publish, distribute, sublicense, and/or sell copies of the Software, and to 
+permit persons to whom the Software is furnished to do so, subject to the following 
+conditions:
+	The above copyright notice and this permission notice shall be included in all copies 
+or substantial portions of the Software.
+	THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A 
+PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT 
+HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF 
+CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE 
+OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+// queue_test contains test and benchmark functions for `queue.go`.
+package event_dispatcher;
+
+//# Dependencies
+import(
+	//## Internal
+	//## Standard
+	"testing"
+	"log"
+	"math/rand"
+	"strconv"
+	//## External
+);
+
+//# Types
+//## Structs
+// slice_queue_struct is the re-slicing queue `EventDispatcher_struct` used before the ring buffer, kept here as a benchmark baseline.
+type slice_queue_struct struct{
+	events_slice []Event_struct
+}
+//### Methods
+func (slice_queue *slice_queue_struct) Length() int{ return len(slice_queue.events_slice); }
+func (slice_queue *slice_queue_struct) Get( index int ) Event_struct{ return slice_queue.events_slice[index]; }
+func (slice_queue *slice_queue_struct) Insert( index int, event Event_struct ){
+	slice_queue.events_slice = append(slice_queue.events_slice, Event_struct{});
+	copy(slice_queue.events_slice[(index+1):], slice_queue.events_slice[index:]);
+	slice_queue.events_slice[index] = event;
+}
+func (slice_queue *slice_queue_struct) Remove( index int ) Event_struct{
+	var event Event_struct = slice_queue.events_slice[index];
+	slice_queue.events_slice = append(slice_queue.events_slice[:index], slice_queue.events_slice[(index+1):]...);
+	return event;
+}
+func (slice_queue *slice_queue_struct) PushBack( event Event_struct ){ slice_queue.events_slice = append(slice_queue.events_slice, event); }
+func (slice_queue *slice_queue_struct) PopFront() Event_struct{
+	var event Event_struct = slice_queue.events_slice[0];
+	slice_queue.events_slice = append(slice_queue.events_slice[:0], slice_queue.events_slice[1:]...);
+	return event;
+}
+func (slice_queue *slice_queue_struct) PopBack() Event_struct{ return slice_queue.Remove( len(slice_queue.events_slice) - 1 ); }
+func (slice_queue *slice_queue_struct) Clear(){ slice_queue.events_slice = nil; }
+
+//# Exported Functions
+/**
+* @fn TestRingBuffer
+* @brief Tests the ring buffer against the slice queue with a random sequence of operations.
+* @param t *testing.T [in] Go stdlib testing object.
+*/
+
+// TestRingBuffer tests the ring buffer against the slice queue with a random sequence of operations.
+func TestRingBuffer( t *testing.T ){
+	//Variables
+	var ring_buffer event_queue_interface = newRingBuffer();
+	var slice_queue event_queue_interface = &slice_queue_struct{};
+	var random *rand.Rand = rand.New( rand.NewSource( 1 ) );
+	var event Event_struct;
+	var index int;
+	var i, j int;
+	var mismatch bool;
+	//Parametres
+	//Function
+	for i = 0; i < 20000 && mismatch == false; i++ {
+		event = Event_struct{ name: strconv.Itoa( i ) };
+		switch( random.Intn( 6 ) ){
+			case 0, 1:
+				ring_buffer.PushBack( event );
+				slice_queue.PushBack( event );
+			case 2:
+				index = random.Intn( slice_queue.Length() + 1 );
+				ring_buffer.Insert( index, event );
+				slice_queue.Insert( index, event );
+			case 3:
+				if( slice_queue.Length() > 0 ){
+					index = random.Intn( slice_queue.Length() );
+					mismatch = ( ring_buffer.Remove( index ).name != slice_queue.Remove( index ).name );
+				}
+			case 4:
+				if( slice_queue.Length() > 0 ){
+					mismatch = ( ring_buffer.PopFront().name != slice_queue.PopFront().name );
+				}
+			case 5:
+				if( slice_queue.Length() > 0 ){
+					mismatch = ( ring_buffer.PopBack().name != slice_queue.PopBack().name );
+				}
+		}
+		mismatch = mismatch || ( ring_buffer.Length() != slice_queue.Length() );
+		for j = 0; j < slice_queue.Length() && mismatch == false; j++ {
+			mismatch = ( ring_buffer.Get( j ).name != slice_queue.Get( j ).name );
+		}
+	}
+	if( mismatch == false ){
+		log.Printf("Success: Ring buffer matched the slice queue over %d operations.\n", i);
+	} else{
+		t.Fail();
+		log.Printf("Failure: Ring buffer diverged from the slice queue at operation %d.\n", i);
+	}
+	//Return
+}
+
+/**
+* @fn BenchmarkDrainRingBuffer
+* @brief Benchmarks pushing then shifting 100,000 events through the ring buffer.
+* @param b *testing.B [in] Go stdlib benchmarking object.
+*/
+
+// BenchmarkDrainRingBuffer benchmarks pushing then shifting 100,000 events through the ring buffer.
+func BenchmarkDrainRingBuffer( b *testing.B ){
+	benchmarkDrain( b, func() event_queue_interface{ return newRingBuffer(); } );
+}
+
+/**
+* @fn BenchmarkDrainSliceQueue
+* @brief Benchmarks pushing then shifting 100,000 events through the old re-slicing queue.
+* @param b *testing.B [in] Go stdlib benchmarking object.
+*/
+
+// BenchmarkDrainSliceQueue benchmarks pushing then shifting 100,000 events through the old re-slicing queue.
+func BenchmarkDrainSliceQueue( b *testing.B ){
+	benchmarkDrain( b, func() event_queue_interface{ return &slice_queue_struct{}; } );
+}
+
+/**
+* @fn BenchmarkDrainEventDispatcher
+* @brief Benchmarks `PushEvent` then `ShiftEvent` for 100,000 events on a dispatcher.
+* @param b *testing.B [in] Go stdlib benchmarking object.
+*/
+
+// BenchmarkDrainEventDispatcher benchmarks `PushEvent` then `ShiftEvent` for 100,000 events on a dispatcher.
+func BenchmarkDrainEventDispatcher( b *testing.B ){
+	//Variables
+	var event_dispatcher *EventDispatcher_struct;
+	var event Event_struct = Event_struct{ name: "benchmark", data: map[string]interface{}{} };
+	var i, j int;
+	//Parametres
+	//Function
+	for i = 0; i < b.N; i++ {
+		event_dispatcher = newEventDispatcher( false, true );
+		for j = 0; j < 100000; j++ {
+			event_dispatcher.PushEvent( event );
+		}
+		for event_dispatcher.ShiftEvent().NoError() == true {
+		}
+	}
+	//Return
+}
+
+//# Private Functions
+/**
+* @fn benchmarkDrain
+* @brief Pushes then shifts 100,000 events through a queue made by `new_queue`, `b.N` times.
+* @param b *testing.B [in] Go stdlib benchmarking object.
+* @param new_queue func() event_queue_interface [in] Creates the queue to benchmark.
+*/
+
+// benchmarkDrain pushes then shifts 100,000 events through a queue made by `new_queue`, `b.N` times.
+func benchmarkDrain( b *testing.B, new_queue func() event_queue_interface ){
+	//Variables
+	var queue event_queue_interface;
+	var event Event_struct = Event_struct{ name: "benchmark" };
+	var i, j int;
+	//Parametres
+	//Function
+	for i = 0; i < b.N; i++ {
+		queue = new_queue();
+		for j = 0; j < 100000; j++ {
+			queue.PushBack( event );
+		}
+		for queue.Length() > 0 {
+			queue.PopFront();
+		}
+	}
+	//Return
+}
@@ -0,0 +1,536 @@
+/**
+* @file recorder.go
+* @brief Records the events a dispatcher processes, to replay them later at their original pace, faster, or a step at a time.
+* @author Anadian
+* @copyright 	Copyright 2019 Canosw
+	Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+software and associated documentation files (the "Software"), to deal in the Software 
+without restriction, including without limitation the rights to use, copy, modify, 
+merge, publish, distribute, sublicense, and/or sell copies of the Software, and to 
+permit persons to whom the Software is furnished to do so, subject to the following 
+conditions:
+	The above copyright notice and this permission notice shall be included in all copies 
+or substantial portions of the Software.
+	THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A 
+PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT 
+HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF 
+CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE 
+OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+package event_dispatcher;
+
+//# Dependencies
+import(
+	//## Internal
+	//## Standard
+	"context"
+	"encoding/binary"
+	"io"
+	"sync"
+	"time"
+	//## External
+	error_report "github.com/Anadian/error_report/source"
+	matchkey "github.com/Anadian/matchkey/source"
+);
+
+//# Constants
+const(
+	//## Exported Constants
+	//### Errors
+	ERROR_CODE_RECORDING_ERROR int64 = 42;
+	//## Private Constants
+	recorded_time_length int = 8; //The recorded time in Unix nanoseconds, before each saved event.
+);
+
+//# Types
+//## Structs
+// RecordingFilter_struct selects recorded events; the zero value selects them all.
+type RecordingFilter_struct struct{
+	Name_matchkey matchkey.MatchKey_struct //Events whose names match; any name if unspecified.
+	From_time time.Time //Events at or after this time; no lower bound if zero.
+	To_time time.Time //Events before this time; no upper bound if zero.
+}
+// Recorder_struct keeps the events processed by the dispatchers it's given to with `SetRecorder`, in the order processed, along with the time each was recorded. It's safe for concurrent use.
+type Recorder_struct struct{
+	mutex sync.Mutex
+	recorded_events_slice []recorded_event_struct
+	limit int
+}
+// recorded_event_struct is a recorded event and the time it was recorded; kept beside the event so its data is as processed.
+type recorded_event_struct struct{
+	event Event_struct
+	recorded_time time.Time
+}
+// Replay_struct feeds a selection of recorded events to a dispatcher, from `Recorder.NewReplay`.
+type Replay_struct struct{
+	mutex sync.Mutex
+	event_dispatcher *EventDispatcher_struct
+	recorded_events_slice []recorded_event_struct
+	next int
+}
+//### Methods
+/**
+* @fn Events
+* @brief Returns the recorded events the filter selects, in the order processed.
+* @struct recorder *Recorder_struct
+* @param filter RecordingFilter_struct [in] Which events to return.
+* @return []Event_struct
+*/
+
+// Events returns the recorded events the filter selects, in the order processed. An event's time, for the filter and for pacing replays, is its "submission_time" stamp if it was queued, else its "creation_time", else the time it was recorded.
+func (recorder *Recorder_struct) Events( filter RecordingFilter_struct ) []Event_struct{
+	//Variables
+	var recorded_events []recorded_event_struct = recorder.selectRecordedEvents( filter );
+	var events []Event_struct = make([]Event_struct, len(recorded_events));
+	var i int;
+	//Parametres
+	//Function
+	for i = 0; i < len(recorded_events); i++ {
+		events[i] = recorded_events[i].event;
+	}
+	//Return
+	return events;
+}
+
+/**
+* @fn Clear
+* @brief Discards the recorded events.
+* @struct recorder *Recorder_struct
+*/
+
+// Clear discards the recorded events.
+func (recorder *Recorder_struct) Clear(){
+	//Variables
+	//Parametres
+	//Function
+	recorder.mutex.Lock();
+	recorder.recorded_events_slice = nil;
+	recorder.mutex.Unlock();
+	//Return
+}
+
+/**
+* @fn Save
+* @brief Writes the recorded events to `writer` with the given codec, for `LoadRecorder`.
+* @struct recorder *Recorder_struct
+* @param writer io.Writer [in] Where to write them.
+* @param codec Codec_interface [in] How to encode them; the JSON codec if nil.
+* @return ( return_report error_report.ErrorReport_struct ) 
+* @retval 0 Success
+* @retval 1 Not Supported
+* @retval >1 Error
+*/
+
+// Save writes the recorded events, and the times they were recorded, to `writer` with the given codec, for `LoadRecorder` to read back elsewhere, such as on a developer's machine. Their data's types must be registered as `RegisterPayloadType` describes; the "saved" datum counts the events written.
+func (recorder *Recorder_struct) Save( writer io.Writer, codec Codec_interface ) ( return_report error_report.ErrorReport_struct ){
+	//Variables
+	var recorded_events []recorded_event_struct;
+	var function_return error_report.ErrorReport_struct;
+	var length_bytes []byte = make([]byte, 4);
+	var record []byte;
+	var write_error error;
+	var i int;
+	//Parametres
+	if( codec == nil ){
+		codec = JSONCodec;
+	}
+	//Function
+	recorded_events = recorder.selectRecordedEvents( RecordingFilter_struct{} );
+	write_error = writeRecordingRecord( writer, length_bytes, []byte(codec.Name()) );
+	for i = 0; i < len(recorded_events) && write_error == nil; i++ {
+		function_return = codec.Encode( recorded_events[i].event );
+		if( function_return.IsError() == true ){
+			return error_report.New( ERROR_CODE_RECORDING_ERROR, map[string]interface{}{ "message": "Couldn't encode a recorded event.", "event": recorded_events[i].event, "saved": i }, &function_return );
+		}
+		///Each record is the recorded time followed by the encoded event.
+		record = make([]byte, recorded_time_length, recorded_time_length + len(function_return.Data["encoded"].([]byte)));
+		binary.BigEndian.PutUint64( record, uint64(recorded_events[i].recorded_time.UnixNano()) );
+		record = append(record, function_return.Data["encoded"].([]byte)...);
+		write_error = writeRecordingRecord( writer, length_bytes, record );
+	}
+	if( write_error == nil ){
+		return_report = error_report.New( 0, map[string]interface{}{ "saved": len(recorded_events) }, nil );
+	} else{
+		return_report = error_report.New( ERROR_CODE_RECORDING_ERROR, map[string]interface{}{ "message": write_error.Error(), "error": write_error }, nil );
+	}
+	//Return
+	return return_report;
+}
+
+/**
+* @fn NewReplay
+* @brief Returns a replay of the recorded events the filter selects into the given dispatcher.
+* @struct recorder *Recorder_struct
+* @param event_dispatcher *EventDispatcher_struct [in] The dispatcher to process them; typically a fresh one with the listeners under investigation.
+* @param filter RecordingFilter_struct [in] Which events to replay.
+* @return ( return_report error_report.ErrorReport_struct ) 
+* @retval 0 Success
+* @retval 1 Not Supported
+* @retval >1 Error
+*/
+
+// NewReplay returns a replay, the "replay" datum, of the recorded events the filter selects, as they are now, into the given dispatcher; typically a fresh one with the listeners under investigation, which shouldn't have this recorder.
+func (recorder *Recorder_struct) NewReplay( event_dispatcher *EventDispatcher_struct, filter RecordingFilter_struct ) ( return_report error_report.ErrorReport_struct ){
+	//Variables
+	//Parametres
+	if( event_dispatcher == nil ){
+		return error_report.New( ERROR_CODE_RECORDING_ERROR, map[string]interface{}{ "message": "event_dispatcher is nil." }, nil );
+	}
+	//Function
+	return_report = error_report.New( 0, map[string]interface{}{ "replay": &Replay_struct{ event_dispatcher: event_dispatcher, recorded_events_slice: recorder.selectRecordedEvents( filter ) } }, nil );
+	//Return
+	return return_report;
+}
+
+/**
+* @fn record
+* @brief Keeps the event with the current time.
+* @struct recorder *Recorder_struct
+* @param event Event_struct [in] The event being processed.
+*/
+
+// record keeps the event with the current time, discarding the oldest if that passes the limit. The event's context and acknowledgement ID are dropped: the latter belongs to this dispatcher's write-ahead log or queue backend, and replaying it into another would acknowledge an unrelated event there.
+func (recorder *Recorder_struct) record( event Event_struct ){
+	//Variables
+	var recorded_event recorded_event_struct = recorded_event_struct{ event: event, recorded_time: time.Now() };
+	//Parametres
+	//Function
+	recorded_event.event.ctx = nil;
+	recorded_event.event.propagation = nil;
+	recorded_event.event.acknowledgement_id = 0;
+	recorder.mutex.Lock();
+	if( recorder.limit > 0 && len(recorder.recorded_events_slice) >= recorder.limit ){
+		recorder.recorded_events_slice = recorder.recorded_events_slice[len(recorder.recorded_events_slice) - recorder.limit + 1:];
+	}
+	recorder.recorded_events_slice = append(recorder.recorded_events_slice, recorded_event);
+	recorder.mutex.Unlock();
+	//Return
+}
+
+/**
+* @fn selectRecordedEvents
+* @brief Returns the recorded events the filter selects, with their recorded times, in the order processed.
+* @struct recorder *Recorder_struct
+* @param filter RecordingFilter_struct [in] Which events to return.
+* @return []recorded_event_struct
+*/
+
+// selectRecordedEvents returns the recorded events the filter selects, with their recorded times, in the order processed; see `Events`.
+func (recorder *Recorder_struct) selectRecordedEvents( filter RecordingFilter_struct ) []recorded_event_struct{
+	//Variables
+	var recorded_events []recorded_event_struct = []recorded_event_struct{};
+	var recorded_event recorded_event_struct;
+	var event_time time.Time;
+	var match bool;
+	//Parametres
+	//Function
+	recorder.mutex.Lock();
+	for _, recorded_event = range recorder.recorded_events_slice {
+		event_time = recorded_event.eventTime();
+		if( filter.Name_matchkey.Matchkey_type == matchkey.MATCHKEY_TYPE_UNSPECIFIED ){
+			match = true;
+		} else{
+			match, _ = filter.Name_matchkey.Match( recorded_event.event.name );
+		}
+		if( match == true && ( filter.From_time.IsZero() == true || event_time.Before( filter.From_time ) == false ) && ( filter.To_time.IsZero() == true || event_time.Before( filter.To_time ) == true ) ){
+			recorded_events = append(recorded_events, recorded_event);
+		}
+	}
+	recorder.mutex.Unlock();
+	//Return
+	return recorded_events;
+}
+
+/**
+* @fn eventTime
+* @brief Returns the time a recorded event is filtered and paced by.
+* @struct recorded_event recorded_event_struct
+* @return time.Time
+*/
+
+// eventTime returns the time a recorded event is filtered and paced by: its "submission_time" stamp, else its "creation_time", else the time it was recorded.
+func (recorded_event recorded_event_struct) eventTime() time.Time{
+	//Variables
+	var event_time time.Time;
+	var ok bool;
+	//Parametres
+	//Function
+	event_time, ok = recorded_event.event.data["submission_time"].(time.Time);
+	if( ok == false ){
+		event_time, ok = recorded_event.event.data["creation_time"].(time.Time);
+	}
+	if( ok == false ){
+		event_time = recorded_event.recorded_time;
+	}
+	//Return
+	return event_time;
+}
+
+/**
+* @fn Step
+* @brief Processes the next event of the replay.
+* @struct replay *Replay_struct
+* @return ( return_report error_report.ErrorReport_struct ) 
+* @retval 0 Success
+* @retval 1 Not Supported
+* @retval >1 Error
+*/
+
+// Step processes the next event of the replay with `ProcessEvent`, returning its report with the number of events "remaining"; ERROR_CODE_INDEX_OUT_OF_RANGE once the replay is finished.
+func (replay *Replay_struct) Step() ( return_report error_report.ErrorReport_struct ){
+	//Variables
+	var event Event_struct;
+	var remaining int;
+	//Parametres
+	//Function
+	replay.mutex.Lock();
+	if( replay.next >= len(replay.recorded_events_slice) ){
+		replay.mutex.Unlock();
+		return error_report.New( ERROR_CODE_INDEX_OUT_OF_RANGE, map[string]interface{}{ "message": "The replay is finished.", "remaining": 0 }, nil );
+	}
+	event = replay.recorded_events_slice[replay.next].event;
+	replay.next++;
+	remaining = len(replay.recorded_events_slice) - replay.next;
+	replay.mutex.Unlock();
+	return_report = replay.event_dispatcher.ProcessEvent( event );
+	return_report.Data["remaining"] = remaining;
+	//Return
+	return return_report;
+}
+
+/**
+* @fn Run
+* @brief Processes the rest of the replay, spacing the events by their recorded times divided by `speed`.
+* @struct replay *Replay_struct
+* @param ctx context.Context [in] The replay stops when it's done.
+* @param speed float64 [in] 1 for the original pace, 10 for ten times faster; 0 for no delays.
+* @return ( return_report error_report.ErrorReport_struct ) 
+* @retval 0 Success
+* @retval 1 Not Supported
+* @retval >1 Error
+*/
+
+// Run processes the rest of the replay, waiting between events for the time between their recorded times divided by `speed`: 1 reproduces the original pace, 10 is ten times faster, and 0 replays without delays. The "replayed" and "errors" data count the events processed and those whose processing failed; the replay stops, with ERROR_CODE_REPLAY_ERROR, if `ctx` is done, and can be resumed with `Step` or `Run`.
+func (replay *Replay_struct) Run( ctx context.Context, speed float64 ) ( return_report error_report.ErrorReport_struct ){
+	//Variables
+	var function_return error_report.ErrorReport_struct;
+	var previous_time time.Time;
+	var event_time time.Time;
+	var timer *time.Timer;
+	var replayed int;
+	var errors int;
+	var waiting bool = true;
+	//Parametres
+	if( ctx == nil ){
+		ctx = context.Background();
+	}
+	//Function
+	for ctx.Err() == nil && replay.Remaining() > 0 {
+		replay.mutex.Lock();
+		event_time = replay.recorded_events_slice[replay.next].eventTime();
+		replay.mutex.Unlock();
+		if( speed > 0 && previous_time.IsZero() == false && event_time.After( previous_time ) == true ){
+			timer = time.NewTimer( time.Duration( float64(event_time.Sub( previous_time )) / speed ) );
+			select{
+				case <-timer.C:
+				case <-ctx.Done():
+					timer.Stop();
+					waiting = false;
+			}
+			if( waiting == false ){
+				break;
+			}
+		}
+		previous_time = event_time;
+		function_return = replay.Step();
+		if( function_return.CodeEqual( ERROR_CODE_INDEX_OUT_OF_RANGE ) == true ){
+			break;
+		}
+		replayed++;
+		if( function_return.IsError() == true ){
+			errors++;
+		}
+	}
+	if( ctx.Err() == nil ){
+		return_report = error_report.New( 0, map[string]interface{}{ "replayed": replayed, "errors": errors }, nil );
+	} else{
+		return_report = error_report.New( ERROR_CODE_REPLAY_ERROR, map[string]interface{}{ "message": ctx.Err().Error(), "context_error": ctx.Err(), "replayed": replayed, "errors": errors }, nil );
+	}
+	//Return
+	return return_report;
+}
+
+/**
+* @fn Remaining
+* @brief Returns the number of events left to replay.
+* @struct replay *Replay_struct
+* @return int
+*/
+
+// Remaining returns the number of events left to replay.
+func (replay *Replay_struct) Remaining() int{
+	//Variables
+	var remaining int;
+	//Parametres
+	//Function
+	replay.mutex.Lock();
+	remaining = len(replay.recorded_events_slice) - replay.next;
+	replay.mutex.Unlock();
+	//Return
+	return remaining;
+}
+
+/**
+* @fn SetRecorder
+* @brief Records every event the dispatcher processes with the given recorder.
+* @struct event_dispatcher *EventDispatcher_struct
+* @param recorder *Recorder_struct [in] The recorder; nil to stop recording.
+* @return ( return_report error_report.ErrorReport_struct ) 
+* @retval 0 Success
+* @retval 1 Not Supported
+* @retval >1 Error
+*/
+
+// SetRecorder records every event the dispatcher processes, whether queued or not, with the given recorder; nil stops recording. Events are recorded as `ProcessEvent` receives them, before their listeners are called.
+func (event_dispatcher *EventDispatcher_struct) SetRecorder( recorder *Recorder_struct ) ( return_report error_report.ErrorReport_struct ){
+	//Variables
+	//Parametres
+	//Function
+	event_dispatcher.mutex.Lock();
+	event_dispatcher.recorder = recorder;
+	event_dispatcher.mutex.Unlock();
+	return_report = error_report.New( 0, map[string]interface{}{}, nil );
+	//Return
+	return return_report;
+}
+
+//# Global Variables
+var(
+	//## Exported Variables
+	//## Private Variables
+);
+
+//# Exported Functions
+/**
+* @fn NewRecorder
+* @brief Creates an empty recorder keeping at most `limit` events.
+* @param limit uint [in] How many of the most recent events to keep; 0 for no limit.
+* @return ( return_report error_report.ErrorReport_struct ) 
+* @retval 0 Success
+* @retval 1 Not Supported
+* @retval >1 Error
+*/
+
+// NewRecorder creates an empty recorder, the "recorder" datum, keeping the most recent `limit` events; 0 for no limit, which suits short sessions rather than long-running processes.
+func NewRecorder( limit uint ) ( return_report error_report.ErrorReport_struct ){
+	//Variables
+	//Parametres
+	//Function
+	return_report = error_report.New( 0, map[string]interface{}{ "recorder": &Recorder_struct{ limit: int(limit) } }, nil );
+	//Return
+	return return_report;
+}
+
+/**
+* @fn LoadRecorder
+* @brief Reads events written by `Recorder.Save` into a new recorder.
+* @param reader io.Reader [in] What `Save` wrote.
+* @return ( return_report error_report.ErrorReport_struct ) 
+* @retval 0 Success
+* @retval 1 Not Supported
+* @retval >1 Error
+*/
+
+// LoadRecorder reads events written by `Recorder.Save` into a new recorder, the "recorder" datum, with no limit, so they can be replayed. The codec `Save` used must be registered, as must their data's types.
+func LoadRecorder( reader io.Reader ) ( return_report error_report.ErrorReport_struct ){
+	//Variables
+	var recorder *Recorder_struct = &Recorder_struct{};
+	var recorded_event recorded_event_struct;
+	var codec Codec_interface;
+	var record []byte;
+	var function_return error_report.ErrorReport_struct;
+	var read_error error;
+	//Parametres
+	//Function
+	record, read_error = readRecordingRecord( reader );
+	if( read_error != nil ){
+		return error_report.New( ERROR_CODE_RECORDING_ERROR, map[string]interface{}{ "message": "Couldn't read the recording's codec.", "error": read_error }, nil );
+	}
+	function_return = GetCodec( string(record) );
+	if( function_return.IsError() == true ){
+		return error_report.New( ERROR_CODE_RECORDING_ERROR, map[string]interface{}{ "message": "The recording's codec isn't registered." }, &function_return );
+	}
+	codec = function_return.Data["codec"].(Codec_interface);
+	for record, read_error = readRecordingRecord( reader ); read_error == nil; record, read_error = readRecordingRecord( reader ) {
+		if( len(record) < recorded_time_length ){
+			return error_report.New( ERROR_CODE_RECORDING_ERROR, map[string]interface{}{ "message": "A recorded event is missing its recorded time.", "loaded": len(recorder.recorded_events_slice) }, nil );
+		}
+		function_return = codec.Decode( record[recorded_time_length:] );
+		if( function_return.IsError() == true ){
+			return error_report.New( ERROR_CODE_RECORDING_ERROR, map[string]interface{}{ "message": "Couldn't decode a recorded event.", "loaded": len(recorder.recorded_events_slice) }, &function_return );
+		}
+		recorded_event = recorded_event_struct{ event: function_return.Data["event"].(Event_struct), recorded_time: time.Unix( 0, int64(binary.BigEndian.Uint64( record[0:recorded_time_length] )) ) };
+		recorded_event.event.ctx = nil;
+		recorded_event.event.propagation = nil;
+		recorded_event.event.acknowledgement_id = 0;
+		recorder.recorded_events_slice = append(recorder.recorded_events_slice, recorded_event);
+	}
+	if( read_error == io.EOF ){
+		return_report = error_report.New( 0, map[string]interface{}{ "recorder": recorder }, nil );
+	} else{
+		return_report = error_report.New( ERROR_CODE_RECORDING_ERROR, map[string]interface{}{ "message": read_error.Error(), "error": read_error, "loaded": len(recorder.recorded_events_slice) }, nil );
+	}
+	//Return
+	return return_report;
+}
+
+//# Private Functions
+/**
+* @fn writeRecordingRecord
+* @brief Writes a length-prefixed record.
+* @param writer io.Writer [in] Where to write it.
+* @param length_bytes []byte [in] A 4-byte scratch buffer.
+* @param record []byte [in] The record.
+* @return error
+*/
+
+// writeRecordingRecord writes a record prefixed by its big-endian 32-bit length.
+func writeRecordingRecord( writer io.Writer, length_bytes []byte, record []byte ) error{
+	//Variables
+	var write_error error;
+	//Parametres
+	//Function
+	binary.BigEndian.PutUint32( length_bytes, uint32(len(record)) );
+	_, write_error = writer.Write( length_bytes );
+	if( write_error == nil ){
+		_, write_error = writer.Write( record );
+	}
+	//Return
+	return write_error;
+}
+
+/**
+* @fn readRecordingRecord
+* @brief Reads a length-prefixed record.
+* @param reader io.Reader [in] What to read it from.
+* @return ( record []byte, read_error error ) io.EOF at a clean end.
+*/
+
+// readRecordingRecord reads a record written by `writeRecordingRecord`; io.EOF at a clean end, io.ErrUnexpectedEOF if it's cut short.
+func readRecordingRecord( reader io.Reader ) ( record []byte, read_error error ){
+	//Variables
+	var length_bytes []byte = make([]byte, 4);
+	//Parametres
+	//Function
+	_, read_error = io.ReadFull( reader, length_bytes );
+	if( read_error == nil ){
+		record = make([]byte, binary.BigEndian.Uint32( length_bytes ));
+		_, read_error = io.ReadFull( reader, record );
+		if( read_error == io.EOF ){
+			read_error = io.ErrUnexpectedEOF;
+		}
+	}
+	//Return
+	return record, read_error;
+}
@@ -0,0 +1,191 @@
+/**
+* @file recorder_test.go
+* @brief Contains test functions for `recorder.go`.
+* @author Anadian
+* @copyright 	Copyright 2019 Canosw
+	Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+software and associated documentation files (the "Software"), to deal in the Software 
+without restriction, including without limitation the rights to use, copy, modify, 
+merge, publish, distribute, sublicense, and/or sell copies of the Software, and to 
+permit persons to whom the Software is furnished to do so, subject to the following 
+conditions:
+	The above copyright notice and this permission notice shall be included in all copies 
+or substantial portions of the Software.
+	THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A 
+PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT 
+HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF 
+CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE 
+OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+// recorder_test contains test functions for `recorder.go`.
+package event_dispatcher;
+
+//# Dependencies
+import(
+	//## Internal
+	//## Standard
+	"testing"
+	"log"
+	"bytes"
+	"context"
+	"time"
+	//## External
+	error_report "github.com/Anadian/error_report/source"
+	matchkey "github.com/Anadian/matchkey/source"
+);
+
+//# Exported Functions
+/**
+* @fn TestRecorder
+* @brief Tests recording processed events, filtering, saving and loading, and replaying stepped, paced, and accelerated.
+* @param t *testing.T [in] Go stdlib testing object.
+*/
+
+// TestRecorder tests recording processed events, filtering, saving and loading, and replaying stepped, paced, and accelerated.
+func TestRecorder( t *testing.T ){
+	//Variables
+	var event_dispatcher *EventDispatcher_struct;
+	var replay_dispatcher *EventDispatcher_struct;
+	var recorder *Recorder_struct;
+	var loaded_recorder *Recorder_struct;
+	var replay *Replay_struct;
+	var function_return error_report.ErrorReport_struct;
+	var key matchkey.MatchKey_struct;
+	var events []Event_struct;
+	var names []string;
+	var buffer bytes.Buffer;
+	var start time.Time;
+	var elapsed time.Duration;
+	var ctx context.Context;
+	var cancel context.CancelFunc;
+	var ok bool;
+	var directory string;
+	//Parametres
+	//Function
+	event_dispatcher = newEventDispatcher( true, false );
+	recorder = NewRecorder( 0 ).Data["recorder"].(*Recorder_struct);
+	event_dispatcher.SetRecorder( recorder );
+	event_dispatcher.Publish( NewEvent( "order:placed", map[string]interface{}{ "total": 5 } ).Data["event"].(Event_struct) );
+	time.Sleep( 30 * time.Millisecond );
+	event_dispatcher.Publish( NewEvent( "user:login", map[string]interface{}{} ).Data["event"].(Event_struct) );
+	time.Sleep( 30 * time.Millisecond );
+	event_dispatcher.Publish( NewEvent( "order:shipped", map[string]interface{}{} ).Data["event"].(Event_struct) );
+	key, _ = matchkey.New( matchkey.MATCHKEY_TYPE_REGEX, "^order:" );
+	events = recorder.Events( RecordingFilter_struct{ Name_matchkey: key } );
+	if( len(events) == 2 && events[0].Name() == "order:placed" && events[1].Name() == "order:shipped" ){
+		log.Printf("Success: Filtered the recording by name.\n");
+	} else{
+		t.Fail();
+		log.Printf("Failure: Filtering by name returned: %v\n", events);
+	}
+	events = recorder.Events( RecordingFilter_struct{ From_time: recorder.selectRecordedEvents( RecordingFilter_struct{} )[1].eventTime() } );
+	if( len(events) == 2 && events[0].Name() == "user:login" ){
+		log.Printf("Success: Filtered the recording by time.\n");
+	} else{
+		t.Fail();
+		log.Printf("Failure: Filtering by time returned: %v\n", events);
+	}
+	///Save and load, as if moving the recording to another machine.
+	function_return = recorder.Save( &buffer, CBORCodec );
+	if( function_return.IsError() == true ){
+		t.Fatalf("Failure: Save returned: %v\n", function_return);
+	}
+	function_return = LoadRecorder( &buffer );
+	if( function_return.IsError() == true ){
+		t.Fatalf("Failure: LoadRecorder returned: %v\n", function_return);
+	}
+	loaded_recorder = function_return.Data["recorder"].(*Recorder_struct);
+	events = loaded_recorder.Events( RecordingFilter_struct{} );
+	if( len(events) == 3 && events[2].Name() == "order:shipped" && loaded_recorder.selectRecordedEvents( RecordingFilter_struct{} )[2].recorded_time.Equal( recorder.selectRecordedEvents( RecordingFilter_struct{} )[2].recorded_time ) == true ){
+		log.Printf("Success: The recording, with its recorded times, survived saving and loading.\n");
+	} else{
+		t.Fail();
+		log.Printf("Failure: Loaded: %v\n", events);
+	}
+	_, ok = events[0].Get( "recorded_time" );
+	if( ok == false ){
+		log.Printf("Success: Recording left the events' data as processed.\n");
+	} else{
+		t.Fail();
+		log.Printf("Failure: Recording added a datum: %v\n", events[0].Data());
+	}
+	///Replay into a fresh dispatcher.
+	replay_dispatcher = newEventDispatcher( false, false );
+	key, _ = matchkey.New( matchkey.MATCHKEY_TYPE_REGEX, "." );
+	replay_dispatcher.AddEventListener( NewEventListener( key, false, func( event Event_struct, args ...interface{} ){
+		names = append(names, event.Name());
+	} ).Data["event_listener"].(EventListener_struct) );
+	replay = loaded_recorder.NewReplay( replay_dispatcher, RecordingFilter_struct{} ).Data["replay"].(*Replay_struct);
+	function_return = replay.Step();
+	if( function_return.NoError() == true && function_return.Data["remaining"] == 2 && len(names) == 1 && names[0] == "order:placed" ){
+		log.Printf("Success: Stepped through the first event.\n");
+	} else{
+		t.Fail();
+		log.Printf("Failure: Step returned %v; names: %v\n", function_return, names);
+	}
+	start = time.Now();
+	function_return = replay.Run( context.Background(), 1 );
+	elapsed = time.Since( start );
+	if( function_return.Data["replayed"] == 2 && len(names) == 3 && elapsed >= 25 * time.Millisecond && replay.Step().CodeEqual( ERROR_CODE_INDEX_OUT_OF_RANGE ) == true ){
+		log.Printf("Success: Replayed the rest at the original pace in %v.\n", elapsed);
+	} else{
+		t.Fail();
+		log.Printf("Failure: Run returned %v after %v; names: %v\n", function_return, elapsed, names);
+	}
+	replay = loaded_recorder.NewReplay( replay_dispatcher, RecordingFilter_struct{} ).Data["replay"].(*Replay_struct);
+	start = time.Now();
+	replay.Run( context.Background(), 100 );
+	elapsed = time.Since( start );
+	if( len(names) == 6 && elapsed < 30 * time.Millisecond ){
+		log.Printf("Success: Replayed accelerated in %v.\n", elapsed);
+	} else{
+		t.Fail();
+		log.Printf("Failure: The accelerated replay took %v; names: %v\n", elapsed, names);
+	}
+	replay = loaded_recorder.NewReplay( replay_dispatcher, RecordingFilter_struct{} ).Data["replay"].(*Replay_struct);
+	ctx, cancel = context.WithTimeout( context.Background(), 10 * time.Millisecond );
+	function_return = replay.Run( ctx, 1 );
+	cancel();
+	if( function_return.CodeEqual( ERROR_CODE_REPLAY_ERROR ) == true && function_return.Data["replayed"] == 1 && replay.Remaining() == 2 ){
+		log.Printf("Success: A paced replay stopped with its context.\n");
+	} else{
+		t.Fail();
+		log.Printf("Failure: Run returned: %v\n", function_return);
+	}
+	///A limited recorder keeps the most recent events.
+	recorder = NewRecorder( 2 ).Data["recorder"].(*Recorder_struct);
+	event_dispatcher.SetRecorder( recorder );
+	event_dispatcher.Publish( NewEvent( "limit:1", map[string]interface{}{} ).Data["event"].(Event_struct) );
+	event_dispatcher.Publish( NewEvent( "limit:2", map[string]interface{}{} ).Data["event"].(Event_struct) );
+	event_dispatcher.Publish( NewEvent( "limit:3", map[string]interface{}{} ).Data["event"].(Event_struct) );
+	events = recorder.Events( RecordingFilter_struct{} );
+	if( len(events) == 2 && events[0].Name() == "limit:2" && events[1].Name() == "limit:3" ){
+		log.Printf("Success: The recorder kept the most recent events.\n");
+	} else{
+		t.Fail();
+		log.Printf("Failure: The limited recorder kept: %v\n", events);
+	}
+	///Replaying into a dispatcher with its own write-ahead log doesn't acknowledge its unrelated events.
+	event_dispatcher = NewEventDispatcherWithWriteAheadLog( false, t.TempDir(), WriteAheadLogOptions_struct{ Fsync_policy: FSYNC_POLICY_NEVER } ).Data["event_dispatcher"].(*EventDispatcher_struct);
+	recorder = NewRecorder( 0 ).Data["recorder"].(*Recorder_struct);
+	event_dispatcher.SetRecorder( recorder );
+	event_dispatcher.PushEvent( NewEvent( "wal:recorded", map[string]interface{}{} ).Data["event"].(Event_struct) );
+	event_dispatcher.ProcessEvents();
+	event_dispatcher.Close();
+	directory = t.TempDir();
+	replay_dispatcher = NewEventDispatcherWithWriteAheadLog( false, directory, WriteAheadLogOptions_struct{ Fsync_policy: FSYNC_POLICY_NEVER } ).Data["event_dispatcher"].(*EventDispatcher_struct);
+	replay_dispatcher.PushEvent( NewEvent( "wal:unrelated", map[string]interface{}{} ).Data["event"].(Event_struct) );
+	recorder.NewReplay( replay_dispatcher, RecordingFilter_struct{} ).Data["replay"].(*Replay_struct).Step();
+	replay_dispatcher.Close();
+	function_return = NewEventDispatcherWithWriteAheadLog( false, directory, WriteAheadLogOptions_struct{ Fsync_policy: FSYNC_POLICY_NEVER } );
+	if( function_return.Data["recovered"] == 1 ){
+		log.Printf("Success: The replayed event didn't acknowledge the target's pending event.\n");
+	} else{
+		t.Fail();
+		log.Printf("Failure: The target recovered %v events.\n", function_return.Data["recovered"]);
+	}
+	function_return.Data["event_dispatcher"].(*EventDispatcher_struct).Close();
+	//Return
+}
@@ -0,0 +1,326 @@
+/**
+* @file retry.go
+* @brief Retry policies, with exponential backoff and jitter, for event listeners which return errors or panic.
+* @author Anadian
+* @copyright 	Copyright 2019 Canosw
+	Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+software and associated documentation files (the "Software"), to deal in the Software 
+without restriction, including without limitation the rights to use, copy, modify, 
+merge, publish, distribute, sublicense, and/or sell copies of the Software, and to 
+permit persons to whom the Software is furnished to do so, subject to the following 
+conditions:
+	The above copyright notice and this permission notice shall be included in all copies 
+or substantial portions of the Software.
+	THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A 
+PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT 
+HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF 
+CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE 
+OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+package event_dispatcher;
+
+//# Dependencies
+import(
+	//## Internal
+	//## Standard
+	"time"
+	"math"
+	"math/rand"
+	"fmt"
+	//## External
+	error_report "github.com/Anadian/error_report/source"
+);
+
+//# Constants
+const(
+	//## Exported Constants
+	//### Errors
+	ERROR_CODE_INVALID_RETRY_POLICY int64 = 26;
+	ERROR_CODE_RETRIES_EXHAUSTED int64 = 27;
+	//## Private Constants
+);
+
+//# Types
+//## Structs
+// RetryPolicy_struct describes how often, and how long after, a failed listener call is retried.
+type RetryPolicy_struct struct{
+	max_attempts uint
+	initial_backoff time.Duration
+	max_backoff time.Duration
+	multiplier float64
+	jitter float64
+	retryable_function func( listener_error error ) bool
+	terminal_function func( event Event_struct, report error_report.ErrorReport_struct )
+}
+//### Methods
+/**
+* @fn SetRetryableFunction
+* @brief Sets the predicate deciding whether a listener error is worth retrying; by default every error is.
+* @struct retry_policy *RetryPolicy_struct
+* @param retryable_function func( listener_error error ) bool [in] Returns true for errors which should be retried. Panics are passed as an error describing the panic.
+*/
+
+// SetRetryableFunction sets the predicate deciding whether a listener error is worth retrying; by default every error is.
+func (retry_policy *RetryPolicy_struct) SetRetryableFunction( retryable_function func( listener_error error ) bool ){
+	//Variables
+	//Parametres
+	//Function
+	retry_policy.retryable_function = retryable_function;
+	//Return
+}
+
+/**
+* @fn SetTerminalFunction
+* @brief Sets the function called with the event, and the last failure's report, once the policy gives up; without one the event goes to the dead-letter queue.
+* @struct retry_policy *RetryPolicy_struct
+* @param terminal_function func( event Event_struct, report error_report.ErrorReport_struct ) [in] The terminal handler.
+*/
+
+// SetTerminalFunction sets the function called with the event, and the last failure's report, once the policy gives up; without one the event goes to the dead-letter queue.
+func (retry_policy *RetryPolicy_struct) SetTerminalFunction( terminal_function func( event Event_struct, report error_report.ErrorReport_struct ) ){
+	//Variables
+	//Parametres
+	//Function
+	retry_policy.terminal_function = terminal_function;
+	//Return
+}
+
+/**
+* @fn Backoff
+* @brief Returns how long to wait before the retry following the given number of failed attempts.
+* @struct retry_policy RetryPolicy_struct
+* @param failed_attempts uint [in] How many attempts have failed so far; at least 1.
+* @return time.Duration
+*/
+
+// Backoff returns how long to wait before the retry following the given number of failed attempts. Uncapped waits too long for a `time.Duration` are clamped to the longest one.
+func (retry_policy RetryPolicy_struct) Backoff( failed_attempts uint ) time.Duration{
+	//Variables
+	var backoff float64;
+	var duration time.Duration;
+	//Parametres
+	if( failed_attempts < 1 ){
+		failed_attempts = 1;
+	}
+	//Function
+	backoff = float64(retry_policy.initial_backoff) * math.Pow( retry_policy.multiplier, float64(failed_attempts - 1) );
+	if( retry_policy.max_backoff > 0 && backoff > float64(retry_policy.max_backoff) ){
+		backoff = float64(retry_policy.max_backoff);
+	} else if( backoff > float64(math.MaxInt64) ){
+		backoff = float64(math.MaxInt64);
+	}
+	if( retry_policy.jitter > 0 ){
+		backoff -= backoff * retry_policy.jitter * rand.Float64();
+	}
+	///float64(math.MaxInt64) rounds up to 2^63, which doesn't fit.
+	if( backoff >= float64(math.MaxInt64) ){
+		duration = time.Duration(math.MaxInt64);
+	} else{
+		duration = time.Duration(backoff);
+	}
+	//Return
+	return duration;
+}
+
+/**
+* @fn SetRetryPolicy
+* @brief Attaches a retry policy to the event listener; must be called before the listener is added to a dispatcher.
+* @struct event_listener *EventListener_struct
+* @param retry_policy RetryPolicy_struct [in] The policy, as created by `NewRetryPolicy`.
+* @return ( return_report error_report.ErrorReport_struct ) 
+* @retval 0 Success
+* @retval 1 Not Supported
+* @retval >1 Error
+*/
+
+// SetRetryPolicy attaches a retry policy to the event listener; must be called before the listener is added to a dispatcher.
+func (event_listener *EventListener_struct) SetRetryPolicy( retry_policy RetryPolicy_struct ) ( return_report error_report.ErrorReport_struct ){
+	//Variables
+	//Parametres
+	//Function
+	if( retry_policy.max_attempts > 0 ){
+		event_listener.retry_policy = &retry_policy;
+		return_report = error_report.New( 0, map[string]interface{}{}, nil );
+	} else{
+		return_report = error_report.New( ERROR_CODE_INVALID_RETRY_POLICY, map[string]interface{}{ "message": "The retry policy wasn't created by NewRetryPolicy." }, nil );
+	}
+	//Return
+	return return_report;
+}
+
+/**
+* @fn RetryAttempt
+* @brief Returns how many times delivering this event to the current listener has already failed; 0 on the first delivery.
+* @struct event Event_struct
+* @return uint
+*/
+
+// RetryAttempt returns how many times delivering this event to the current listener has already failed; 0 on the first delivery.
+func (event Event_struct) RetryAttempt() uint{
+	//Variables
+	//Parametres
+	//Function
+	//Return
+	return event.retry_attempt;
+}
+
+/**
+* @fn retryOrGiveUp
+* @brief Applies the listener's retry policy, if it has one, to a failed call: either schedules a retry or hands the event to the terminal handler or dead-letter queue.
+* @struct event_dispatcher *EventDispatcher_struct
+* @param event_listener EventListener_struct [in] The listener which failed.
+* @param event Event_struct [in] The event it failed on.
+* @param failure_report error_report.ErrorReport_struct [in] The report returned by `callEventListener`.
+* @return bool True if the listener has a retry policy, and so the failure has been taken care of.
+*/
+
+// retryOrGiveUp applies the listener's retry policy, if it has one, to a failed call: either schedules a retry or hands the event to the terminal handler or dead-letter queue.
+func (event_dispatcher *EventDispatcher_struct) retryOrGiveUp( event_listener EventListener_struct, event Event_struct, failure_report error_report.ErrorReport_struct ) bool{
+	//Variables
+	var retry_policy *RetryPolicy_struct = event_listener.retry_policy;
+	var failed_attempts uint;
+	var listener_error error;
+	var retry_event Event_struct;
+	var retry_timer *time.Timer;
+	var exhausted_report error_report.ErrorReport_struct;
+	//Parametres
+	if( retry_policy == nil ){
+		return false;
+	}
+	failed_attempts = event.retry_attempt + 1;
+	listener_error, _ = failure_report.Data["error"].(error);
+	if( listener_error == nil ){
+		listener_error = fmt.Errorf( "%v", failure_report.Data["message"] );
+	}
+	//Function
+	failure_report.Data["attempt"] = failed_attempts;
+	if( failed_attempts < retry_policy.max_attempts && ( retry_policy.retryable_function == nil || retry_policy.retryable_function( listener_error ) == true ) ){
+		failure_report.Data["retry_scheduled"] = true;
+		retry_event = event;
+		retry_event.propagation = nil;
+		retry_event.retry_event_listener_id = event_listener.id;
+		retry_event.retry_attempt = failed_attempts;
+		retry_event.acknowledgement_id = 0;
+		///The timer is tracked so `Stop` and `Close` can cancel it; it untracks itself when it fires, unless it was cancelled first.
+		event_dispatcher.retry_mutex.Lock();
+		retry_timer = time.AfterFunc( retry_policy.Backoff( failed_attempts ), func(){
+			var tracked bool;
+			event_dispatcher.retry_mutex.Lock();
+			_, tracked = event_dispatcher.retry_timers_map[retry_timer];
+			delete(event_dispatcher.retry_timers_map, retry_timer);
+			event_dispatcher.retry_mutex.Unlock();
+			if( tracked == true ){
+				event_dispatcher.requeueRetry( retry_event );
+			}
+		} );
+		event_dispatcher.retry_timers_map[retry_timer] = struct{}{};
+		event_dispatcher.retry_mutex.Unlock();
+	} else{
+		failure_report.Data["retry_scheduled"] = false;
+		exhausted_report = error_report.New( ERROR_CODE_RETRIES_EXHAUSTED, map[string]interface{}{ "message": "The event listener's retry policy gave up.", "event_listener_id": event_listener.id, "attempts": failed_attempts }, &failure_report );
+		if( retry_policy.terminal_function != nil ){
+			retry_policy.terminal_function( event, exhausted_report );
+		} else if( failure_report.CodeEqual( ERROR_CODE_EVENT_LISTENER_PANIC ) == true ){
+			event_dispatcher.addDeadLetter( event, DEAD_LETTER_REASON_PANIC, exhausted_report );
+		} else{
+			event_dispatcher.addDeadLetter( event, DEAD_LETTER_REASON_LISTENER_ERROR, exhausted_report );
+		}
+	}
+	//Return
+	return true;
+}
+
+/**
+* @fn requeueRetry
+* @brief Puts a retry back through the dispatcher: onto the event queue when buffered, straight to `ProcessEvent` otherwise.
+* @struct event_dispatcher *EventDispatcher_struct
+* @param retry_event Event_struct [in] The event copy targeting the failed listener.
+*/
+
+// requeueRetry puts a retry back through the dispatcher: onto the event queue when buffered, straight to `ProcessEvent` otherwise.
+func (event_dispatcher *EventDispatcher_struct) requeueRetry( retry_event Event_struct ){
+	//Variables
+	var buffered bool;
+	var function_return error_report.ErrorReport_struct;
+	//Parametres
+	event_dispatcher.mutex.Lock();
+	buffered = event_dispatcher.buffered;
+	event_dispatcher.mutex.Unlock();
+	//Function
+	///The publisher's context may well have been cancelled by now.
+	retry_event.ctx = detachContext( retry_event.ctx );
+	if( buffered == true ){
+		function_return = event_dispatcher.PushEvent( retry_event );
+	} else{
+		function_return = event_dispatcher.ProcessEvent( retry_event );
+	}
+	if( function_return.IsError() == true ){
+		event_dispatcher.sinkError( function_return );
+	}
+	//Return
+}
+
+/**
+* @fn cancelRetries
+* @brief Stops every retry still waiting out its backoff; the events are dropped.
+* @struct event_dispatcher *EventDispatcher_struct
+*/
+
+// cancelRetries stops every retry still waiting out its backoff; the events are dropped.
+func (event_dispatcher *EventDispatcher_struct) cancelRetries(){
+	//Variables
+	var retry_timer *time.Timer;
+	//Parametres
+	//Function
+	event_dispatcher.retry_mutex.Lock();
+	for retry_timer = range event_dispatcher.retry_timers_map {
+		retry_timer.Stop();
+	}
+	event_dispatcher.retry_timers_map = map[*time.Timer]struct{}{};
+	event_dispatcher.retry_mutex.Unlock();
+	//Return
+}
+
+//# Global Variables
+var(
+	//## Exported Variables
+	//## Private Variables
+);
+
+//# Exported Functions
+/**
+* @fn NewRetryPolicy
+* @brief Creates a retry policy with exponential backoff: the n-th retry waits `initial_backoff * multiplier^(n-1)`, capped at `max_backoff` and reduced by up to `jitter` of itself at random.
+* @param max_attempts uint [in] The total number of attempts, including the first; must be at least 1.
+* @param initial_backoff time.Duration [in] The wait before the first retry.
+* @param max_backoff time.Duration [in] The longest wait between attempts; 0 means uncapped.
+* @param multiplier float64 [in] The factor the wait grows by after each failed attempt; must be at least 1.
+* @param jitter float64 [in] The largest fraction, from 0 to 1, randomly taken off each wait.
+* @return ( return_report error_report.ErrorReport_struct ) 
+* @retval 0 Success
+* @retval 1 Not Supported
+* @retval >1 Error
+*/
+
+// NewRetryPolicy creates a retry policy with exponential backoff: the n-th retry waits `initial_backoff * multiplier^(n-1)`, capped at `max_backoff` and reduced by up to `jitter` of itself at random.
+func NewRetryPolicy( max_attempts uint, initial_backoff time.Duration, max_backoff time.Duration, multiplier float64, jitter float64 ) ( return_report error_report.ErrorReport_struct ){
+	//Variables
+	var retry_policy RetryPolicy_struct;
+	//Parametres
+	//Function
+	if( max_attempts >= 1 && initial_backoff >= 0 && max_backoff >= 0 && multiplier >= 1 && jitter >= 0 && jitter <= 1 ){
+		retry_policy.max_attempts = max_attempts;
+		retry_policy.initial_backoff = initial_backoff;
+		retry_policy.max_backoff = max_backoff;
+		retry_policy.multiplier = multiplier;
+		retry_policy.jitter = jitter;
+		return_report = error_report.New( 0, map[string]interface{}{ "retry_policy": retry_policy }, nil );
+	} else{
+		return_report = error_report.New( ERROR_CODE_INVALID_RETRY_POLICY, map[string]interface{}{ "message": "max_attempts and multiplier must be at least 1, durations can't be negative, and jitter must be between 0 and 1." }, nil );
+	}
+	//Return
+	return return_report;
+}
+
+//# Private Functions
@@ -0,0 +1,181 @@
+/**
+* @file retry_test.go
+* @brief Contains test functions for `retry.go`.
+* @author Anadian
+* @copyright 	Copyright 2019 Canosw
+	Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+software and associated documentation files (the "Software"), to deal in the Software 
+without restriction, including without limitation the rights to use, copy, modify, 
+merge, publish, distribute, sublicense, and/or sell copies of the Software, and to 
+permit persons to whom the Software is furnished to do so, subject to the following 
+conditions:
+	The above copyright notice and this permission notice shall be included in all copies 
+or substantial portions of the Software.
+	THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A 
+PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT 
+HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF 
+CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE 
+OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+// retry_test contains test functions for `retry.go`.
+package event_dispatcher;
+
+//# Dependencies
+import(
+	//## Internal
+	//## Standard
+	"testing"
+	"log"
+	"errors"
+	"context"
+	"time"
+	"math"
+	//## External
+	error_report "github.com/Anadian/error_report/source"
+	matchkey "github.com/Anadian/matchkey/source"
+);
+
+//# Exported Functions
+/**
+* @fn TestRetryPolicy
+* @brief Tests that failing listeners are retried through the queue and that exhausted events reach the terminal handler.
+* @param t *testing.T [in] Go stdlib testing object.
+*/
+
+// TestRetryPolicy tests that failing listeners are retried through the queue and that exhausted events reach the terminal handler.
+func TestRetryPolicy( t *testing.T ){
+	//Variables
+	var event_dispatcher *EventDispatcher_struct;
+	var key matchkey.MatchKey_struct;
+	var retry_policy RetryPolicy_struct;
+	var event_listener EventListener_struct;
+	var attempts_channel chan uint = make(chan uint, 8);
+	var terminal_channel chan error_report.ErrorReport_struct = make(chan error_report.ErrorReport_struct, 1);
+	var function_return error_report.ErrorReport_struct;
+	var attempts []uint;
+	var timeout <-chan time.Time = time.After( 5 * time.Second );
+	//Parametres
+	//Function
+	function_return = NewRetryPolicy( 0, time.Millisecond, 0, 2, 0 );
+	if( function_return.CodeEqual( ERROR_CODE_INVALID_RETRY_POLICY ) == true ){
+		log.Printf("Success: NewRetryPolicy rejected max_attempts 0.\n");
+	} else{
+		t.Fail();
+		log.Printf("Failure: Didn't get ERROR_CODE_INVALID_RETRY_POLICY: %v\n", function_return);
+	}
+	retry_policy = NewRetryPolicy( 3, time.Millisecond, 4 * time.Millisecond, 2, 0.5 ).Data["retry_policy"].(RetryPolicy_struct);
+	if( retry_policy.Backoff( 10 ) <= 4 * time.Millisecond && retry_policy.Backoff( 10 ) >= 2 * time.Millisecond ){
+		log.Printf("Success: Backoff is capped and jittered.\n");
+	} else{
+		t.Fail();
+		log.Printf("Failure: Unexpected backoff: %v\n", retry_policy.Backoff( 10 ));
+	}
+	retry_policy = NewRetryPolicy( 3, time.Second, 0, 2, 0.5 ).Data["retry_policy"].(RetryPolicy_struct);
+	if( retry_policy.Backoff( 200 ) >= time.Duration(math.MaxInt64 / 2) && NewRetryPolicy( 3, time.Second, 0, 2, 0 ).Data["retry_policy"].(RetryPolicy_struct).Backoff( 2000 ) == time.Duration(math.MaxInt64) ){
+		log.Printf("Success: Uncapped backoff is clamped instead of overflowing.\n");
+	} else{
+		t.Fail();
+		log.Printf("Failure: Uncapped backoff overflowed: %v\n", retry_policy.Backoff( 200 ));
+	}
+	retry_policy.SetTerminalFunction( func( event Event_struct, report error_report.ErrorReport_struct ){
+		terminal_channel <- report;
+	} );
+	key, _ = matchkey.New( matchkey.MATCHKEY_TYPE_STRING, "retry:test" );
+	event_dispatcher = newEventDispatcher( false, true );
+	event_listener = NewFallibleEventListener( key, false, 0, func( event Event_struct, args ...interface{} ) error{
+		attempts_channel <- event.RetryAttempt();
+		return errors.New( "webhook unavailable" );
+	} ).Data["event_listener"].(EventListener_struct);
+	event_listener.SetRetryPolicy( retry_policy );
+	event_dispatcher.AddEventListener( event_listener );
+	///A second listener which succeeds must not be called again by the retries.
+	event_dispatcher.AddEventListener( NewEventListener( key, false, func( event Event_struct, args ...interface{} ){
+		if( event.RetryAttempt() != 0 ){
+			t.Fail();
+			log.Printf("Failure: Successful listener received a retry.\n");
+		}
+	} ).Data["event_listener"].(EventListener_struct) );
+	event_dispatcher.Start( context.Background() );
+	event_dispatcher.PushEvent( NewEvent( "retry:test", map[string]interface{}{} ).Data["event"].(Event_struct) );
+	select{
+		case function_return = <-terminal_channel:
+			if( function_return.CodeEqual( ERROR_CODE_RETRIES_EXHAUSTED ) == true && function_return.Data["attempts"] == uint(3) ){
+				log.Printf("Success: Terminal handler received: %v\n", function_return.Data);
+			} else{
+				t.Fail();
+				log.Printf("Failure: Terminal handler received an unexpected report: %v\n", function_return);
+			}
+		case <-timeout:
+			t.Fail();
+			log.Printf("Failure: Terminal handler wasn't called.\n");
+	}
+	event_dispatcher.Stop();
+	close(attempts_channel);
+	for attempt := range attempts_channel {
+		attempts = append(attempts, attempt);
+	}
+	if( len(attempts) == 3 && attempts[0] == 0 && attempts[1] == 1 && attempts[2] == 2 ){
+		log.Printf("Success: Listener attempted %v.\n", attempts);
+	} else{
+		t.Fail();
+		log.Printf("Failure: Unexpected attempts: %v\n", attempts);
+	}
+	//Return
+}
+
+/**
+* @fn TestRetryLifecycle
+* @brief Tests that `Close` cancels retries waiting out their backoff and that dead-lettered retries lose their retry state.
+* @param t *testing.T [in] Go stdlib testing object.
+*/
+
+// TestRetryLifecycle tests that `Close` cancels retries waiting out their backoff and that dead-lettered retries lose their retry state.
+func TestRetryLifecycle( t *testing.T ){
+	//Variables
+	var event_dispatcher *EventDispatcher_struct;
+	var key matchkey.MatchKey_struct;
+	var event_listener EventListener_struct;
+	var calls_channel chan uint = make(chan uint, 8);
+	var dead_letters_slice []DeadLetter_struct;
+	var deadline time.Time;
+	//Parametres
+	//Function
+	key, _ = matchkey.New( matchkey.MATCHKEY_TYPE_STRING, "retry:lifecycle" );
+	event_listener = NewFallibleEventListener( key, false, 0, func( event Event_struct, args ...interface{} ) error{
+		calls_channel <- event.RetryAttempt();
+		return errors.New( "unavailable" );
+	} ).Data["event_listener"].(EventListener_struct);
+	event_listener.SetRetryPolicy( NewRetryPolicy( 2, 50 * time.Millisecond, 0, 1, 0 ).Data["retry_policy"].(RetryPolicy_struct) );
+	///A retry cancelled by Close never reaches the listener.
+	event_dispatcher = newEventDispatcher( false, false );
+	event_dispatcher.AddEventListener( event_listener );
+	event_dispatcher.ProcessEvent( NewEvent( "retry:lifecycle", map[string]interface{}{} ).Data["event"].(Event_struct) );
+	<-calls_channel;
+	event_dispatcher.Close();
+	select{
+		case <-calls_channel:
+			t.Fail();
+			log.Printf("Failure: The retry ran after Close.\n");
+		case <-time.After( 150 * time.Millisecond ):
+			log.Printf("Success: Close cancelled the pending retry.\n");
+	}
+	///Once the policy gives up, the dead letter is a plain event again.
+	event_dispatcher = newEventDispatcher( false, false );
+	event_dispatcher.EnableDeadLetterQueue( 0 );
+	event_dispatcher.AddEventListener( event_listener );
+	event_dispatcher.ProcessEvent( NewEvent( "retry:lifecycle", map[string]interface{}{} ).Data["event"].(Event_struct) );
+	deadline = time.Now().Add( 5 * time.Second );
+	for len(dead_letters_slice) == 0 && time.Now().Before( deadline ) {
+		time.Sleep( 10 * time.Millisecond );
+		dead_letters_slice = event_dispatcher.GetDeadLetters().Data["dead_letters"].([]DeadLetter_struct);
+	}
+	if( len(dead_letters_slice) == 1 && dead_letters_slice[0].Event().RetryAttempt() == 0 && dead_letters_slice[0].Event().retry_event_listener_id == 0 ){
+		log.Printf("Success: The dead-lettered retry lost its retry state.\n");
+	} else{
+		t.Fail();
+		log.Printf("Failure: Unexpected dead letters: %v\n", dead_letters_slice);
+	}
+	//Return
+}
@@ -0,0 +1,319 @@
+/**
+* @file snapshot.go
+* @brief Snapshots a dispatcher's pending queue, flags, and named listeners, and restores them, e.g. in another process.
+* @author Anadian
+* @copyright 	Copyright 2019 Canosw
+	Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+software and associated documentation files (the "Software"), to deal in the Software 
+without restriction, including without limitation the rights to use, copy, modify, 
+merge, publish, distribute, sublicense, and/or sell copies of the Software, and to 
+permit persons to whom the Software is furnished to do so, subject to the following 
+conditions:
+	The above copyright notice and this permission notice shall be included in all copies 
+or substantial portions of the Software.
+	THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A 
+PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT 
+HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF 
+CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE 
+OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+package event_dispatcher;
+
+//# Dependencies
+import(
+	//## Internal
+	//## Standard
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+	//## External
+	error_report "github.com/Anadian/error_report/source"
+	matchkey "github.com/Anadian/matchkey/source"
+);
+
+//# Constants
+const(
+	//## Exported Constants
+	//### Errors
+	ERROR_CODE_SNAPSHOT_ERROR int64 = 43;
+	ERROR_CODE_UNREGISTERED_LISTENER_FUNCTION int64 = 44;
+	//### Snapshot Format
+	SNAPSHOT_FORMAT string = "event_dispatcher.snapshot" //The "format" field identifying a snapshot.
+	SNAPSHOT_VERSION int = 1 //The newest snapshot version written and understood; `Restore` reads older versions too.
+	//## Private Constants
+);
+
+//# Types
+//## Structs
+// snapshot_struct is the JSON document `Snapshot` writes. Events are encoded with the JSON codec.
+type snapshot_struct struct{
+	Format string `json:"format"`
+	Version int `json:"version"`
+	Add_times bool `json:"add_times"`
+	Buffered bool `json:"buffered"`
+	Event_listeners []snapshot_event_listener_struct `json:"event_listeners"`
+	Events []json.RawMessage `json:"events"`
+}
+// snapshot_event_listener_struct records a named listener.
+type snapshot_event_listener_struct struct{
+	Function string `json:"function"`
+	Matchkey_type uint8 `json:"matchkey_type"`
+	Matchkey_string string `json:"matchkey_string"`
+	Async bool `json:"async"`
+	Priority int64 `json:"priority"`
+}
+//### Methods
+/**
+* @fn Snapshot
+* @brief Writes the dispatcher's queued events, its `add_times` and `buffered` flags, and its named listeners to `writer`.
+* @struct event_dispatcher *EventDispatcher_struct
+* @param writer io.Writer [in] Where to write the snapshot.
+* @return ( return_report error_report.ErrorReport_struct ) 
+* @retval 0 Success
+* @retval 1 Not Supported
+* @retval >1 Error
+*/
+
+// Snapshot writes the dispatcher's queued events, its `add_times` and `buffered` flags, and its listeners from `NewNamedEventListener` to `writer` as a versioned JSON document for `Restore`, so a process restarting gracefully can hand its pending events to its successor. The queue is captured as it was at one instant; events queued afterwards aren't included, so stop the run loop, without draining, first. Listeners with unnamed functions can't be recorded and are counted as "skipped_event_listeners", nor are retry policies; the "events" and "event_listeners" data count what was written. Event data types must be registered as `RegisterPayloadType` describes. A dispatcher with a queue backend keeps its queue there, so only its flags and listeners are written.
+func (event_dispatcher *EventDispatcher_struct) Snapshot( writer io.Writer ) ( return_report error_report.ErrorReport_struct ){
+	//Variables
+	var snapshot snapshot_struct = snapshot_struct{ Format: SNAPSHOT_FORMAT, Version: SNAPSHOT_VERSION, Event_listeners: []snapshot_event_listener_struct{}, Events: []json.RawMessage{} };
+	var events []Event_struct;
+	var event_listener EventListener_struct;
+	var skipped int;
+	var function_return error_report.ErrorReport_struct;
+	var encoded []byte;
+	var write_error error;
+	var i int;
+	//Parametres
+	//Function
+	event_dispatcher.mutex.Lock();
+	snapshot.Add_times = event_dispatcher.add_times;
+	snapshot.Buffered = event_dispatcher.buffered;
+	if( event_dispatcher.queue_backend == nil ){
+		events = make([]Event_struct, event_dispatcher.getEventsQueue_Unsafe().Length());
+		for i = 0; i < len(events); i++ {
+			events[i] = event_dispatcher.events_queue.Get( i );
+		}
+	}
+	for _, event_listener = range event_dispatcher.event_listeners_slice {
+		if( event_listener.function_name == "" ){
+			skipped++;
+		} else{
+			snapshot.Event_listeners = append(snapshot.Event_listeners, snapshot_event_listener_struct{ Function: event_listener.function_name, Matchkey_type: event_listener.key.Matchkey_type, Matchkey_string: event_listener.key.Matchkey_string, Async: event_listener.async, Priority: event_listener.priority });
+		}
+	}
+	event_dispatcher.mutex.Unlock();
+	for i = 0; i < len(events); i++ {
+		function_return = JSONCodec.Encode( events[i] );
+		if( function_return.IsError() == true ){
+			return error_report.New( ERROR_CODE_SNAPSHOT_ERROR, map[string]interface{}{ "message": "Couldn't encode a queued event.", "event": events[i] }, &function_return );
+		}
+		snapshot.Events = append(snapshot.Events, json.RawMessage( function_return.Data["encoded"].([]byte) ));
+	}
+	encoded, write_error = json.Marshal( snapshot );
+	if( write_error == nil ){
+		_, write_error = writer.Write( encoded );
+	}
+	if( write_error == nil ){
+		return_report = error_report.New( 0, map[string]interface{}{ "events": len(snapshot.Events), "event_listeners": len(snapshot.Event_listeners), "skipped_event_listeners": skipped }, nil );
+	} else{
+		return_report = error_report.New( ERROR_CODE_SNAPSHOT_ERROR, map[string]interface{}{ "message": write_error.Error(), "error": write_error }, nil );
+	}
+	//Return
+	return return_report;
+}
+
+/**
+* @fn Restore
+* @brief Reads a snapshot written by `Snapshot`, setting the dispatcher's flags, adding its listeners, and queueing its events.
+* @struct event_dispatcher *EventDispatcher_struct
+* @param reader io.Reader [in] The snapshot.
+* @return ( return_report error_report.ErrorReport_struct ) 
+* @retval 0 Success
+* @retval 1 Not Supported
+* @retval >1 Error
+*/
+
+// Restore reads a snapshot written by `Snapshot`, typically into a new dispatcher: it sets the `add_times` and `buffered` flags, adds the listeners, resolving their functions by name from those registered with `RegisterEventListenerFunction`, and queues the events after any already queued, keeping their stamps, envelope, and data. Nothing changes unless the whole snapshot can be read, its version is supported, and every function is registered; ERROR_CODE_UNREGISTERED_LISTENER_FUNCTION names the first "function" that isn't. The "events" and "event_listeners" data count what was restored. Queueing an event can still fail, such as when the write-ahead log or queue backend does; the flags and listeners have been set by then, and aren't undone, and the events before it stay queued, as the "restored" datum counts.
+func (event_dispatcher *EventDispatcher_struct) Restore( reader io.Reader ) ( return_report error_report.ErrorReport_struct ){
+	//Variables
+	var snapshot snapshot_struct;
+	var event_listeners []EventListener_struct;
+	var events []Event_struct;
+	var function_return error_report.ErrorReport_struct;
+	var key matchkey.MatchKey_struct;
+	var decode_error error;
+	var i int;
+	//Parametres
+	//Function
+	decode_error = json.NewDecoder( reader ).Decode( &snapshot );
+	if( decode_error != nil ){
+		return error_report.New( ERROR_CODE_SNAPSHOT_ERROR, map[string]interface{}{ "message": decode_error.Error(), "error": decode_error }, nil );
+	}
+	if( snapshot.Format != SNAPSHOT_FORMAT || snapshot.Version < 1 || snapshot.Version > SNAPSHOT_VERSION ){
+		return error_report.New( ERROR_CODE_SNAPSHOT_ERROR, map[string]interface{}{ "message": "Not a snapshot, or one from a newer version.", "format": snapshot.Format, "version": snapshot.Version }, nil );
+	}
+	for i = 0; i < len(snapshot.Event_listeners); i++ {
+		key, function_return = matchkey.New( snapshot.Event_listeners[i].Matchkey_type, snapshot.Event_listeners[i].Matchkey_string );
+		if( function_return.IsError() == true ){
+			return error_report.New( ERROR_CODE_SNAPSHOT_ERROR, map[string]interface{}{ "message": "Couldn't recreate a listener's matchkey.", "function": snapshot.Event_listeners[i].Function }, &function_return );
+		}
+		function_return = NewNamedEventListener( key, snapshot.Event_listeners[i].Async, snapshot.Event_listeners[i].Priority, snapshot.Event_listeners[i].Function );
+		if( function_return.IsError() == true ){
+			return function_return;
+		}
+		event_listeners = append(event_listeners, function_return.Data["event_listener"].(EventListener_struct));
+	}
+	for i = 0; i < len(snapshot.Events); i++ {
+		function_return = JSONCodec.Decode( snapshot.Events[i] );
+		if( function_return.IsError() == true ){
+			return error_report.New( ERROR_CODE_SNAPSHOT_ERROR, map[string]interface{}{ "message": "Couldn't decode a queued event.", "index": i }, &function_return );
+		}
+		events = append(events, function_return.Data["event"].(Event_struct));
+	}
+	event_dispatcher.mutex.Lock();
+	event_dispatcher.add_times = snapshot.Add_times;
+	event_dispatcher.buffered = snapshot.Buffered;
+	event_dispatcher.mutex.Unlock();
+	for i = 0; i < len(event_listeners); i++ {
+		event_dispatcher.AddEventListener( event_listeners[i] );
+	}
+	return_report = event_dispatcher.restoreEvents( events );
+	if( return_report.NoError() == true ){
+		return_report = error_report.New( 0, map[string]interface{}{ "events": len(events), "event_listeners": len(event_listeners) }, nil );
+	}
+	//Return
+	return return_report;
+}
+
+/**
+* @fn restoreEvents
+* @brief Queues restored events as they are, without restamping them or applying the queue capacity.
+* @struct event_dispatcher *EventDispatcher_struct
+* @param events []Event_struct [in] The events, in order.
+* @return ( return_report error_report.ErrorReport_struct ) 
+* @retval 0 Success
+* @retval 1 Not Supported
+* @retval >1 Error
+*/
+
+// restoreEvents queues restored events as they are, without restamping them or applying the queue capacity; they're still written to the write-ahead log or queue backend, if there is one. It stops at the first event which can't be queued, with the "restored" datum counting those before it.
+func (event_dispatcher *EventDispatcher_struct) restoreEvents( events []Event_struct ) ( return_report error_report.ErrorReport_struct ){
+	//Variables
+	var restored int;
+	//Parametres
+	//Function
+	return_report = error_report.New( 0, map[string]interface{}{}, nil );
+	if( event_dispatcher.queue_backend != nil ){
+		for restored < len(events) {
+			return_report = event_dispatcher.queue_backend.Enqueue( events[restored] );
+			if( return_report.IsError() == true ){
+				break;
+			}
+			restored++;
+		}
+	} else{
+		event_dispatcher.mutex.Lock();
+		for restored < len(events) {
+			return_report = event_dispatcher.logEvent_Unsafe( &events[restored], return_report );
+			if( return_report.IsError() == true ){
+				break;
+			}
+			event_dispatcher.getEventsQueue_Unsafe().PushBack( events[restored] );
+			restored++;
+		}
+		event_dispatcher.mutex.Unlock();
+	}
+	event_dispatcher.mutex.Lock();
+	event_dispatcher.wake_Unsafe();
+	event_dispatcher.mutex.Unlock();
+	if( return_report.IsError() == true ){
+		return_report = error_report.New( ERROR_CODE_SNAPSHOT_ERROR, map[string]interface{}{ "message": "Couldn't queue a restored event; the events before it were queued.", "restored": restored }, &return_report );
+	}
+	//Return
+	return return_report;
+}
+
+//# Global Variables
+var(
+	//## Exported Variables
+	//## Private Variables
+	listener_functions_rwmutex sync.RWMutex;
+	listener_functions_map map[string]func( ctx context.Context, event Event_struct, args ...interface{} ) error = map[string]func( ctx context.Context, event Event_struct, args ...interface{} ) error{};
+);
+
+//# Exported Functions
+/**
+* @fn RegisterEventListenerFunction
+* @brief Registers a listener function under a name, for `NewNamedEventListener` and `Restore`.
+* @param name string [in] The function's name; unique within the process.
+* @param function func( ctx context.Context, event Event_struct, args ...interface{}) error [in] The function.
+* @return ( return_report error_report.ErrorReport_struct ) 
+* @retval 0 Success
+* @retval 1 Not Supported
+* @retval >1 Error
+*/
+
+// RegisterEventListenerFunction registers a listener function under a name, so listeners using it can be created with `NewNamedEventListener`, recorded by `Snapshot`, and recreated by `Restore`, in this process or another registering the same names. Registering a name twice fails with ERROR_CODE_SNAPSHOT_ERROR.
+func RegisterEventListenerFunction( name string, function func( ctx context.Context, event Event_struct, args ...interface{}) error ) ( return_report error_report.ErrorReport_struct ){
+	//Variables
+	var exists bool;
+	//Parametres
+	if( name == "" || function == nil ){
+		return error_report.New( ERROR_CODE_SNAPSHOT_ERROR, map[string]interface{}{ "message": "name and function are required.", "name": name }, nil );
+	}
+	//Function
+	listener_functions_rwmutex.Lock();
+	_, exists = listener_functions_map[name];
+	if( exists == false ){
+		listener_functions_map[name] = function;
+	}
+	listener_functions_rwmutex.Unlock();
+	if( exists == false ){
+		return_report = error_report.New( 0, map[string]interface{}{}, nil );
+	} else{
+		return_report = error_report.New( ERROR_CODE_SNAPSHOT_ERROR, map[string]interface{}{ "message": "A listener function is already registered under that name.", "name": name }, nil );
+	}
+	//Return
+	return return_report;
+}
+
+/**
+* @fn NewNamedEventListener
+* @brief Creates an event listener calling the function registered under `function_name`.
+* @param key matchkey.MatchKey_struct [in] The key event names are matched against.
+* @param async bool [in] Whether the function is called asynchronously.
+* @param priority int64 [in] Higher priorities are called first.
+* @param function_name string [in] As given to `RegisterEventListenerFunction`.
+* @return ( return_report error_report.ErrorReport_struct ) 
+* @retval 0 Success
+* @retval 1 Not Supported
+* @retval >1 Error
+*/
+
+// NewNamedEventListener creates an event listener, as `NewContextEventListener` does, calling the function registered under `function_name`; unlike other listeners, `Snapshot` records it. ERROR_CODE_UNREGISTERED_LISTENER_FUNCTION if nothing is registered under the name.
+func NewNamedEventListener( key matchkey.MatchKey_struct, async bool, priority int64, function_name string ) ( return_report error_report.ErrorReport_struct ){
+	//Variables
+	var function func( ctx context.Context, event Event_struct, args ...interface{} ) error;
+	var event_listener EventListener_struct;
+	var ok bool;
+	//Parametres
+	//Function
+	listener_functions_rwmutex.RLock();
+	function, ok = listener_functions_map[function_name];
+	listener_functions_rwmutex.RUnlock();
+	if( ok == false ){
+		return error_report.New( ERROR_CODE_UNREGISTERED_LISTENER_FUNCTION, map[string]interface{}{ "message": "No listener function is registered under that name.", "function": function_name }, nil );
+	}
+	return_report = NewContextEventListener( key, async, priority, function );
+	if( return_report.NoError() == true ){
+		event_listener = return_report.Data["event_listener"].(EventListener_struct);
+		event_listener.function_name = function_name;
+		return_report.Data["event_listener"] = event_listener;
+	}
+	//Return
+	return return_report;
+}
@@ -0,0 +1,141 @@
+/**
+* @file snapshot_test.go
+* @brief Contains test functions for `snapshot.go`.
+* @author Anadian
+* @copyright 	Copyright 2019 Canosw
+	Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+software and associated documentation files (the "Software"), to deal in the Software 
+without restriction, including without limitation the rights to use, copy, modify, 
+merge, publish, distribute, sublicense, and/or sell copies of the Software, and to 
+permit persons to whom the Software is furnished to do so, subject to the following 
+conditions:
+	The above copyright notice and this permission notice shall be included in all copies 
+or substantial portions of the Software.
+	THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A 
+PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT 
+HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF 
+CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE 
+OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+// snapshot_test contains test functions for `snapshot.go`.
+package event_dispatcher;
+
+//# Dependencies
+import(
+	//## Internal
+	//## Standard
+	"testing"
+	"log"
+	"bytes"
+	"context"
+	"strings"
+	"time"
+	//## External
+	error_report "github.com/Anadian/error_report/source"
+	matchkey "github.com/Anadian/matchkey/source"
+);
+
+//# Global Variables
+var(
+	//## Private Variables
+	///Listener functions stay registered for the process, so what they collect lives here and is reset by each run of the test.
+	snapshot_test_correlation_ids []string;
+);
+
+//# Exported Functions
+/**
+* @fn TestSnapshot
+* @brief Tests snapshotting a dispatcher's queue, flags, and named listeners, and restoring them into another.
+* @param t *testing.T [in] Go stdlib testing object.
+*/
+
+// TestSnapshot tests snapshotting a dispatcher's queue, flags, and named listeners, and restoring them into another.
+func TestSnapshot( t *testing.T ){
+	//Variables
+	var old_event_dispatcher *EventDispatcher_struct;
+	var new_event_dispatcher *EventDispatcher_struct;
+	var function_return error_report.ErrorReport_struct;
+	var key matchkey.MatchKey_struct;
+	var submission_time time.Time;
+	var restored_event Event_struct;
+	var buffer bytes.Buffer;
+	//Parametres
+	//Function
+	snapshot_test_correlation_ids = nil;
+	RegisterEventListenerFunction( "snapshot_test.collect", func( ctx context.Context, event Event_struct, args ...interface{} ) error{
+		snapshot_test_correlation_ids = append(snapshot_test_correlation_ids, event.CorrelationID());
+		return nil;
+	} );
+	if( RegisterEventListenerFunction( "snapshot_test.collect", func( ctx context.Context, event Event_struct, args ...interface{} ) error{ return nil; } ).CodeEqual( ERROR_CODE_SNAPSHOT_ERROR ) == true ){
+		log.Printf("Success: A listener function name can't be registered twice.\n");
+	} else{
+		t.Fail();
+		log.Printf("Failure: A listener function name was registered twice.\n");
+	}
+	key, _ = matchkey.New( matchkey.MATCHKEY_TYPE_REGEX, "^snapshot:" );
+	if( NewNamedEventListener( key, false, 0, "snapshot_test.missing" ).CodeEqual( ERROR_CODE_UNREGISTERED_LISTENER_FUNCTION ) == false ){
+		t.Fail();
+		log.Printf("Failure: NewNamedEventListener accepted an unregistered name.\n");
+	}
+	///The old process: two pending events, a named listener, and an unnamed one.
+	old_event_dispatcher = newEventDispatcher( true, true );
+	old_event_dispatcher.AddEventListener( NewNamedEventListener( key, false, 5, "snapshot_test.collect" ).Data["event_listener"].(EventListener_struct) );
+	old_event_dispatcher.AddEventListener( NewEventListener( key, false, func( event Event_struct, args ...interface{} ){} ).Data["event_listener"].(EventListener_struct) );
+	old_event_dispatcher.PushEvent( NewEvent( "snapshot:first", map[string]interface{}{ "amount": 10 } ).Data["event"].(Event_struct).WithCorrelationID( "invoice-1" ) );
+	old_event_dispatcher.PushEvent( NewEvent( "snapshot:second", map[string]interface{}{ "amount": 20 } ).Data["event"].(Event_struct).WithCorrelationID( "invoice-2" ) );
+	submission_time = old_event_dispatcher.GetEventByIndex( 0 ).Data["event"].(Event_struct).data["submission_time"].(time.Time);
+	function_return = old_event_dispatcher.Snapshot( &buffer );
+	if( function_return.NoError() == true && function_return.Data["events"] == 2 && function_return.Data["event_listeners"] == 1 && function_return.Data["skipped_event_listeners"] == 1 ){
+		log.Printf("Success: Snapshot recorded the queue and the named listener.\n");
+	} else{
+		t.Fail();
+		log.Printf("Failure: Snapshot returned: %v\n", function_return);
+	}
+	///The new process.
+	new_event_dispatcher = newEventDispatcher( false, false );
+	function_return = new_event_dispatcher.Restore( &buffer );
+	restored_event = new_event_dispatcher.GetEventByIndex( 0 ).Data["event"].(Event_struct);
+	if( function_return.NoError() == true && new_event_dispatcher.add_times == true && new_event_dispatcher.buffered == true && restored_event.data["submission_time"].(time.Time).Equal( submission_time ) == true && restored_event.data["amount"] == 10 ){
+		log.Printf("Success: Restore set the flags and queued the events as they were.\n");
+	} else{
+		t.Fail();
+		log.Printf("Failure: Restore returned %v; first event: %v\n", function_return, restored_event);
+	}
+	new_event_dispatcher.ProcessEvents();
+	if( len(snapshot_test_correlation_ids) == 2 && snapshot_test_correlation_ids[0] == "invoice-1" && snapshot_test_correlation_ids[1] == "invoice-2" ){
+		log.Printf("Success: The restored listener handled the restored events in order.\n");
+	} else{
+		t.Fail();
+		log.Printf("Failure: The restored listener saw: %v\n", snapshot_test_correlation_ids);
+	}
+	///A snapshot Restore can't fully honour changes nothing.
+	new_event_dispatcher = newEventDispatcher( false, false );
+	function_return = new_event_dispatcher.Restore( strings.NewReader( `{"format":"event_dispatcher.snapshot","version":99,"buffered":true}` ) );
+	if( function_return.CodeEqual( ERROR_CODE_SNAPSHOT_ERROR ) == true && new_event_dispatcher.buffered == false ){
+		log.Printf("Success: Restore refused a newer version.\n");
+	} else{
+		t.Fail();
+		log.Printf("Failure: Restore of a newer version returned: %v\n", function_return);
+	}
+	function_return = new_event_dispatcher.Restore( strings.NewReader( `{"format":"event_dispatcher.snapshot","version":1,"buffered":true,"event_listeners":[{"function":"snapshot_test.missing","matchkey_type":1,"matchkey_string":"x"}],"events":[]}` ) );
+	if( function_return.CodeEqual( ERROR_CODE_UNREGISTERED_LISTENER_FUNCTION ) == true && function_return.Data["function"] == "snapshot_test.missing" && new_event_dispatcher.buffered == false ){
+		log.Printf("Success: Restore refused a snapshot naming an unregistered function.\n");
+	} else{
+		t.Fail();
+		log.Printf("Failure: Restore with an unregistered function returned: %v\n", function_return);
+	}
+	///Queueing fails from the first event once the write-ahead log is closed.
+	old_event_dispatcher.Snapshot( &buffer );
+	new_event_dispatcher = NewEventDispatcherWithWriteAheadLog( false, t.TempDir(), WriteAheadLogOptions_struct{ Fsync_policy: FSYNC_POLICY_NEVER } ).Data["event_dispatcher"].(*EventDispatcher_struct);
+	new_event_dispatcher.Close();
+	function_return = new_event_dispatcher.Restore( &buffer );
+	if( function_return.CodeEqual( ERROR_CODE_SNAPSHOT_ERROR ) == true && function_return.Data["restored"] == 0 && new_event_dispatcher.buffered == true ){
+		log.Printf("Success: Restore reported that no events were queued, after setting the flags.\n");
+	} else{
+		t.Fail();
+		log.Printf("Failure: Restore into a closed write-ahead log returned: %v\n", function_return);
+	}
+	//Return
+}
@@ -0,0 +1,340 @@
+/**
+* @file sqlite_queue.go
+* @brief A queue backend storing events in a SQLite table.
+* @author Anadian
+* @copyright 	Copyright 2019 Canosw
+	Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+software and associated documentation files (the "Software"), to deal in the Software 
+without restriction, including without limitation the rights to use, copy, modify, 
+merge, publish, distribute, sublicense, and/or sell copies of the Software, and to 
+permit persons to whom the Software is furnished to do so, subject to the following 
+conditions:
+	The above copyright notice and this permission notice shall be included in all copies 
+or substantial portions of the Software.
+	THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A 
+PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT 
+HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF 
+CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE 
+OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+// sqlite_queue implements `event_dispatcher.QueueBackend_interface` with a SQLite database, using the pure-Go "sqlite" driver.
+package sqlite_queue;
+
+//# Dependencies
+import(
+	//## Internal
+	event_dispatcher "github.com/Anadian/event_dispatcher/source"
+	//## Standard
+	"database/sql"
+	"fmt"
+	"regexp"
+	"time"
+	//## External
+	error_report "github.com/Anadian/error_report/source"
+	_ "modernc.org/sqlite"
+);
+
+//# Constants
+const(
+	//## Exported Constants
+	//## Private Constants
+);
+
+//# Types
+//## Structs
+// sqlite_queue_backend_struct implements `event_dispatcher.QueueBackend_interface` with a table ordered by its integer primary key. Visible events have a `visible_at` of 0, so the index on `( visible_at, id )` finds the head of the queue without walking the events in flight; hidden events have the time they become visible, and each operation first sets those whose time has passed back to 0. Each statement is atomic without an explicit transaction.
+type sqlite_queue_backend_struct struct{
+	database *sql.DB
+	codec event_dispatcher.Codec_interface
+	//Statements with the table's name filled in.
+	restore_query string
+	insert_query string
+	dequeue_query string
+	delete_query string
+	select_query string
+	remove_query string
+	count_query string
+}
+//### Methods
+/**
+* @fn Enqueue
+* @brief Implements `event_dispatcher.QueueBackend_interface`.
+* @struct sqlite_queue_backend *sqlite_queue_backend_struct
+* @param event event_dispatcher.Event_struct [in] The event to enqueue.
+* @return ( return_report error_report.ErrorReport_struct ) 
+* @retval 0 Success
+* @retval 1 Not Supported
+* @retval >1 Error
+*/
+
+// Enqueue implements `event_dispatcher.QueueBackend_interface`.
+func (sqlite_queue_backend *sqlite_queue_backend_struct) Enqueue( event event_dispatcher.Event_struct ) ( return_report error_report.ErrorReport_struct ){
+	//Variables
+	var function_return error_report.ErrorReport_struct;
+	var result sql.Result;
+	var id int64;
+	var query_error error;
+	//Parametres
+	//Function
+	function_return = sqlite_queue_backend.codec.Encode( event );
+	if( function_return.IsError() == true ){
+		return error_report.New( event_dispatcher.ERROR_CODE_QUEUE_BACKEND_ERROR, map[string]interface{}{ "message": "Couldn't encode the event.", "event": event }, &function_return );
+	}
+	result, query_error = sqlite_queue_backend.database.Exec( sqlite_queue_backend.insert_query, sqlite_queue_backend.codec.Name(), function_return.Data["encoded"].([]byte) );
+	if( query_error == nil ){
+		id, query_error = result.LastInsertId();
+	}
+	return_report = sqliteQueueReport( query_error, map[string]interface{}{ "id": uint64(id) } );
+	//Return
+	return return_report;
+}
+
+/**
+* @fn Dequeue
+* @brief Implements `event_dispatcher.QueueBackend_interface`.
+* @struct sqlite_queue_backend *sqlite_queue_backend_struct
+* @param visibility_timeout time.Duration [in] How long to hide the event.
+* @return ( return_report error_report.ErrorReport_struct ) 
+* @retval 0 Success
+* @retval 1 Not Supported
+* @retval >1 Error
+*/
+
+// Dequeue implements `event_dispatcher.QueueBackend_interface`.
+func (sqlite_queue_backend *sqlite_queue_backend_struct) Dequeue( visibility_timeout time.Duration ) ( return_report error_report.ErrorReport_struct ){
+	//Variables
+	var now time.Time = time.Now();
+	var id int64;
+	var query_error error;
+	//Parametres
+	//Function
+	_, query_error = sqlite_queue_backend.database.Exec( sqlite_queue_backend.restore_query, now.UnixNano() );
+	if( query_error != nil ){
+		return sqliteQueueReport( query_error, nil );
+	}
+	return_report = sqlite_queue_backend.scanEvent( sqlite_queue_backend.database.QueryRow( sqlite_queue_backend.dequeue_query, now.Add( visibility_timeout ).UnixNano() ), &id );
+	if( return_report.NoError() == true ){
+		return_report.Data["id"] = uint64(id);
+	}
+	//Return
+	return return_report;
+}
+
+/**
+* @fn Acknowledge
+* @brief Implements `event_dispatcher.QueueBackend_interface`.
+* @struct sqlite_queue_backend *sqlite_queue_backend_struct
+* @param id uint64 [in] As returned by `Dequeue`.
+* @return ( return_report error_report.ErrorReport_struct ) 
+* @retval 0 Success
+* @retval 1 Not Supported
+* @retval >1 Error
+*/
+
+// Acknowledge implements `event_dispatcher.QueueBackend_interface`.
+func (sqlite_queue_backend *sqlite_queue_backend_struct) Acknowledge( id uint64 ) ( return_report error_report.ErrorReport_struct ){
+	//Variables
+	var query_error error;
+	//Parametres
+	//Function
+	_, query_error = sqlite_queue_backend.database.Exec( sqlite_queue_backend.delete_query, int64(id) );
+	return_report = sqliteQueueReport( query_error, map[string]interface{}{} );
+	//Return
+	return return_report;
+}
+
+/**
+* @fn Get
+* @brief Implements `event_dispatcher.QueueBackend_interface`.
+* @struct sqlite_queue_backend *sqlite_queue_backend_struct
+* @param index uint [in] The index among the visible events.
+* @return ( return_report error_report.ErrorReport_struct ) 
+* @retval 0 Success
+* @retval 1 Not Supported
+* @retval >1 Error
+*/
+
+// Get implements `event_dispatcher.QueueBackend_interface`.
+func (sqlite_queue_backend *sqlite_queue_backend_struct) Get( index uint ) ( return_report error_report.ErrorReport_struct ){
+	//Variables
+	var id int64;
+	var query_error error;
+	//Parametres
+	//Function
+	_, query_error = sqlite_queue_backend.database.Exec( sqlite_queue_backend.restore_query, time.Now().UnixNano() );
+	if( query_error != nil ){
+		return sqliteQueueReport( query_error, nil );
+	}
+	return_report = sqlite_queue_backend.scanEvent( sqlite_queue_backend.database.QueryRow( sqlite_queue_backend.select_query, int64(index) ), &id );
+	//Return
+	return return_report;
+}
+
+/**
+* @fn Remove
+* @brief Implements `event_dispatcher.QueueBackend_interface`.
+* @struct sqlite_queue_backend *sqlite_queue_backend_struct
+* @param index uint [in] The index among the visible events.
+* @return ( return_report error_report.ErrorReport_struct ) 
+* @retval 0 Success
+* @retval 1 Not Supported
+* @retval >1 Error
+*/
+
+// Remove implements `event_dispatcher.QueueBackend_interface`.
+func (sqlite_queue_backend *sqlite_queue_backend_struct) Remove( index uint ) ( return_report error_report.ErrorReport_struct ){
+	//Variables
+	var id int64;
+	var query_error error;
+	//Parametres
+	//Function
+	_, query_error = sqlite_queue_backend.database.Exec( sqlite_queue_backend.restore_query, time.Now().UnixNano() );
+	if( query_error != nil ){
+		return sqliteQueueReport( query_error, nil );
+	}
+	return_report = sqlite_queue_backend.scanEvent( sqlite_queue_backend.database.QueryRow( sqlite_queue_backend.remove_query, int64(index) ), &id );
+	//Return
+	return return_report;
+}
+
+/**
+* @fn Length
+* @brief Implements `event_dispatcher.QueueBackend_interface`.
+* @struct sqlite_queue_backend *sqlite_queue_backend_struct
+* @return ( return_report error_report.ErrorReport_struct ) 
+* @retval 0 Success
+* @retval 1 Not Supported
+* @retval >1 Error
+*/
+
+// Length implements `event_dispatcher.QueueBackend_interface`.
+func (sqlite_queue_backend *sqlite_queue_backend_struct) Length() ( return_report error_report.ErrorReport_struct ){
+	//Variables
+	var length int;
+	var query_error error;
+	//Parametres
+	//Function
+	_, query_error = sqlite_queue_backend.database.Exec( sqlite_queue_backend.restore_query, time.Now().UnixNano() );
+	if( query_error == nil ){
+		query_error = sqlite_queue_backend.database.QueryRow( sqlite_queue_backend.count_query ).Scan( &length );
+	}
+	return_report = sqliteQueueReport( query_error, map[string]interface{}{ "length": length } );
+	//Return
+	return return_report;
+}
+
+/**
+* @fn scanEvent
+* @brief Scans an ID, codec name, and encoded event from the row, returning the decoded "event" datum.
+* @struct sqlite_queue_backend *sqlite_queue_backend_struct
+* @param row *sql.Row [in] The result of a statement returning `id, codec, payload`.
+* @param id *int64 [out] The event's ID.
+* @return ( return_report error_report.ErrorReport_struct ) 
+* @retval 0 Success
+* @retval 1 Not Supported
+* @retval >1 Error
+*/
+
+// scanEvent scans an ID, codec name, and encoded event from the row, returning the decoded "event" datum; ERROR_CODE_INDEX_OUT_OF_RANGE if there's no row, and the "id" datum with ERROR_CODE_UNDECODABLE_QUEUED_EVENT.
+func (sqlite_queue_backend *sqlite_queue_backend_struct) scanEvent( row *sql.Row, id *int64 ) ( return_report error_report.ErrorReport_struct ){
+	//Variables
+	var codec_name string;
+	var encoded []byte;
+	var scan_error error;
+	//Parametres
+	//Function
+	scan_error = row.Scan( id, &codec_name, &encoded );
+	if( scan_error == sql.ErrNoRows ){
+		return_report = error_report.New( event_dispatcher.ERROR_CODE_INDEX_OUT_OF_RANGE, map[string]interface{}{ "message": "No visible event at that index." }, nil );
+	} else if( scan_error != nil ){
+		return_report = sqliteQueueReport( scan_error, nil );
+	} else{
+		return_report = event_dispatcher.DecodeQueuedEvent( codec_name, encoded );
+		if( return_report.IsError() == true ){
+			return_report.Data["id"] = uint64(*id);
+		}
+	}
+	//Return
+	return return_report;
+}
+
+//# Global Variables
+var(
+	//## Exported Variables
+	//## Private Variables
+	sqlite_table_name_regexp *regexp.Regexp = regexp.MustCompile( `^[A-Za-z_][A-Za-z0-9_]*$` );
+);
+
+//# Exported Functions
+/**
+* @fn New
+* @brief Creates a queue backend storing events in a table of a SQLite database.
+* @param database *sql.DB [in] An open database; the caller remains responsible for closing it.
+* @param table string [in] The table's name, created if need be; letters, digits, and underscores only.
+* @param codec event_dispatcher.Codec_interface [in] How to encode events; the JSON codec if nil.
+* @return ( return_report error_report.ErrorReport_struct ) 
+* @retval 0 Success
+* @retval 1 Not Supported
+* @retval >1 Error
+*/
+
+// New creates a queue backend storing events in a table of a SQLite database, returned as the "queue_backend" datum for `event_dispatcher.NewEventDispatcherWithQueueBackend`, so they can live alongside an application's own tables. This package registers the pure-Go "sqlite" driver, so `sql.Open( "sqlite", path )` works without cgo; a `_pragma=busy_timeout(...)` in the data source name keeps concurrent writers from failing with SQLITE_BUSY.
+func New( database *sql.DB, table string, codec event_dispatcher.Codec_interface ) ( return_report error_report.ErrorReport_struct ){
+	//Variables
+	var sqlite_queue_backend *sqlite_queue_backend_struct;
+	var query_error error;
+	//Parametres
+	if( database == nil || sqlite_table_name_regexp.MatchString( table ) == false ){
+		return error_report.New( event_dispatcher.ERROR_CODE_QUEUE_BACKEND_ERROR, map[string]interface{}{ "message": "database is required and table must be a plain identifier.", "table": table }, nil );
+	}
+	if( codec == nil ){
+		codec = event_dispatcher.JSONCodec;
+	}
+	//Function
+	_, query_error = database.Exec( fmt.Sprintf( `CREATE TABLE IF NOT EXISTS "%[1]s" ( id INTEGER PRIMARY KEY AUTOINCREMENT, visible_at INTEGER NOT NULL, codec TEXT NOT NULL, payload BLOB NOT NULL ); CREATE INDEX IF NOT EXISTS "%[1]s_visible_at" ON "%[1]s" ( visible_at, id );`, table ) );
+	if( query_error != nil ){
+		return sqliteQueueReport( query_error, nil );
+	}
+	sqlite_queue_backend = &sqlite_queue_backend_struct{
+		database: database,
+		codec: codec,
+		restore_query: fmt.Sprintf( `UPDATE "%s" SET visible_at = 0 WHERE visible_at > 0 AND visible_at <= ?`, table ),
+		insert_query: fmt.Sprintf( `INSERT INTO "%s" ( visible_at, codec, payload ) VALUES ( 0, ?, ? )`, table ),
+		dequeue_query: fmt.Sprintf( `UPDATE "%[1]s" SET visible_at = ? WHERE id = ( SELECT id FROM "%[1]s" WHERE visible_at = 0 ORDER BY id LIMIT 1 ) RETURNING id, codec, payload`, table ),
+		delete_query: fmt.Sprintf( `DELETE FROM "%s" WHERE id = ?`, table ),
+		select_query: fmt.Sprintf( `SELECT id, codec, payload FROM "%s" WHERE visible_at = 0 ORDER BY id LIMIT 1 OFFSET ?`, table ),
+		remove_query: fmt.Sprintf( `DELETE FROM "%[1]s" WHERE id = ( SELECT id FROM "%[1]s" WHERE visible_at = 0 ORDER BY id LIMIT 1 OFFSET ? ) RETURNING id, codec, payload`, table ),
+		count_query: fmt.Sprintf( `SELECT COUNT(*) FROM "%s" WHERE visible_at = 0`, table ),
+	};
+	return_report = error_report.New( 0, map[string]interface{}{ "queue_backend": event_dispatcher.QueueBackend_interface(sqlite_queue_backend) }, nil );
+	//Return
+	return return_report;
+}
+
+//# Private Functions
+/**
+* @fn sqliteQueueReport
+* @brief Returns a report with the data, or ERROR_CODE_QUEUE_BACKEND_ERROR if the statement failed.
+* @param query_error error [in] The error returned by the statement.
+* @param data map[string]interface{} [in] The data on success.
+* @return ( return_report error_report.ErrorReport_struct ) 
+* @retval 0 Success
+* @retval 1 Not Supported
+* @retval >1 Error
+*/
+
+// sqliteQueueReport returns a report with the data, or ERROR_CODE_QUEUE_BACKEND_ERROR if the statement failed.
+func sqliteQueueReport( query_error error, data map[string]interface{} ) ( return_report error_report.ErrorReport_struct ){
+	//Variables
+	//Parametres
+	//Function
+	if( query_error == nil ){
+		return_report = error_report.New( 0, data, nil );
+	} else{
+		return_report = error_report.New( event_dispatcher.ERROR_CODE_QUEUE_BACKEND_ERROR, map[string]interface{}{ "message": query_error.Error(), "error": query_error }, nil );
+	}
+	//Return
+	return return_report;
+}
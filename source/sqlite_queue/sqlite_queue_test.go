@@ -0,0 +1,79 @@
+/**
+* @file sqlite_queue_test.go
+* @brief Contains test functions for `sqlite_queue.go`.
+* @author Anadian
+* @copyright 	Copyright 2019 Canosw
+	Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+software and associated documentation files (the "Software"), to deal in the Software 
+without restriction, including without limitation the rights to use, copy, modify, 
+merge, publish, distribute, sublicense, and/or sell copies of the Software, and to 
+permit persons to whom the Software is furnished to do so, subject to the following 
+conditions:
+	The above copyright notice and this permission notice shall be included in all copies 
+or substantial portions of the Software.
+	THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A 
+PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT 
+HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF 
+CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE 
+OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+// sqlite_queue_test contains test functions for `sqlite_queue.go`.
+package sqlite_queue;
+
+//# Dependencies
+import(
+	//## Internal
+	event_dispatcher "github.com/Anadian/event_dispatcher/source"
+	queue_backend_testing "github.com/Anadian/event_dispatcher/source/queue_backend_testing"
+	//## Standard
+	"testing"
+	"log"
+	"database/sql"
+	"path/filepath"
+	"strconv"
+	//## External
+	error_report "github.com/Anadian/error_report/source"
+);
+
+//# Exported Functions
+/**
+* @fn TestSQLiteQueueBackend
+* @brief Runs the queue backend tests against a SQLite database.
+* @param t *testing.T [in] Go stdlib testing object.
+*/
+
+// TestSQLiteQueueBackend runs the queue backend tests against a SQLite database, with and without a dispatcher, and checks table names are validated.
+func TestSQLiteQueueBackend( t *testing.T ){
+	//Variables
+	var database *sql.DB;
+	var tables int;
+	var open_error error;
+	var new_queue_backend func( codec event_dispatcher.Codec_interface ) event_dispatcher.QueueBackend_interface;
+	//Parametres
+	//Function
+	database, open_error = sql.Open( "sqlite", "file:" + filepath.Join( t.TempDir(), "queue.sqlite" ) + "?_pragma=busy_timeout(5000)" );
+	if( open_error != nil ){
+		t.Fatalf("Failure: sql.Open returned: %v\n", open_error);
+	}
+	defer database.Close();
+	if( New( database, `events"; DROP TABLE x; --`, nil ).CodeEqual( event_dispatcher.ERROR_CODE_QUEUE_BACKEND_ERROR ) == true ){
+		log.Printf("Success: New rejected an unsafe table name.\n");
+	} else{
+		t.Fail();
+		log.Printf("Failure: New accepted an unsafe table name.\n");
+	}
+	new_queue_backend = func( codec event_dispatcher.Codec_interface ) event_dispatcher.QueueBackend_interface{
+		var function_return error_report.ErrorReport_struct;
+		tables++;
+		function_return = New( database, "event_queue_" + strconv.Itoa( tables ), codec );
+		if( function_return.IsError() == true ){
+			t.Fatalf("Failure: New returned: %v\n", function_return);
+		}
+		return function_return.Data["queue_backend"].(event_dispatcher.QueueBackend_interface);
+	};
+	queue_backend_testing.TestQueueBackend( t, new_queue_backend );
+	queue_backend_testing.TestEventDispatcher( t, new_queue_backend );
+	//Return
+}
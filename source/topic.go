@@ -0,0 +1,215 @@
+/**
+* @file topic.go
+* @brief Typed topics: a generic layer over the dispatcher giving event payloads a compile-time type.
+* @author Anadian
+* @copyright 	Copyright 2019 Canosw
+	Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+software and associated documentation files (the "Software"), to deal in the Software 
+without restriction, including without limitation the rights to use, copy, modify, 
+merge, publish, distribute, sublicense, and/or sell copies of the Software, and to 
+permit persons to whom the Software is furnished to do so, subject to the following 
+conditions:
+	The above copyright notice and this permission notice shall be included in all copies 
+or substantial portions of the Software.
+	THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A 
+PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT 
+HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF 
+CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE 
+OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+package event_dispatcher;
+
+//# Dependencies
+import(
+	//## Internal
+	//## Standard
+	"context"
+	"errors"
+	"fmt"
+	//## External
+	error_report "github.com/Anadian/error_report/source"
+	matchkey "github.com/Anadian/matchkey/source"
+);
+
+//# Constants
+const(
+	//## Exported Constants
+	//### Errors
+	ERROR_CODE_INVALID_TOPIC int64 = 32;
+	//### Data Keys
+	TOPIC_PAYLOAD_KEY string = "payload" //The key under which a topic stores its payload in the event's data.
+	//## Private Constants
+);
+
+//# Types
+//## Structs
+// Topic_struct publishes and subscribes to events named `name` carrying a payload of type `T`, so neither side has to type-assert map values. The events are ordinary events on the underlying dispatcher, so untyped listeners see them too, with the payload under `TOPIC_PAYLOAD_KEY`.
+type Topic_struct[T any] struct{
+	name string
+	event_dispatcher *EventDispatcher_struct
+}
+//### Methods
+/**
+* @fn Name
+* @brief Returns the name of the events published on the topic.
+* @struct topic *Topic_struct[T]
+* @return string
+*/
+
+// Name returns the name of the events published on the topic.
+func (topic *Topic_struct[T]) Name() string{
+	//Variables
+	//Parametres
+	//Function
+	//Return
+	return topic.name;
+}
+
+/**
+* @fn Publish
+* @brief Publishes an event carrying the payload, as `EventDispatcher_struct.Publish` does.
+* @struct topic *Topic_struct[T]
+* @param payload T [in] The payload.
+* @return ( return_report error_report.ErrorReport_struct ) 
+* @retval 0 Success
+* @retval 1 Not Supported
+* @retval >1 Error
+*/
+
+// Publish publishes an event carrying the payload, as `EventDispatcher_struct.Publish` does.
+func (topic *Topic_struct[T]) Publish( payload T ) ( return_report error_report.ErrorReport_struct ){
+	//Variables
+	//Parametres
+	//Function
+	return_report = topic.PublishCtx( context.Background(), payload );
+	//Return
+	return return_report;
+}
+
+/**
+* @fn PublishCtx
+* @brief Publishes an event carrying the payload, as `EventDispatcher_struct.PublishCtx` does.
+* @struct topic *Topic_struct[T]
+* @param ctx context.Context [in] Passed on to the listeners.
+* @param payload T [in] The payload.
+* @return ( return_report error_report.ErrorReport_struct ) 
+* @retval 0 Success
+* @retval 1 Not Supported
+* @retval >1 Error
+*/
+
+// PublishCtx publishes an event carrying the payload, as `EventDispatcher_struct.PublishCtx` does.
+func (topic *Topic_struct[T]) PublishCtx( ctx context.Context, payload T ) ( return_report error_report.ErrorReport_struct ){
+	//Variables
+	var function_return error_report.ErrorReport_struct;
+	//Parametres
+	//Function
+	function_return = NewEvent( topic.name, map[string]interface{}{ TOPIC_PAYLOAD_KEY: payload } );
+	if( function_return.NoError() == true ){
+		return_report = topic.event_dispatcher.PublishCtx( ctx, function_return.Data["event"].(Event_struct) );
+	} else{
+		return_report = error_report.New( ERROR_CODE_SUBORDINATE_FUNCTION_ERROR, map[string]interface{}{ "message": "NewEvent returned an error.", "topic": topic.name }, &function_return );
+	}
+	//Return
+	return return_report;
+}
+
+/**
+* @fn Subscribe
+* @brief Adds a listener for the topic's payloads.
+* @struct topic *Topic_struct[T]
+* @param async bool [in] Whether the function should be called in its own goroutine, or on the worker pool.
+* @param function func( payload T ) [in] Called with each published payload.
+* @return ( return_report error_report.ErrorReport_struct ) 
+* @retval 0 Success
+* @retval 1 Not Supported
+* @retval >1 Error
+*/
+
+// Subscribe adds a listener for the topic's payloads; the report's "subscription" datum can be used to unsubscribe.
+func (topic *Topic_struct[T]) Subscribe( async bool, function func( payload T ) ) ( return_report error_report.ErrorReport_struct ){
+	//Variables
+	//Parametres
+	//Function
+	return_report = topic.SubscribeCtx( async, 0, func( ctx context.Context, payload T ) error{
+		function( payload );
+		return nil;
+	} );
+	//Return
+	return return_report;
+}
+
+/**
+* @fn SubscribeCtx
+* @brief Adds a prioritised, fallible listener for the topic's payloads which also receives the publisher's context.
+* @struct topic *Topic_struct[T]
+* @param async bool [in] Whether the function should be called in its own goroutine, or on the worker pool.
+* @param priority int64 [in] The listener's priority; see `NewPriorityEventListener`.
+* @param function func( ctx context.Context, payload T ) error [in] Called with each published payload; errors are handled as for `NewFallibleEventListener`.
+* @return ( return_report error_report.ErrorReport_struct ) 
+* @retval 0 Success
+* @retval 1 Not Supported
+* @retval >1 Error
+*/
+
+// SubscribeCtx adds a prioritised, fallible listener for the topic's payloads which also receives the publisher's context. An event of the topic's name published through the untyped API without a payload of type `T` fails the listener with an error wrapping `ErrPayloadTypeMismatch`.
+func (topic *Topic_struct[T]) SubscribeCtx( async bool, priority int64, function func( ctx context.Context, payload T ) error ) ( return_report error_report.ErrorReport_struct ){
+	//Variables
+	var key matchkey.MatchKey_struct;
+	var function_return error_report.ErrorReport_struct;
+	//Parametres
+	//Function
+	key, function_return = matchkey.New( matchkey.MATCHKEY_TYPE_STRING, topic.name );
+	if( function_return.NoError() == true ){
+		function_return = NewContextEventListener( key, async, priority, func( ctx context.Context, event Event_struct, args ...interface{} ) error{
+			var payload T;
+			var ok bool;
+			payload, ok = event.data[TOPIC_PAYLOAD_KEY].(T);
+			if( ok == false ){
+				return fmt.Errorf( "%w: topic %q got a %T payload, expected %T", ErrPayloadTypeMismatch, topic.name, event.data[TOPIC_PAYLOAD_KEY], payload );
+			}
+			return function( ctx, payload );
+		} );
+	}
+	if( function_return.NoError() == true ){
+		return_report = topic.event_dispatcher.AddEventListener( function_return.Data["event_listener"].(EventListener_struct) );
+	} else{
+		return_report = error_report.New( ERROR_CODE_SUBORDINATE_FUNCTION_ERROR, map[string]interface{}{ "message": "Couldn't create the topic's event listener.", "topic": topic.name }, &function_return );
+	}
+	//Return
+	return return_report;
+}
+
+//# Global Variables
+var(
+	//## Exported Variables
+	ErrPayloadTypeMismatch error = errors.New( "event payload type mismatch" ) //Wrapped by the error a topic's listener returns for an event without a payload of the topic's type.
+	//## Private Variables
+);
+
+//# Exported Functions
+/**
+* @fn NewTopic
+* @brief Creates a typed topic for events of the given name on the given dispatcher.
+* @param event_dispatcher *EventDispatcher_struct [in] The dispatcher the topic's events go through.
+* @param name string [in] The name of the topic's events; matched literally.
+* @return ( return_report error_report.ErrorReport_struct ) 
+* @retval 0 Success
+* @retval 1 Not Supported
+* @retval >1 Error
+*/
+
+// NewTopic creates a typed topic for events of the given name on the given dispatcher; the "topic" datum is a `*Topic_struct[T]`.
+func NewTopic[T any]( event_dispatcher *EventDispatcher_struct, name string ) ( return_report error_report.ErrorReport_struct ){
+	//Variables
+	//Parametres
+	//Function
+	if( event_dispatcher != nil && name != "" ){
+		return_report = error_report.New( 0, map[string]interface{}{ "topic": &Topic_struct[T]{ name: name, event_dispatcher: event_dispatcher } }, nil );
+	} else{
+		return_report = error_report.New( ERROR_CODE_INVALID_TOPIC, map[string]interface{}{ "message": "NewTopic needs a dispatcher and a non-empty name." }, nil );
+	}
+	//Return
+	return return_report;
+}
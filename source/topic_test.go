@@ -0,0 +1,103 @@
+/**
+* @file topic_test.go
+* @brief Contains test functions for `topic.go`.
+* @author Anadian
+* @copyright 	Copyright 2019 Canosw
+	Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+software and associated documentation files (the "Software"), to deal in the Software 
+without restriction, including without limitation the rights to use, copy, modify, 
+merge, publish, distribute, sublicense, and/or sell copies of the Software, and to 
+permit persons to whom the Software is furnished to do so, subject to the following 
+conditions:
+	The above copyright notice and this permission notice shall be included in all copies 
+or substantial portions of the Software.
+	THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A 
+PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT 
+HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF 
+CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE 
+OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+// topic_test contains test functions for `topic.go`.
+package event_dispatcher;
+
+//# Dependencies
+import(
+	//## Internal
+	//## Standard
+	"testing"
+	"log"
+	"errors"
+	//## External
+	error_report "github.com/Anadian/error_report/source"
+);
+
+//# Types
+type topic_test_order_struct struct{
+	ID string
+	Total int
+}
+
+//# Exported Functions
+/**
+* @fn TestTopic
+* @brief Tests publishing and subscribing to typed payloads, and payload type mismatches.
+* @param t *testing.T [in] Go stdlib testing object.
+*/
+
+// TestTopic tests publishing and subscribing to typed payloads, and payload type mismatches.
+func TestTopic( t *testing.T ){
+	//Variables
+	var event_dispatcher *EventDispatcher_struct;
+	var topic *Topic_struct[topic_test_order_struct];
+	var function_return error_report.ErrorReport_struct;
+	var orders []topic_test_order_struct;
+	var listener_error error;
+	var datum interface{};
+	var listener_report error_report.ErrorReport_struct;
+	var ok bool;
+	//Parametres
+	//Function
+	event_dispatcher = newEventDispatcher( false, false );
+	function_return = NewTopic[topic_test_order_struct]( event_dispatcher, "" );
+	if( function_return.CodeEqual( ERROR_CODE_INVALID_TOPIC ) == true ){
+		log.Printf("Success: NewTopic rejected an empty name.\n");
+	} else{
+		t.Fail();
+		log.Printf("Failure: NewTopic returned: %v\n", function_return);
+	}
+	topic = NewTopic[topic_test_order_struct]( event_dispatcher, "order:created" ).Data["topic"].(*Topic_struct[topic_test_order_struct]);
+	function_return = topic.Subscribe( false, func( order topic_test_order_struct ){
+		orders = append(orders, order);
+	} );
+	if( function_return.IsError() == true ){
+		t.Fail();
+		log.Printf("Failure: Subscribe returned: %v\n", function_return);
+	}
+	function_return = topic.Publish( topic_test_order_struct{ ID: "order-1", Total: 42 } );
+	if( function_return.NoError() == true && len(orders) == 1 && orders[0].ID == "order-1" && orders[0].Total == 42 ){
+		log.Printf("Success: The subscriber got the typed payload.\n");
+	} else{
+		t.Fail();
+		log.Printf("Failure: Publish returned %v; the subscriber got %v\n", function_return, orders);
+	}
+	///An untyped event with the topic's name but the wrong payload.
+	function_return = event_dispatcher.Publish( NewEvent( "order:created", map[string]interface{}{ TOPIC_PAYLOAD_KEY: "order-2" } ).Data["event"].(Event_struct) );
+	if( function_return.IsError() == true ){
+		function_return = function_return.GetWrapped();
+		for _, datum = range function_return.Data {
+			listener_report, ok = datum.(error_report.ErrorReport_struct);
+			if( ok == true ){
+				listener_error, _ = listener_report.Data["error"].(error);
+			}
+		}
+	}
+	if( errors.Is( listener_error, ErrPayloadTypeMismatch ) == true && len(orders) == 1 ){
+		log.Printf("Success: A mistyped payload failed the listener: %v\n", listener_error);
+	} else{
+		t.Fail();
+		log.Printf("Failure: A mistyped payload gave: %v\n", function_return);
+	}
+	//Return
+}
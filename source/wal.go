@@ -0,0 +1,777 @@
+/**
+* @file wal.go
+* @brief A segmented, append-only write-ahead log making a buffered dispatcher's queue survive crashes.
+* @author Anadian
+* @copyright 	Copyright 2019 Canosw
+	Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+software and associated documentation files (the "Software"), to deal in the Software 
+without restriction, including without limitation the rights to use, copy, modify, 
+merge, publish, distribute, sublicense, and/or sell copies of the Software, and to 
+permit persons to whom the Software is furnished to do so, subject to the following 
+conditions:
+	The above copyright notice and this permission notice shall be included in all copies 
+or substantial portions of the Software.
+	THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A 
+PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT 
+HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF 
+CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE 
+OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+package event_dispatcher;
+
+//# Dependencies
+import(
+	//## Internal
+	//## Standard
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+	//## External
+	error_report "github.com/Anadian/error_report/source"
+);
+
+//# Constants
+const(
+	//## Exported Constants
+	//### Errors
+	ERROR_CODE_WRITE_AHEAD_LOG_ERROR int64 = 37;
+	//### Fsync Policies
+	FSYNC_POLICY_ALWAYS uint8 = 1 //Sync the log to disk after every record; nothing acknowledged by `PushEvent` is lost. The default.
+	FSYNC_POLICY_INTERVAL uint8 = 2 //Sync every `Fsync_interval`; a crash can lose the records of the last interval.
+	FSYNC_POLICY_NEVER uint8 = 3 //Leave syncing to the operating system; survives the process crashing but not the machine.
+	//## Private Constants
+	wal_record_header uint8 = 0;
+	wal_record_enqueue uint8 = 1;
+	wal_record_acknowledge uint8 = 2;
+	wal_record_prefix_length int = 17; //Payload length (4), CRC-32 (4), type (1), sequence (8).
+	wal_segment_extension string = ".wal";
+	wal_default_segment_size int64 = 64 << 20;
+	wal_default_fsync_interval time.Duration = time.Second;
+);
+
+//# Types
+//## Structs
+// WriteAheadLogOptions_struct configures `NewEventDispatcherWithWriteAheadLog`; the zero value gives FSYNC_POLICY_ALWAYS, 64 MiB segments, and the JSON codec.
+type WriteAheadLogOptions_struct struct{
+	Fsync_policy uint8
+	Fsync_interval time.Duration //For FSYNC_POLICY_INTERVAL; 1 second if 0.
+	Segment_size int64 //A new segment is started once the current one reaches this many bytes.
+	Codec Codec_interface //Encodes the queued events; payload types must be registered as `RegisterPayloadType` describes.
+}
+// write_ahead_log_struct is a directory of numbered segment files, each a header record naming the codec followed by enqueue and acknowledgement records. A segment is deleted once every event enqueued in it has been acknowledged and every segment before it has been deleted: its acknowledgement records may be all that stops events in an earlier segment being recovered.
+type write_ahead_log_struct struct{
+	mutex sync.Mutex
+	directory string
+	options WriteAheadLogOptions_struct
+	file *os.File
+	segment uint64
+	segment_size int64
+	next_sequence uint64
+	//The segment each pending event was enqueued in, and the number pending per segment.
+	segments_map map[uint64]uint64
+	pending_counts_map map[uint64]int
+	//The segments on disk, oldest first.
+	segments_slice []uint64
+	dirty bool
+	stop_channel chan struct{}
+}
+// acknowledgement_struct acknowledges an event once the dispatch which took it from the queue, and every asynchronous listener call that dispatch started, has finished.
+type acknowledgement_struct struct{
+	event_dispatcher *EventDispatcher_struct
+	event Event_struct
+	//Updated atomically; the event is acknowledged when it drops to 0.
+	holds int64
+}
+// wal_record_struct is a record read back from a segment.
+type wal_record_struct struct{
+	record_type uint8
+	sequence uint64
+	payload []byte
+}
+//### Methods
+/**
+* @fn append
+* @brief Writes an enqueue record for the event.
+* @struct write_ahead_log *write_ahead_log_struct
+* @param event Event_struct [in] The event being queued.
+* @return ( sequence uint64, return_report error_report.ErrorReport_struct ) The sequence number to acknowledge the event by.
+*/
+
+// append writes an enqueue record for the event, returning the sequence number to acknowledge it by.
+func (write_ahead_log *write_ahead_log_struct) append( event Event_struct ) ( sequence uint64, return_report error_report.ErrorReport_struct ){
+	//Variables
+	var function_return error_report.ErrorReport_struct;
+	//Parametres
+	//Function
+	function_return = write_ahead_log.options.Codec.Encode( event );
+	if( function_return.IsError() == true ){
+		return 0, error_report.New( ERROR_CODE_WRITE_AHEAD_LOG_ERROR, map[string]interface{}{ "message": "Couldn't encode the event for the write-ahead log.", "event": event }, &function_return );
+	}
+	write_ahead_log.mutex.Lock();
+	sequence = write_ahead_log.next_sequence;
+	return_report = write_ahead_log.writeRecord_Unsafe( wal_record_enqueue, sequence, function_return.Data["encoded"].([]byte) );
+	if( return_report.NoError() == true ){
+		write_ahead_log.next_sequence++;
+		write_ahead_log.segments_map[sequence] = write_ahead_log.segment;
+		write_ahead_log.pending_counts_map[write_ahead_log.segment]++;
+	}
+	write_ahead_log.mutex.Unlock();
+	//Return
+	return sequence, return_report;
+}
+
+/**
+* @fn acknowledge
+* @brief Writes an acknowledgement record for the sequence number, so the event isn't recovered again.
+* @struct write_ahead_log *write_ahead_log_struct
+* @param sequence uint64 [in] As returned by `append`.
+* @return ( return_report error_report.ErrorReport_struct ) 
+* @retval 0 Success
+* @retval 1 Not Supported
+* @retval >1 Error
+*/
+
+// acknowledge writes an acknowledgement record for the sequence number, so the event isn't recovered again, and deletes the segments which no longer need keeping. Unknown or already acknowledged sequence numbers are ignored.
+func (write_ahead_log *write_ahead_log_struct) acknowledge( sequence uint64 ) ( return_report error_report.ErrorReport_struct ){
+	//Variables
+	var segment uint64;
+	var ok bool;
+	//Parametres
+	//Function
+	write_ahead_log.mutex.Lock();
+	segment, ok = write_ahead_log.segments_map[sequence];
+	if( ok == true && write_ahead_log.file != nil ){
+		return_report = write_ahead_log.writeRecord_Unsafe( wal_record_acknowledge, sequence, nil );
+		if( return_report.NoError() == true ){
+			delete( write_ahead_log.segments_map, sequence );
+			write_ahead_log.pending_counts_map[segment]--;
+			write_ahead_log.releaseSegments_Unsafe();
+		}
+	} else{
+		return_report = error_report.New( 0, map[string]interface{}{}, nil );
+	}
+	write_ahead_log.mutex.Unlock();
+	//Return
+	return return_report;
+}
+
+/**
+* @fn close
+* @brief Syncs and closes the current segment; later appends fail.
+* @struct write_ahead_log *write_ahead_log_struct
+* @return ( return_report error_report.ErrorReport_struct ) 
+* @retval 0 Success
+* @retval 1 Not Supported
+* @retval >1 Error
+*/
+
+// close syncs and closes the current segment; later appends fail and acknowledgements are ignored, leaving the events to be recovered.
+func (write_ahead_log *write_ahead_log_struct) close() ( return_report error_report.ErrorReport_struct ){
+	//Variables
+	var close_error error;
+	//Parametres
+	//Function
+	write_ahead_log.mutex.Lock();
+	if( write_ahead_log.file != nil ){
+		close_error = write_ahead_log.file.Sync();
+		if( close_error == nil ){
+			close_error = write_ahead_log.file.Close();
+		} else{
+			write_ahead_log.file.Close();
+		}
+		write_ahead_log.file = nil;
+		close(write_ahead_log.stop_channel);
+	}
+	write_ahead_log.mutex.Unlock();
+	if( close_error == nil ){
+		return_report = error_report.New( 0, map[string]interface{}{}, nil );
+	} else{
+		return_report = error_report.New( ERROR_CODE_WRITE_AHEAD_LOG_ERROR, map[string]interface{}{ "message": close_error.Error(), "error": close_error }, nil );
+	}
+	//Return
+	return return_report;
+}
+
+/**
+* @fn writeRecord_Unsafe
+* @brief Appends a record to the current segment, starting a new segment first if it's full, and syncs according to the policy; the caller must hold `mutex`.
+* @struct write_ahead_log *write_ahead_log_struct
+* @param record_type uint8 [in] One of the `wal_record_*` constants.
+* @param sequence uint64 [in] The event's sequence number.
+* @param payload []byte [in] The encoded event, for enqueue records.
+* @return ( return_report error_report.ErrorReport_struct ) 
+* @retval 0 Success
+* @retval 1 Not Supported
+* @retval >1 Error
+*/
+
+// writeRecord_Unsafe appends a record to the current segment, starting a new segment first if it's full, and syncs according to the policy; the caller must hold `mutex`.
+func (write_ahead_log *write_ahead_log_struct) writeRecord_Unsafe( record_type uint8, sequence uint64, payload []byte ) ( return_report error_report.ErrorReport_struct ){
+	//Variables
+	var record []byte = make([]byte, wal_record_prefix_length + len(payload));
+	var write_error error;
+	//Parametres
+	if( write_ahead_log.file == nil ){
+		return error_report.New( ERROR_CODE_WRITE_AHEAD_LOG_ERROR, map[string]interface{}{ "message": "The write-ahead log is closed." }, nil );
+	}
+	//Function
+	if( record_type != wal_record_header && write_ahead_log.segment_size >= write_ahead_log.options.Segment_size ){
+		return_report = write_ahead_log.openSegment_Unsafe( write_ahead_log.segment + 1 );
+		if( return_report.IsError() == true ){
+			return return_report;
+		}
+		write_ahead_log.releaseSegments_Unsafe();
+	}
+	binary.BigEndian.PutUint32( record[0:4], uint32(len(payload)) );
+	record[8] = record_type;
+	binary.BigEndian.PutUint64( record[9:17], sequence );
+	copy( record[17:], payload );
+	binary.BigEndian.PutUint32( record[4:8], crc32.ChecksumIEEE( record[8:] ) );
+	_, write_error = write_ahead_log.file.Write( record );
+	if( write_error == nil ){
+		write_ahead_log.segment_size += int64(len(record));
+		if( write_ahead_log.options.Fsync_policy == FSYNC_POLICY_ALWAYS ){
+			write_error = write_ahead_log.file.Sync();
+		} else{
+			write_ahead_log.dirty = true;
+		}
+	}
+	if( write_error == nil ){
+		return_report = error_report.New( 0, map[string]interface{}{}, nil );
+	} else{
+		return_report = error_report.New( ERROR_CODE_WRITE_AHEAD_LOG_ERROR, map[string]interface{}{ "message": write_error.Error(), "error": write_error, "segment": write_ahead_log.segment }, nil );
+	}
+	//Return
+	return return_report;
+}
+
+/**
+* @fn openSegment_Unsafe
+* @brief Closes the current segment and starts a new one with the given number; the caller must hold `mutex`.
+* @struct write_ahead_log *write_ahead_log_struct
+* @param segment uint64 [in] The new segment's number.
+* @return ( return_report error_report.ErrorReport_struct ) 
+* @retval 0 Success
+* @retval 1 Not Supported
+* @retval >1 Error
+*/
+
+// openSegment_Unsafe closes the current segment and starts a new one with the given number, beginning with a header record naming the codec; the caller must hold `mutex`.
+func (write_ahead_log *write_ahead_log_struct) openSegment_Unsafe( segment uint64 ) ( return_report error_report.ErrorReport_struct ){
+	//Variables
+	var file *os.File;
+	var open_error error;
+	//Parametres
+	//Function
+	file, open_error = os.OpenFile( walSegmentPath( write_ahead_log.directory, segment ), os.O_CREATE | os.O_EXCL | os.O_WRONLY | os.O_APPEND, 0644 );
+	if( open_error != nil ){
+		return error_report.New( ERROR_CODE_WRITE_AHEAD_LOG_ERROR, map[string]interface{}{ "message": open_error.Error(), "error": open_error, "segment": segment }, nil );
+	}
+	if( write_ahead_log.file != nil ){
+		write_ahead_log.file.Sync();
+		write_ahead_log.file.Close();
+	}
+	write_ahead_log.file = file;
+	write_ahead_log.segment = segment;
+	write_ahead_log.segment_size = 0;
+	write_ahead_log.segments_slice = append(write_ahead_log.segments_slice, segment);
+	return_report = write_ahead_log.writeRecord_Unsafe( wal_record_header, 0, []byte(write_ahead_log.options.Codec.Name()) );
+	if( return_report.NoError() == true ){
+		///Make the new file's directory entry durable too.
+		return_report = syncDirectory( write_ahead_log.directory );
+	}
+	//Return
+	return return_report;
+}
+
+/**
+* @fn releaseSegments_Unsafe
+* @brief Deletes the oldest segments, up to the first which is current or still has pending events; the caller must hold `mutex`.
+* @struct write_ahead_log *write_ahead_log_struct
+*/
+
+// releaseSegments_Unsafe deletes the oldest segments, up to the first which is current or still has pending events; the caller must hold `mutex`. A segment with nothing pending but an older segment still live is kept, since it may hold the acknowledgements of that segment's events; once the older segment is gone they're simply ignored on recovery.
+func (write_ahead_log *write_ahead_log_struct) releaseSegments_Unsafe(){
+	//Variables
+	var segment uint64;
+	//Parametres
+	//Function
+	for len(write_ahead_log.segments_slice) > 0 {
+		segment = write_ahead_log.segments_slice[0];
+		if( segment == write_ahead_log.segment || write_ahead_log.pending_counts_map[segment] > 0 ){
+			break;
+		}
+		delete( write_ahead_log.pending_counts_map, segment );
+		os.Remove( walSegmentPath( write_ahead_log.directory, segment ) );
+		write_ahead_log.segments_slice = write_ahead_log.segments_slice[1:];
+	}
+	//Return
+}
+
+/**
+* @fn syncPeriodically
+* @brief Syncs the current segment every `Fsync_interval` while there are unsynced records, until the log is closed.
+* @struct write_ahead_log *write_ahead_log_struct
+*/
+
+// syncPeriodically syncs the current segment every `Fsync_interval` while there are unsynced records, until the log is closed; for FSYNC_POLICY_INTERVAL.
+func (write_ahead_log *write_ahead_log_struct) syncPeriodically(){
+	//Variables
+	var ticker *time.Ticker = time.NewTicker( write_ahead_log.options.Fsync_interval );
+	//Parametres
+	//Function
+	defer ticker.Stop();
+	for{
+		select{
+			case <-ticker.C:
+				write_ahead_log.mutex.Lock();
+				if( write_ahead_log.dirty == true && write_ahead_log.file != nil ){
+					write_ahead_log.file.Sync();
+					write_ahead_log.dirty = false;
+				}
+				write_ahead_log.mutex.Unlock();
+			case <-write_ahead_log.stop_channel:
+				return;
+		}
+	}
+	//Return
+}
+
+/**
+* @fn AcknowledgeEvent
+* @brief Marks an event taken from a write-ahead-logged or backend-stored queue as done, so it isn't delivered again.
+* @struct event_dispatcher *EventDispatcher_struct
+* @param event Event_struct [in] An event returned by `ShiftEvent`, `PopEvent`, or `ExtractEventByIndex`.
+* @return ( return_report error_report.ErrorReport_struct ) 
+* @retval 0 Success
+* @retval 1 Not Supported
+* @retval >1 Error
+*/
+
+// AcknowledgeEvent marks an event taken from a write-ahead-logged or backend-stored queue as done, so it isn't recovered after a restart or redelivered once its visibility timeout expires. `ProcessEvent`, and so `ProcessEvents` and the run loop, do this once the event's listeners, asynchronous ones included, have returned, and `RemoveEventByIndex` and the overflow strategies when they discard one; only events otherwise taken from the queue need it. Does nothing without a write-ahead log or queue backend.
+func (event_dispatcher *EventDispatcher_struct) AcknowledgeEvent( event Event_struct ) ( return_report error_report.ErrorReport_struct ){
+	//Variables
+	//Parametres
+	//Function
+	if( event_dispatcher.write_ahead_log != nil && event.acknowledgement_id != 0 ){
+		return_report = event_dispatcher.write_ahead_log.acknowledge( event.acknowledgement_id );
+	} else if( event_dispatcher.queue_backend != nil && event.acknowledgement_id != 0 ){
+		return_report = event_dispatcher.queue_backend.Acknowledge( event.acknowledgement_id );
+	} else{
+		return_report = error_report.New( 0, map[string]interface{}{}, nil );
+	}
+	//Return
+	return return_report;
+}
+
+/**
+* @fn Close
+* @brief Cancels pending retries, then syncs and closes the dispatcher's write-ahead log, if it has one.
+* @struct event_dispatcher *EventDispatcher_struct
+* @return ( return_report error_report.ErrorReport_struct ) 
+* @retval 0 Success
+* @retval 1 Not Supported
+* @retval >1 Error
+*/
+
+// Close cancels retries still waiting out their backoff, then syncs and closes the dispatcher's write-ahead log, if it has one; events still queued are recovered by the next `NewEventDispatcherWithWriteAheadLog` on the same directory. Stop the run loop first.
+func (event_dispatcher *EventDispatcher_struct) Close() ( return_report error_report.ErrorReport_struct ){
+	//Variables
+	//Parametres
+	//Function
+	event_dispatcher.cancelRetries();
+	if( event_dispatcher.write_ahead_log != nil ){
+		return_report = event_dispatcher.write_ahead_log.close();
+	} else{
+		return_report = error_report.New( 0, map[string]interface{}{}, nil );
+	}
+	//Return
+	return return_report;
+}
+
+/**
+* @fn logEvent_Unsafe
+* @brief Writes the event to the write-ahead log, if there is one, before it's queued; the caller must hold `mutex`.
+* @struct event_dispatcher *EventDispatcher_struct
+* @param event *Event_struct [in,out] The event; given the sequence number to acknowledge it by.
+* @param reserve_report error_report.ErrorReport_struct [in] The report from `reserveSpace_Unsafe`, returned on success.
+* @return ( return_report error_report.ErrorReport_struct ) 
+* @retval 0 Success
+* @retval 1 Not Supported
+* @retval >1 Error
+*/
+
+// logEvent_Unsafe writes the event to the write-ahead log, if there is one, before it's queued; the caller must hold `mutex`, so the log's order matches the queue's. On failure the event mustn't be queued.
+func (event_dispatcher *EventDispatcher_struct) logEvent_Unsafe( event *Event_struct, reserve_report error_report.ErrorReport_struct ) ( return_report error_report.ErrorReport_struct ){
+	//Variables
+	var sequence uint64;
+	//Parametres
+	//Function
+	return_report = reserve_report;
+	if( event_dispatcher.write_ahead_log != nil ){
+		sequence, return_report = event_dispatcher.write_ahead_log.append( *event );
+		if( return_report.NoError() == true ){
+			event.acknowledgement_id = sequence;
+			return_report = reserve_report;
+		}
+	}
+	//Return
+	return return_report;
+}
+
+/**
+* @fn acknowledgeEvent
+* @brief `AcknowledgeEvent`, sending any failure to the error sink.
+* @struct event_dispatcher *EventDispatcher_struct
+* @param event Event_struct [in] The event.
+*/
+
+// acknowledgeEvent is `AcknowledgeEvent`, sending any failure to the error sink; the caller may hold `mutex`.
+func (event_dispatcher *EventDispatcher_struct) acknowledgeEvent( event Event_struct ){
+	//Variables
+	var function_return error_report.ErrorReport_struct;
+	//Parametres
+	//Function
+	function_return = event_dispatcher.AcknowledgeEvent( event );
+	if( function_return.IsError() == true ){
+		///May be called with `mutex` held, so the sink is called in its own goroutine.
+		go event_dispatcher.sinkError( function_return );
+	}
+	//Return
+}
+
+/**
+* @fn holdAcknowledgement
+* @brief Returns the acknowledgement for an event about to be dispatched, held once for the dispatch itself, or nil if the event needs no acknowledging.
+* @struct event_dispatcher *EventDispatcher_struct
+* @param event Event_struct [in] The event.
+* @return *acknowledgement_struct
+*/
+
+// holdAcknowledgement returns the acknowledgement for an event about to be dispatched, held once for the dispatch itself, or nil if the event needs no acknowledging; `hold` and `release` do nothing on nil.
+func (event_dispatcher *EventDispatcher_struct) holdAcknowledgement( event Event_struct ) *acknowledgement_struct{
+	//Variables
+	var acknowledgement *acknowledgement_struct;
+	//Parametres
+	//Function
+	if( event.acknowledgement_id != 0 ){
+		acknowledgement = &acknowledgement_struct{ event_dispatcher: event_dispatcher, event: event, holds: 1 };
+	}
+	//Return
+	return acknowledgement;
+}
+
+/**
+* @fn hold
+* @brief Delays the acknowledgement until a matching `release`, for an asynchronous listener call.
+* @struct acknowledgement *acknowledgement_struct
+*/
+
+// hold delays the acknowledgement until a matching `release`, for an asynchronous listener call.
+func (acknowledgement *acknowledgement_struct) hold(){
+	//Variables
+	//Parametres
+	//Function
+	if( acknowledgement != nil ){
+		atomic.AddInt64( &acknowledgement.holds, 1 );
+	}
+	//Return
+}
+
+/**
+* @fn release
+* @brief Releases a hold, acknowledging the event when it was the last.
+* @struct acknowledgement *acknowledgement_struct
+*/
+
+// release releases a hold, acknowledging the event when it was the last.
+func (acknowledgement *acknowledgement_struct) release(){
+	//Variables
+	//Parametres
+	//Function
+	if( acknowledgement != nil && atomic.AddInt64( &acknowledgement.holds, -1 ) == 0 ){
+		acknowledgement.event_dispatcher.acknowledgeEvent( acknowledgement.event );
+	}
+	//Return
+}
+
+//# Global Variables
+var(
+	//## Exported Variables
+	//## Private Variables
+);
+
+//# Exported Functions
+/**
+* @fn NewEventDispatcherWithWriteAheadLog
+* @brief Creates a buffered event dispatcher whose queue is recorded in a write-ahead log in `directory`, recovering any events left pending by a previous process.
+* @param add_times bool [in] As for `NewEventDispatcher`.
+* @param directory string [in] The log's directory, created if need be; only one dispatcher may use it at a time.
+* @param options WriteAheadLogOptions_struct [in] The fsync policy, segment size, and codec.
+* @return ( return_report error_report.ErrorReport_struct ) 
+* @retval 0 Success
+* @retval 1 Not Supported
+* @retval >1 Error
+*/
+
+// NewEventDispatcherWithWriteAheadLog creates a buffered event dispatcher whose queue is recorded in a write-ahead log in `directory`. Every event queued by `PushEvent`, `InsertEventAtIndex`, or `Publish` is logged before it's queued, and acknowledged once its listeners, asynchronous ones included, have returned, so events pending when the process died are queued again here, in the order they were logged, as the "recovered" datum counts; where `InsertEventAtIndex` placed an event isn't logged, so it's recovered behind the events logged before it: delivery is at least once, so listeners should be idempotent. Recovered events keep their envelope and data but not their context. Call `Close` when done.
+func NewEventDispatcherWithWriteAheadLog( add_times bool, directory string, options WriteAheadLogOptions_struct ) ( return_report error_report.ErrorReport_struct ){
+	//Variables
+	var event_dispatcher *EventDispatcher_struct;
+	var write_ahead_log *write_ahead_log_struct;
+	var recovered_events []Event_struct;
+	var i int;
+	//Parametres
+	if( options.Fsync_policy == 0 ){
+		options.Fsync_policy = FSYNC_POLICY_ALWAYS;
+	}
+	if( options.Fsync_interval <= 0 ){
+		options.Fsync_interval = wal_default_fsync_interval;
+	}
+	if( options.Segment_size <= 0 ){
+		options.Segment_size = wal_default_segment_size;
+	}
+	if( options.Codec == nil ){
+		options.Codec = JSONCodec;
+	}
+	if( options.Fsync_policy > FSYNC_POLICY_NEVER ){
+		return error_report.New( ERROR_CODE_WRITE_AHEAD_LOG_ERROR, map[string]interface{}{ "message": "Invalid fsync policy.", "fsync_policy": options.Fsync_policy }, nil );
+	}
+	//Function
+	write_ahead_log, recovered_events, return_report = openWriteAheadLog( directory, options );
+	if( return_report.IsError() == true ){
+		return return_report;
+	}
+	event_dispatcher = newEventDispatcher( add_times, true );
+	event_dispatcher.write_ahead_log = write_ahead_log;
+	for i = 0; i < len(recovered_events); i++ {
+		event_dispatcher.getEventsQueue_Unsafe().PushBack( recovered_events[i] );
+	}
+	return_report = error_report.New( 0, map[string]interface{}{ "event_dispatcher": event_dispatcher, "recovered": len(recovered_events) }, nil );
+	//Return
+	return return_report;
+}
+
+//# Private Functions
+/**
+* @fn openWriteAheadLog
+* @brief Recovers the pending events from the segments in `directory` and starts a new segment after them.
+* @param directory string [in] The log's directory.
+* @param options WriteAheadLogOptions_struct [in] With defaults applied.
+* @return ( write_ahead_log *write_ahead_log_struct, recovered_events []Event_struct, return_report error_report.ErrorReport_struct )
+*/
+
+// openWriteAheadLog recovers the pending events, in the order they were enqueued, from the segments in `directory`, deletes the segments with nothing pending, and starts a new segment after them. A torn record at the end of a segment, left by a crash mid-write, is truncated away.
+func openWriteAheadLog( directory string, options WriteAheadLogOptions_struct ) ( write_ahead_log *write_ahead_log_struct, recovered_events []Event_struct, return_report error_report.ErrorReport_struct ){
+	//Variables
+	var segments []uint64;
+	var segment uint64;
+	var records []wal_record_struct;
+	var record wal_record_struct;
+	var codec Codec_interface;
+	var pending_map map[uint64]Event_struct = map[uint64]Event_struct{};
+	var sequences []uint64;
+	var sequence uint64;
+	var event Event_struct;
+	var function_return error_report.ErrorReport_struct;
+	var os_error error;
+	//Parametres
+	//Function
+	os_error = os.MkdirAll( directory, 0755 );
+	if( os_error == nil ){
+		segments, os_error = listWALSegments( directory );
+	}
+	if( os_error != nil ){
+		return nil, nil, error_report.New( ERROR_CODE_WRITE_AHEAD_LOG_ERROR, map[string]interface{}{ "message": os_error.Error(), "error": os_error, "directory": directory }, nil );
+	}
+	write_ahead_log = &write_ahead_log_struct{ directory: directory, options: options, next_sequence: 1, segments_map: map[uint64]uint64{}, pending_counts_map: map[uint64]int{}, segments_slice: append([]uint64(nil), segments...), stop_channel: make(chan struct{}) };
+	for _, segment = range segments {
+		records, os_error = readWALSegment( walSegmentPath( directory, segment ) );
+		if( os_error != nil ){
+			return nil, nil, error_report.New( ERROR_CODE_WRITE_AHEAD_LOG_ERROR, map[string]interface{}{ "message": os_error.Error(), "error": os_error, "segment": segment }, nil );
+		}
+		codec = nil;
+		for _, record = range records {
+			switch record.record_type {
+				case wal_record_header:
+					function_return = GetCodec( string(record.payload) );
+					if( function_return.IsError() == true ){
+						return nil, nil, error_report.New( ERROR_CODE_WRITE_AHEAD_LOG_ERROR, map[string]interface{}{ "message": "The segment's codec isn't registered.", "segment": segment }, &function_return );
+					}
+					codec = function_return.Data["codec"].(Codec_interface);
+				case wal_record_enqueue:
+					if( codec == nil ){
+						return nil, nil, error_report.New( ERROR_CODE_WRITE_AHEAD_LOG_ERROR, map[string]interface{}{ "message": "The segment has no header.", "segment": segment }, nil );
+					}
+					function_return = codec.Decode( record.payload );
+					if( function_return.IsError() == true ){
+						return nil, nil, error_report.New( ERROR_CODE_WRITE_AHEAD_LOG_ERROR, map[string]interface{}{ "message": "Couldn't decode a logged event.", "segment": segment, "sequence": record.sequence }, &function_return );
+					}
+					event = function_return.Data["event"].(Event_struct);
+					event.acknowledgement_id = record.sequence;
+					pending_map[record.sequence] = event;
+					write_ahead_log.segments_map[record.sequence] = segment;
+					write_ahead_log.pending_counts_map[segment]++;
+				case wal_record_acknowledge:
+					if _, ok := pending_map[record.sequence]; ok {
+						delete( pending_map, record.sequence );
+						write_ahead_log.pending_counts_map[write_ahead_log.segments_map[record.sequence]]--;
+						delete( write_ahead_log.segments_map, record.sequence );
+					}
+			}
+			if( record.sequence >= write_ahead_log.next_sequence ){
+				write_ahead_log.next_sequence = record.sequence + 1;
+			}
+		}
+	}
+	for sequence = range pending_map {
+		sequences = append(sequences, sequence);
+	}
+	sort.Slice( sequences, func( i int, j int ) bool{ return sequences[i] < sequences[j]; } );
+	for _, sequence = range sequences {
+		recovered_events = append(recovered_events, pending_map[sequence]);
+	}
+	if( len(segments) > 0 ){
+		segment = segments[len(segments) - 1] + 1;
+	} else{
+		segment = 1;
+	}
+	write_ahead_log.mutex.Lock();
+	return_report = write_ahead_log.openSegment_Unsafe( segment );
+	if( return_report.NoError() == true ){
+		write_ahead_log.releaseSegments_Unsafe();
+	}
+	write_ahead_log.mutex.Unlock();
+	if( return_report.IsError() == true ){
+		return nil, nil, return_report;
+	}
+	if( options.Fsync_policy == FSYNC_POLICY_INTERVAL ){
+		go write_ahead_log.syncPeriodically();
+	}
+	//Return
+	return write_ahead_log, recovered_events, return_report;
+}
+
+/**
+* @fn readWALSegment
+* @brief Reads every intact record from a segment, truncating a torn record at its end.
+* @param path string [in] The segment's path.
+* @return ( records []wal_record_struct, read_error error )
+*/
+
+// readWALSegment reads every intact record from a segment. A short or corrupt record ends the segment: it can only have been the last one being written when the process died, so the file is truncated to the records before it.
+func readWALSegment( path string ) ( records []wal_record_struct, read_error error ){
+	//Variables
+	var contents []byte;
+	var offset int;
+	var length int;
+	var record wal_record_struct;
+	//Parametres
+	//Function
+	contents, read_error = os.ReadFile( path );
+	if( read_error != nil ){
+		return nil, read_error;
+	}
+	for offset + wal_record_prefix_length <= len(contents) {
+		length = int(binary.BigEndian.Uint32( contents[offset:offset + 4] ));
+		if( offset + wal_record_prefix_length + length > len(contents) || crc32.ChecksumIEEE( contents[offset + 8:offset + wal_record_prefix_length + length] ) != binary.BigEndian.Uint32( contents[offset + 4:offset + 8] ) ){
+			break;
+		}
+		record = wal_record_struct{ record_type: contents[offset + 8], sequence: binary.BigEndian.Uint64( contents[offset + 9:offset + 17] ), payload: contents[offset + wal_record_prefix_length:offset + wal_record_prefix_length + length] };
+		records = append(records, record);
+		offset += wal_record_prefix_length + length;
+	}
+	if( offset < len(contents) ){
+		read_error = os.Truncate( path, int64(offset) );
+	}
+	//Return
+	return records, read_error;
+}
+
+/**
+* @fn listWALSegments
+* @brief Returns the numbers of the segments in the directory, in order.
+* @param directory string [in] The log's directory.
+* @return ( segments []uint64, list_error error )
+*/
+
+// listWALSegments returns the numbers of the segments in the directory, in order.
+func listWALSegments( directory string ) ( segments []uint64, list_error error ){
+	//Variables
+	var entries []os.DirEntry;
+	var entry os.DirEntry;
+	var segment uint64;
+	var parse_error error;
+	//Parametres
+	//Function
+	entries, list_error = os.ReadDir( directory );
+	for _, entry = range entries {
+		if( entry.IsDir() == false && strings.HasSuffix( entry.Name(), wal_segment_extension ) == true ){
+			segment, parse_error = strconv.ParseUint( strings.TrimSuffix( entry.Name(), wal_segment_extension ), 10, 64 );
+			if( parse_error == nil ){
+				segments = append(segments, segment);
+			}
+		}
+	}
+	sort.Slice( segments, func( i int, j int ) bool{ return segments[i] < segments[j]; } );
+	//Return
+	return segments, list_error;
+}
+
+/**
+* @fn walSegmentPath
+* @brief Returns the path of the numbered segment.
+* @param directory string [in] The log's directory.
+* @param segment uint64 [in] The segment's number.
+* @return string
+*/
+
+// walSegmentPath returns the path of the numbered segment; zero-padded so the files sort by name.
+func walSegmentPath( directory string, segment uint64 ) string{
+	//Variables
+	//Parametres
+	//Function
+	//Return
+	return filepath.Join( directory, fmt.Sprintf( "%020d%s", segment, wal_segment_extension ) );
+}
+
+/**
+* @fn syncDirectory
+* @brief Syncs the directory so files created in it survive a crash.
+* @param directory string [in] The directory.
+* @return ( return_report error_report.ErrorReport_struct ) 
+* @retval 0 Success
+* @retval 1 Not Supported
+* @retval >1 Error
+*/
+
+// syncDirectory syncs the directory so files created in it survive a crash; platforms which can't sync directories are ignored.
+func syncDirectory( directory string ) ( return_report error_report.ErrorReport_struct ){
+	//Variables
+	var file *os.File;
+	var sync_error error;
+	//Parametres
+	//Function
+	file, sync_error = os.Open( directory );
+	if( sync_error == nil ){
+		file.Sync();
+		sync_error = file.Close();
+	}
+	if( sync_error == nil || sync_error == io.EOF ){
+		return_report = error_report.New( 0, map[string]interface{}{}, nil );
+	} else{
+		return_report = error_report.New( ERROR_CODE_WRITE_AHEAD_LOG_ERROR, map[string]interface{}{ "message": sync_error.Error(), "error": sync_error, "directory": directory }, nil );
+	}
+	//Return
+	return return_report;
+}
@@ -0,0 +1,261 @@
+/**
+* @file wal_test.go
+* @brief Contains test functions for `wal.go`.
+* @author Anadian
+* @copyright 	Copyright 2019 Canosw
+	Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+software and associated documentation files (the "Software"), to deal in the Software 
+without restriction, including without limitation the rights to use, copy, modify, 
+merge, publish, distribute, sublicense, and/or sell copies of the Software, and to 
+permit persons to whom the Software is furnished to do so, subject to the following 
+conditions:
+	The above copyright notice and this permission notice shall be included in all copies 
+or substantial portions of the Software.
+	THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A 
+PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT 
+HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF 
+CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE 
+OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+// wal_test contains test functions for `wal.go`.
+package event_dispatcher;
+
+//# Dependencies
+import(
+	//## Internal
+	//## Standard
+	"testing"
+	"log"
+	"fmt"
+	"os"
+	"time"
+	//## External
+	error_report "github.com/Anadian/error_report/source"
+	matchkey "github.com/Anadian/matchkey/source"
+);
+
+//# Exported Functions
+/**
+* @fn TestWriteAheadLog
+* @brief Tests recovering unacknowledged events, torn records, and deleting fully acknowledged segments.
+* @param t *testing.T [in] Go stdlib testing object.
+*/
+
+// TestWriteAheadLog tests recovering unacknowledged events, torn records, and deleting fully acknowledged segments.
+func TestWriteAheadLog( t *testing.T ){
+	//Variables
+	var directory string = t.TempDir();
+	var options WriteAheadLogOptions_struct = WriteAheadLogOptions_struct{ Fsync_policy: FSYNC_POLICY_NEVER, Segment_size: 256 };
+	var event_dispatcher *EventDispatcher_struct;
+	var function_return error_report.ErrorReport_struct;
+	var key matchkey.MatchKey_struct;
+	var names []string;
+	var segments []uint64;
+	var file *os.File;
+	var i int;
+	//Parametres
+	//Function
+	///First process: queue five events, process two, remove one.
+	function_return = NewEventDispatcherWithWriteAheadLog( false, directory, options );
+	if( function_return.IsError() == true ){
+		t.Fatalf("Failure: NewEventDispatcherWithWriteAheadLog returned: %v\n", function_return);
+	}
+	event_dispatcher = function_return.Data["event_dispatcher"].(*EventDispatcher_struct);
+	for i = 0; i < 5; i++ {
+		event_dispatcher.PushEvent( NewEvent( "wal:test", map[string]interface{}{ "index": i } ).Data["event"].(Event_struct).WithCorrelationID( "billing-run" ) );
+	}
+	event_dispatcher.ProcessEvent( event_dispatcher.ShiftEvent().Data["event"].(Event_struct) );
+	event_dispatcher.ProcessEvent( event_dispatcher.ShiftEvent().Data["event"].(Event_struct) );
+	event_dispatcher.RemoveEventByIndex( 1 );
+	segments, _ = listWALSegments( directory );
+	if( len(segments) > 1 ){
+		log.Printf("Success: The log rotated into %d segments.\n", len(segments));
+	} else{
+		t.Fail();
+		log.Printf("Failure: The log didn't rotate: %v\n", segments);
+	}
+	///Simulate a crash part way through writing a record.
+	file, _ = os.OpenFile( walSegmentPath( directory, segments[len(segments) - 1] ), os.O_WRONLY | os.O_APPEND, 0644 );
+	file.Write( []byte{ 0, 0, 1, 0, 9, 9 } );
+	file.Close();
+	///Second process: events 2 and 4 are recovered in order.
+	function_return = NewEventDispatcherWithWriteAheadLog( false, directory, options );
+	if( function_return.IsError() == true ){
+		t.Fatalf("Failure: NewEventDispatcherWithWriteAheadLog returned: %v\n", function_return);
+	}
+	event_dispatcher = function_return.Data["event_dispatcher"].(*EventDispatcher_struct);
+	key, _ = matchkey.New( matchkey.MATCHKEY_TYPE_STRING, "wal:test" );
+	event_dispatcher.AddEventListener( NewEventListener( key, false, func( event Event_struct, args ...interface{} ){
+		value, _ := event.Get( "index" );
+		names = append(names, fmt.Sprintf( "%s:%v", event.CorrelationID(), value ));
+	} ).Data["event_listener"].(EventListener_struct) );
+	if( function_return.Data["recovered"] == 2 ){
+		log.Printf("Success: Recovered the two unacknowledged events past a torn record.\n");
+	} else{
+		t.Fail();
+		log.Printf("Failure: Recovered %v events.\n", function_return.Data["recovered"]);
+	}
+	event_dispatcher.ProcessEvents();
+	if( len(names) == 2 && names[0] == "billing-run:2" && names[1] == "billing-run:4" ){
+		log.Printf("Success: Recovered events kept their order, data, and envelope.\n");
+	} else{
+		t.Fail();
+		log.Printf("Failure: Recovered events were: %v\n", names);
+	}
+	event_dispatcher.Close();
+	if( event_dispatcher.PushEvent( NewEvent( "wal:test", map[string]interface{}{} ).Data["event"].(Event_struct) ).CodeEqual( ERROR_CODE_WRITE_AHEAD_LOG_ERROR ) == true && event_dispatcher.GetEventByIndex( 0 ).IsError() == true ){
+		log.Printf("Success: Events aren't queued once the log can't record them.\n");
+	} else{
+		t.Fail();
+		log.Printf("Failure: An event was queued after Close.\n");
+	}
+	///Third process: nothing pending, and only the new segment remains.
+	function_return = NewEventDispatcherWithWriteAheadLog( false, directory, options );
+	segments, _ = listWALSegments( directory );
+	if( function_return.Data["recovered"] == 0 && len(segments) == 1 ){
+		log.Printf("Success: Fully acknowledged segments were deleted.\n");
+	} else{
+		t.Fail();
+		log.Printf("Failure: Recovered %v events; segments: %v\n", function_return.Data["recovered"], segments);
+	}
+	event_dispatcher = function_return.Data["event_dispatcher"].(*EventDispatcher_struct);
+	event_dispatcher.SetQueueCapacity( 1, OVERFLOW_STRATEGY_DROP_OLDEST );
+	event_dispatcher.PushEvent( NewEvent( "wal:kept", map[string]interface{}{} ).Data["event"].(Event_struct) );
+	event_dispatcher.Close();
+	function_return = event_dispatcher.PushEvent( NewEvent( "wal:test", map[string]interface{}{} ).Data["event"].(Event_struct) );
+	if( function_return.CodeEqual( ERROR_CODE_WRITE_AHEAD_LOG_ERROR ) == true && event_dispatcher.GetEventByIndex( 0 ).Data["event"].(Event_struct).Name() == "wal:kept" && event_dispatcher.GetDroppedEventCounts().Data["dropped_event_counts"].(map[uint8]uint64)[OVERFLOW_STRATEGY_DROP_OLDEST] == 0 ){
+		log.Printf("Success: OVERFLOW_STRATEGY_DROP_OLDEST kept the oldest event when the new one couldn't be logged.\n");
+	} else{
+		t.Fail();
+		log.Printf("Failure: PushEvent into a full, closed log returned: %v\n", function_return);
+	}
+	//Return
+}
+
+/**
+* @fn TestWriteAheadLogSegments
+* @brief Tests that a segment holding only acknowledgements isn't deleted while an earlier segment is live.
+* @param t *testing.T [in] Go stdlib testing object.
+*/
+
+// TestWriteAheadLogSegments tests that a segment holding only acknowledgements isn't deleted while an earlier segment is live, which would have the acknowledged events recovered again.
+func TestWriteAheadLogSegments( t *testing.T ){
+	//Variables
+	var directory string = t.TempDir();
+	var options WriteAheadLogOptions_struct = WriteAheadLogOptions_struct{ Fsync_policy: FSYNC_POLICY_NEVER };
+	var event_dispatcher *EventDispatcher_struct;
+	var function_return error_report.ErrorReport_struct;
+	var events []Event_struct;
+	var event_record_size int64;
+	var header_record_size int64;
+	var segments []uint64;
+	var i int;
+	//Parametres
+	//Function
+	for i = 0; i < 4; i++ {
+		events = append(events, NewEvent( "wal:segments", map[string]interface{}{ "index": i } ).Data["event"].(Event_struct));
+	}
+	///Segments fit the header and two events, so: 1 = [A, B]; 2 = [ack A, C, D]; 3 = [ack C, ack D].
+	event_record_size = int64(wal_record_prefix_length + len(JSONCodec.Encode( events[0] ).Data["encoded"].([]byte)));
+	header_record_size = int64(wal_record_prefix_length + len(JSONCodec.Name()));
+	options.Segment_size = header_record_size + event_record_size * 3 / 2;
+	function_return = NewEventDispatcherWithWriteAheadLog( false, directory, options );
+	if( function_return.IsError() == true ){
+		t.Fatalf("Failure: NewEventDispatcherWithWriteAheadLog returned: %v\n", function_return);
+	}
+	event_dispatcher = function_return.Data["event_dispatcher"].(*EventDispatcher_struct);
+	event_dispatcher.PushEvent( events[0] );
+	event_dispatcher.PushEvent( events[1] );
+	event_dispatcher.AcknowledgeEvent( event_dispatcher.ShiftEvent().Data["event"].(Event_struct) );
+	event_dispatcher.PushEvent( events[2] );
+	event_dispatcher.PushEvent( events[3] );
+	event_dispatcher.AcknowledgeEvent( event_dispatcher.ExtractEventByIndex( 1 ).Data["event"].(Event_struct) );
+	event_dispatcher.AcknowledgeEvent( event_dispatcher.ExtractEventByIndex( 1 ).Data["event"].(Event_struct) );
+	segments, _ = listWALSegments( directory );
+	event_dispatcher.Close();
+	if( len(segments) == 3 ){
+		log.Printf("Success: The acknowledgement-only segment was kept while the first segment is live: %v\n", segments);
+	} else{
+		t.Fail();
+		log.Printf("Failure: Unexpected segments: %v\n", segments);
+	}
+	///Only B is pending after a restart.
+	function_return = NewEventDispatcherWithWriteAheadLog( false, directory, options );
+	if( function_return.IsError() == true ){
+		t.Fatalf("Failure: NewEventDispatcherWithWriteAheadLog returned: %v\n", function_return);
+	}
+	event_dispatcher = function_return.Data["event_dispatcher"].(*EventDispatcher_struct);
+	if( function_return.Data["recovered"] == 1 && event_dispatcher.GetEventByIndex( 0 ).Data["event"].(Event_struct).ID() == events[1].ID() ){
+		log.Printf("Success: Only the unacknowledged event was recovered.\n");
+	} else{
+		t.Fail();
+		log.Printf("Failure: Recovered %v events.\n", function_return.Data["recovered"]);
+	}
+	///Acknowledging B frees every earlier segment.
+	event_dispatcher.AcknowledgeEvent( event_dispatcher.ShiftEvent().Data["event"].(Event_struct) );
+	segments, _ = listWALSegments( directory );
+	event_dispatcher.Close();
+	if( len(segments) == 1 ){
+		log.Printf("Success: The segments were deleted in order once nothing was pending.\n");
+	} else{
+		t.Fail();
+		log.Printf("Failure: Unexpected segments: %v\n", segments);
+	}
+	//Return
+}
+
+/**
+* @fn TestWriteAheadLogAsyncAcknowledgement
+* @brief Tests that an event isn't acknowledged until its asynchronous listeners have returned.
+* @param t *testing.T [in] Go stdlib testing object.
+*/
+
+// TestWriteAheadLogAsyncAcknowledgement tests that an event isn't acknowledged until its asynchronous listeners have returned, so one still running at a crash is recovered.
+func TestWriteAheadLogAsyncAcknowledgement( t *testing.T ){
+	//Variables
+	var directory string = t.TempDir();
+	var options WriteAheadLogOptions_struct = WriteAheadLogOptions_struct{ Fsync_policy: FSYNC_POLICY_NEVER };
+	var event_dispatcher *EventDispatcher_struct;
+	var function_return error_report.ErrorReport_struct;
+	var key matchkey.MatchKey_struct;
+	var release_channel chan struct{} = make(chan struct{});
+	//Parametres
+	//Function
+	key, _ = matchkey.New( matchkey.MATCHKEY_TYPE_STRING, "wal:async" );
+	event_dispatcher = NewEventDispatcherWithWriteAheadLog( false, directory, options ).Data["event_dispatcher"].(*EventDispatcher_struct);
+	event_dispatcher.AddEventListener( NewEventListener( key, true, func( event Event_struct, args ...interface{} ){
+		<-release_channel;
+	} ).Data["event_listener"].(EventListener_struct) );
+	event_dispatcher.PushEvent( NewEvent( "wal:async", map[string]interface{}{} ).Data["event"].(Event_struct) );
+	event_dispatcher.ProcessEvents();
+	///Simulate a crash while the listener is still running.
+	event_dispatcher.Close();
+	close(release_channel);
+	event_dispatcher.Wait();
+	function_return = NewEventDispatcherWithWriteAheadLog( false, directory, options );
+	if( function_return.Data["recovered"] == 1 ){
+		log.Printf("Success: The event was recovered since its asynchronous listener hadn't returned.\n");
+	} else{
+		t.Fail();
+		log.Printf("Failure: Recovered %v events.\n", function_return.Data["recovered"]);
+	}
+	///Once the listener returns, the event is acknowledged.
+	event_dispatcher = function_return.Data["event_dispatcher"].(*EventDispatcher_struct);
+	event_dispatcher.AddEventListener( NewEventListener( key, true, func( event Event_struct, args ...interface{} ){
+		time.Sleep( 10 * time.Millisecond );
+	} ).Data["event_listener"].(EventListener_struct) );
+	event_dispatcher.ProcessEvents();
+	event_dispatcher.Wait();
+	event_dispatcher.Close();
+	function_return = NewEventDispatcherWithWriteAheadLog( false, directory, options );
+	if( function_return.Data["recovered"] == 0 ){
+		log.Printf("Success: The event was acknowledged once its asynchronous listener returned.\n");
+	} else{
+		t.Fail();
+		log.Printf("Failure: Recovered %v events.\n", function_return.Data["recovered"]);
+	}
+	function_return.Data["event_dispatcher"].(*EventDispatcher_struct).Close();
+	//Return
+}
@@ -0,0 +1,347 @@
+/**
+* @file worker_pool.go
+* @brief A bounded pool of goroutines running asynchronous listeners, and waiting for in-flight handlers.
+* @author Anadian
+* @copyright 	Copyright 2019 Canosw
+	Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+software and associated documentation files (the "Software"), to deal in the Software 
+without restriction, including without limitation the rights to use, copy, modify, 
+merge, publish, distribute, sublicense, and/or sell copies of the Software, and to 
+permit persons to whom the Software is furnished to do so, subject to the following 
+conditions:
+	The above copyright notice and this permission notice shall be included in all copies 
+or substantial portions of the Software.
+	THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A 
+PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT 
+HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF 
+CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE 
+OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+package event_dispatcher;
+
+//# Dependencies
+import(
+	//## Internal
+	//## Standard
+	"context"
+	"hash/fnv"
+	"sync"
+	//## External
+	error_report "github.com/Anadian/error_report/source"
+);
+
+//# Constants
+const(
+	//## Exported Constants
+	//### Errors
+	ERROR_CODE_DRAIN_INCOMPLETE int64 = 31;
+	//## Private Constants
+);
+
+//# Types
+//## Structs
+// worker_pool_struct is a fixed number of goroutines running submitted tasks; `submit` blocks while every worker is busy and the task channel is full. Tasks without a partition key go to whichever worker is free; tasks with one always go to the same worker's own channel so they run in the order submitted.
+type worker_pool_struct struct{
+	size uint
+	task_channel chan func()
+	partition_channels_slice []chan func()
+	//Guards `stopped` so that the channels are only closed once every `submit` which got past the check has sent its task.
+	mutex sync.Mutex
+	stopped bool
+	senders sync.WaitGroup
+}
+//### Methods
+/**
+* @fn submit
+* @brief Hands the task to a worker, blocking until one can take it.
+* @struct worker_pool *worker_pool_struct
+* @param task func() [in] The task to run.
+* @param partition_key string [in] If not empty, the task runs on the worker this key hashes to, after every task submitted earlier with the same key.
+* @return bool False if the pool has been stopped, in which case the task wasn't run.
+*/
+
+// submit hands the task to a worker, blocking until one can take it; returns false if the pool has been stopped, in which case the task wasn't run.
+func (worker_pool *worker_pool_struct) submit( task func(), partition_key string ) bool{
+	//Variables
+	var task_channel chan func() = worker_pool.task_channel;
+	var hash = fnv.New32a();
+	//Parametres
+	if( partition_key != "" ){
+		hash.Write( []byte(partition_key) );
+		task_channel = worker_pool.partition_channels_slice[hash.Sum32() % uint32(worker_pool.size)];
+	}
+	//Function
+	worker_pool.mutex.Lock();
+	if( worker_pool.stopped == true ){
+		worker_pool.mutex.Unlock();
+		return false;
+	}
+	worker_pool.senders.Add( 1 );
+	worker_pool.mutex.Unlock();
+	task_channel <- task;
+	worker_pool.senders.Done();
+	//Return
+	return true;
+}
+
+/**
+* @fn stop
+* @brief Refuses any further tasks; the workers exit once they've run every task already submitted. Doesn't block.
+* @struct worker_pool *worker_pool_struct
+*/
+
+// stop refuses any further tasks; the workers exit once they've run every task already submitted. Doesn't block.
+func (worker_pool *worker_pool_struct) stop(){
+	//Variables
+	//Parametres
+	//Function
+	worker_pool.mutex.Lock();
+	worker_pool.stopped = true;
+	worker_pool.mutex.Unlock();
+	go func(){
+		worker_pool.senders.Wait();
+		var i int;
+		close(worker_pool.task_channel);
+		for i = 0; i < len(worker_pool.partition_channels_slice); i++ {
+			close(worker_pool.partition_channels_slice[i]);
+		}
+	}();
+	//Return
+}
+
+/**
+* @fn work
+* @brief The body of each worker: runs tasks from its own channel and the shared one until both are closed.
+* @struct worker_pool *worker_pool_struct
+* @param partition_channel chan func() [in] This worker's own channel.
+*/
+
+// work is the body of each worker: runs tasks from its own channel and the shared one until both are closed.
+func (worker_pool *worker_pool_struct) work( partition_channel chan func() ){
+	//Variables
+	var task_channel chan func() = worker_pool.task_channel;
+	var task func();
+	var open bool;
+	//Parametres
+	//Function
+	for task_channel != nil || partition_channel != nil {
+		select{
+			case task, open = <-partition_channel:
+				if( open == false ){
+					partition_channel = nil;
+				} else{
+					task();
+				}
+			case task, open = <-task_channel:
+				if( open == false ){
+					task_channel = nil;
+				} else{
+					task();
+				}
+		}
+	}
+	//Return
+}
+
+/**
+* @fn SetWorkerPoolSize
+* @brief Runs asynchronous listeners on a pool of `size` goroutines instead of a new goroutine per call.
+* @struct event_dispatcher *EventDispatcher_struct
+* @param size uint [in] The number of workers; 0 goes back to a new goroutine per call, the default. Once every worker is busy, dispatching a matching event blocks until one is free, so a listener running on the pool shouldn't synchronously dispatch events to asynchronous listeners of a full pool.
+* @return ( return_report error_report.ErrorReport_struct ) 
+* @retval 0 Success
+* @retval 1 Not Supported
+* @retval >1 Error
+*/
+
+// SetWorkerPoolSize runs asynchronous listeners on a pool of `size` goroutines instead of a new goroutine per call. 0 goes back to a new goroutine per call, the default. Once every worker is busy, dispatching a matching event blocks until one is free. Replacing a pool lets the old workers finish what was already submitted to them.
+func (event_dispatcher *EventDispatcher_struct) SetWorkerPoolSize( size uint ) ( return_report error_report.ErrorReport_struct ){
+	//Variables
+	var old_worker_pool *worker_pool_struct;
+	var new_worker_pool *worker_pool_struct;
+	var i uint;
+	//Parametres
+	//Function
+	if( size > 0 ){
+		new_worker_pool = &worker_pool_struct{ size: size, task_channel: make(chan func(), size), partition_channels_slice: make([]chan func(), size) };
+		for i = 0; i < size; i++ {
+			new_worker_pool.partition_channels_slice[i] = make(chan func(), size);
+			go new_worker_pool.work( new_worker_pool.partition_channels_slice[i] );
+		}
+	}
+	event_dispatcher.mutex.Lock();
+	old_worker_pool = event_dispatcher.worker_pool;
+	event_dispatcher.worker_pool = new_worker_pool;
+	event_dispatcher.mutex.Unlock();
+	if( old_worker_pool != nil ){
+		old_worker_pool.stop();
+	}
+	return_report = error_report.New( 0, map[string]interface{}{ "worker_pool_size": size }, nil );
+	//Return
+	return return_report;
+}
+
+/**
+* @fn Wait
+* @brief Blocks until every asynchronous listener call in flight has returned.
+* @struct event_dispatcher *EventDispatcher_struct
+* @return ( return_report error_report.ErrorReport_struct ) 
+* @retval 0 Success
+* @retval 1 Not Supported
+* @retval >1 Error
+*/
+
+// Wait blocks until every asynchronous listener call in flight has returned.
+func (event_dispatcher *EventDispatcher_struct) Wait() ( return_report error_report.ErrorReport_struct ){
+	//Variables
+	//Parametres
+	//Function
+	return_report = event_dispatcher.Drain( context.Background() );
+	//Return
+	return return_report;
+}
+
+/**
+* @fn Drain
+* @brief Blocks until every asynchronous listener call in flight has returned or the context is done.
+* @struct event_dispatcher *EventDispatcher_struct
+* @param ctx context.Context [in] Bounds the wait.
+* @return ( return_report error_report.ErrorReport_struct ) 
+* @retval 0 Success
+* @retval 1 Not Supported
+* @retval >1 Error
+*/
+
+// Drain blocks until every asynchronous listener call in flight has returned or the context is done, in which case it returns ERROR_CODE_DRAIN_INCOMPLETE with the number still in flight. Calls started while draining are waited on too.
+func (event_dispatcher *EventDispatcher_struct) Drain( ctx context.Context ) ( return_report error_report.ErrorReport_struct ){
+	//Variables
+	var idle_channel chan struct{};
+	var in_flight uint64;
+	//Parametres
+	//Function
+	event_dispatcher.in_flight_mutex.Lock();
+	if( event_dispatcher.in_flight > 0 ){
+		if( event_dispatcher.idle_channel == nil ){
+			event_dispatcher.idle_channel = make(chan struct{});
+		}
+		idle_channel = event_dispatcher.idle_channel;
+	}
+	event_dispatcher.in_flight_mutex.Unlock();
+	if( idle_channel != nil ){
+		select{
+			case <-idle_channel:
+			case <-ctx.Done():
+				event_dispatcher.in_flight_mutex.Lock();
+				in_flight = event_dispatcher.in_flight;
+				event_dispatcher.in_flight_mutex.Unlock();
+				return_report = error_report.New( ERROR_CODE_DRAIN_INCOMPLETE, map[string]interface{}{ "message": "The context was done before every asynchronous listener returned.", "context_error": ctx.Err(), "in_flight": in_flight }, nil );
+		}
+	}
+	if( return_report.Code == 0 ){
+		return_report = error_report.New( 0, map[string]interface{}{ "in_flight": uint64(0) }, nil );
+	}
+	//Return
+	return return_report;
+}
+
+/**
+* @fn SetPartitionKeyFunction
+* @brief Sets the function giving each event its partition key, so asynchronous listeners on the worker pool get events sharing a key one at a time, in the order they were dispatched.
+* @struct event_dispatcher *EventDispatcher_struct
+* @param partition_key_function func( event Event_struct ) string [in] Typically returns an entity ID from the event's data; an empty string leaves the event unpartitioned. May be called once per matching asynchronous listener. `nil` disables partitioning.
+* @return ( return_report error_report.ErrorReport_struct ) 
+* @retval 0 Success
+* @retval 1 Not Supported
+* @retval >1 Error
+*/
+
+// SetPartitionKeyFunction sets the function giving each event its partition key. Events sharing a key are handed to asynchronous listeners one at a time, by the same worker, in the order they were dispatched, while different keys run in parallel. This only holds with a worker pool set by `SetWorkerPoolSize`; without one, every call gets its own goroutine and no order is kept. Retried events are requeued after their backoff so may be overtaken.
+func (event_dispatcher *EventDispatcher_struct) SetPartitionKeyFunction( partition_key_function func( event Event_struct ) string ) ( return_report error_report.ErrorReport_struct ){
+	//Variables
+	//Parametres
+	//Function
+	event_dispatcher.mutex.Lock();
+	event_dispatcher.partition_key_function = partition_key_function;
+	event_dispatcher.mutex.Unlock();
+	return_report = error_report.New( 0, map[string]interface{}{}, nil );
+	//Return
+	return return_report;
+}
+
+/**
+* @fn PartitionKey
+* @brief Returns the partition key given to the event by the dispatcher's partition key function, if any.
+* @struct event Event_struct
+* @return string
+*/
+
+// PartitionKey returns the partition key given to the event by the dispatcher's partition key function, if any; only set on the copies passed to asynchronous listeners.
+func (event Event_struct) PartitionKey() string{
+	//Variables
+	//Parametres
+	//Function
+	//Return
+	return event.partition_key;
+}
+
+/**
+* @fn submitAsyncEventListener
+* @brief Calls the asynchronous listener on the worker pool, or in a new goroutine if there isn't one, counting the call as in flight until it returns.
+* @struct event_dispatcher *EventDispatcher_struct
+* @param event_listener EventListener_struct [in] The listener to call.
+* @param event Event_struct [in] The event to pass to it.
+* @param dispatch_snapshot dispatch_snapshot_struct [in] The snapshot taken by the dispatch submitting the call, holding the worker pool and partition key function.
+* @param acknowledgement *acknowledgement_struct [in] Released once the call returns; may be nil.
+*/
+
+// submitAsyncEventListener calls the asynchronous listener on the worker pool, or in a new goroutine if there isn't one, counting the call as in flight until it returns.
+func (event_dispatcher *EventDispatcher_struct) submitAsyncEventListener( event_listener EventListener_struct, event Event_struct, dispatch_snapshot dispatch_snapshot_struct, acknowledgement *acknowledgement_struct ){
+	//Variables
+	var worker_pool *worker_pool_struct = dispatch_snapshot.worker_pool;
+	var task func();
+	//Parametres
+	//Function
+	event_dispatcher.in_flight_mutex.Lock();
+	event_dispatcher.in_flight++;
+	event_dispatcher.in_flight_mutex.Unlock();
+	if( event.partition_key == "" && dispatch_snapshot.partition_key_function != nil ){
+		event.partition_key = dispatch_snapshot.partition_key_function( event );
+	}
+	task = func(){
+		defer event_dispatcher.finishAsyncEventListener();
+		defer acknowledgement.release();
+		event_dispatcher.callAsyncEventListener( event_listener, event, dispatch_snapshot );
+	};
+	if( worker_pool == nil || worker_pool.submit( task, event.partition_key ) == false ){
+		go task();
+	}
+	//Return
+}
+
+/**
+* @fn finishAsyncEventListener
+* @brief Counts an asynchronous listener call as no longer in flight, waking `Drain` when it was the last.
+* @struct event_dispatcher *EventDispatcher_struct
+*/
+
+// finishAsyncEventListener counts an asynchronous listener call as no longer in flight, waking `Drain` when it was the last.
+func (event_dispatcher *EventDispatcher_struct) finishAsyncEventListener(){
+	//Variables
+	//Parametres
+	//Function
+	event_dispatcher.in_flight_mutex.Lock();
+	event_dispatcher.in_flight--;
+	if( event_dispatcher.in_flight == 0 && event_dispatcher.idle_channel != nil ){
+		close(event_dispatcher.idle_channel);
+		event_dispatcher.idle_channel = nil;
+	}
+	event_dispatcher.in_flight_mutex.Unlock();
+	//Return
+}
+
+//# Global Variables
+var(
+	//## Exported Variables
+	//## Private Variables
+);
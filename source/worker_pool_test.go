@@ -0,0 +1,201 @@
+/**
+* @file worker_pool_test.go
+* @brief Contains test functions for `worker_pool.go`.
+* @author Anadian
+* @copyright 	Copyright 2019 Canosw
+	Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+software and associated documentation files (the "Software"), to deal in the Software 
+without restriction, including without limitation the rights to use, copy, modify, 
+merge, publish, distribute, sublicense, and/or sell copies of the Software, and to 
+permit persons to whom the Software is furnished to do so, subject to the following 
+conditions:
+	The above copyright notice and this permission notice shall be included in all copies 
+or substantial portions of the Software.
+	THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A 
+PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT 
+HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF 
+CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE 
+OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+*/
+
+// worker_pool_test contains test functions for `worker_pool.go`.
+package event_dispatcher;
+
+//# Dependencies
+import(
+	//## Internal
+	//## Standard
+	"testing"
+	"log"
+	"context"
+	"sync"
+	"time"
+	"strconv"
+	//## External
+	matchkey "github.com/Anadian/matchkey/source"
+	error_report "github.com/Anadian/error_report/source"
+);
+
+//# Exported Functions
+/**
+* @fn TestWorkerPool
+* @brief Tests that the worker pool bounds concurrency and that `Wait` and `Drain` wait for in-flight listeners.
+* @param t *testing.T [in] Go stdlib testing object.
+*/
+
+// TestWorkerPool tests that the worker pool bounds concurrency and that `Wait` and `Drain` wait for in-flight listeners.
+func TestWorkerPool( t *testing.T ){
+	//Variables
+	var event_dispatcher *EventDispatcher_struct;
+	var function_return error_report.ErrorReport_struct;
+	var key matchkey.MatchKey_struct;
+	var event Event_struct;
+	var mutex sync.Mutex;
+	var running int;
+	var max_running int;
+	var finished int;
+	var release_channel chan struct{} = make(chan struct{});
+	var done_channel chan error_report.ErrorReport_struct = make(chan error_report.ErrorReport_struct, 1);
+	var ctx context.Context;
+	var cancel context.CancelFunc;
+	var i int;
+	//Parametres
+	//Function
+	event_dispatcher = newEventDispatcher( false, false );
+	event_dispatcher.SetWorkerPoolSize( 2 );
+	key, _ = matchkey.New( matchkey.MATCHKEY_TYPE_STRING, "worker_pool:test" );
+	event_dispatcher.AddEventListener( NewEventListener( key, true, func( event Event_struct, args ...interface{} ){
+		mutex.Lock();
+		running++;
+		if( running > max_running ){
+			max_running = running;
+		}
+		mutex.Unlock();
+		<-release_channel;
+		mutex.Lock();
+		running--;
+		finished++;
+		mutex.Unlock();
+	} ).Data["event_listener"].(EventListener_struct) );
+	event = NewEvent( "worker_pool:test", map[string]interface{}{} ).Data["event"].(Event_struct);
+	///Two run, two wait in the task channel; dispatching more would block.
+	for i = 0; i < 4; i++ {
+		event_dispatcher.ProcessEvent( event );
+	}
+	ctx, cancel = context.WithTimeout( context.Background(), 20 * time.Millisecond );
+	function_return = event_dispatcher.Drain( ctx );
+	cancel();
+	if( function_return.CodeEqual( ERROR_CODE_DRAIN_INCOMPLETE ) == true && function_return.Data["in_flight"] == uint64(4) ){
+		log.Printf("Success: Drain gave up with the context: %v\n", function_return.Data["in_flight"]);
+	} else{
+		t.Fail();
+		log.Printf("Failure: Drain returned: %v\n", function_return);
+	}
+	close(release_channel);
+	function_return = event_dispatcher.Wait();
+	mutex.Lock();
+	if( function_return.NoError() == true && finished == 4 && max_running == 2 ){
+		log.Printf("Success: Wait returned after every listener finished, with at most %d running at once.\n", max_running);
+	} else{
+		t.Fail();
+		log.Printf("Failure: Wait returned %v with %d finished and at most %d running at once.\n", function_return, finished, max_running);
+	}
+	mutex.Unlock();
+	///Without a pool, Wait still covers goroutine-per-call listeners.
+	event_dispatcher.SetWorkerPoolSize( 0 );
+	for i = 0; i < 4; i++ {
+		event_dispatcher.ProcessEvent( event );
+	}
+	event_dispatcher.Wait();
+	mutex.Lock();
+	if( finished == 8 ){
+		log.Printf("Success: Wait covered listeners run without a pool.\n");
+	} else{
+		t.Fail();
+		log.Printf("Failure: Only %d listeners had finished.\n", finished);
+	}
+	mutex.Unlock();
+	///The in-flight count has its own lock, so submitting doesn't deadlock a caller of ProcessEvent_Unsafe holding `mutex`.
+	event_dispatcher.SetWorkerPoolSize( 2 );
+	event_dispatcher.mutex.Lock();
+	go func(){
+		done_channel <- event_dispatcher.ProcessEvent_Unsafe( event );
+	}();
+	select{
+		case <-done_channel:
+			log.Printf("Success: ProcessEvent_Unsafe submitted the asynchronous listener with the lock held.\n");
+		case <-time.After( 5 * time.Second ):
+			t.Fail();
+			log.Printf("Failure: ProcessEvent_Unsafe deadlocked submitting the asynchronous listener.\n");
+	}
+	event_dispatcher.mutex.Unlock();
+	event_dispatcher.Wait();
+	mutex.Lock();
+	if( finished == 9 ){
+		log.Printf("Success: Wait covered the listener submitted by ProcessEvent_Unsafe.\n");
+	} else{
+		t.Fail();
+		log.Printf("Failure: Only %d listeners had finished.\n", finished);
+	}
+	mutex.Unlock();
+	//Return
+}
+
+/**
+* @fn TestPartitionedDispatch
+* @brief Tests that asynchronous listeners get events sharing a partition key in order.
+* @param t *testing.T [in] Go stdlib testing object.
+*/
+
+// TestPartitionedDispatch tests that asynchronous listeners get events sharing a partition key in order.
+func TestPartitionedDispatch( t *testing.T ){
+	//Variables
+	var event_dispatcher *EventDispatcher_struct;
+	var key matchkey.MatchKey_struct;
+	var mutex sync.Mutex;
+	var sequences map[string][]int = map[string][]int{};
+	var wrong_keys int;
+	var order_id string;
+	var i int;
+	var j int;
+	var ordered bool = true;
+	//Parametres
+	//Function
+	event_dispatcher = newEventDispatcher( false, false );
+	event_dispatcher.SetWorkerPoolSize( 4 );
+	event_dispatcher.SetPartitionKeyFunction( func( event Event_struct ) string{
+		return event.data["order_id"].(string);
+	} );
+	key, _ = matchkey.New( matchkey.MATCHKEY_TYPE_STRING, "order:updated" );
+	event_dispatcher.AddEventListener( NewEventListener( key, true, func( event Event_struct, args ...interface{} ){
+		///Later events of an order sleep less, so they'd overtake earlier ones if run in parallel.
+		time.Sleep( time.Duration( 20 - event.data["sequence"].(int) ) * 100 * time.Microsecond );
+		mutex.Lock();
+		sequences[event.data["order_id"].(string)] = append(sequences[event.data["order_id"].(string)], event.data["sequence"].(int));
+		if( event.PartitionKey() != event.data["order_id"].(string) ){
+			wrong_keys++;
+		}
+		mutex.Unlock();
+	} ).Data["event_listener"].(EventListener_struct) );
+	for i = 0; i < 20; i++ {
+		for j = 0; j < 3; j++ {
+			event_dispatcher.ProcessEvent( NewEvent( "order:updated", map[string]interface{}{ "order_id": "order-" + strconv.Itoa( j ), "sequence": i } ).Data["event"].(Event_struct) );
+		}
+	}
+	event_dispatcher.Wait();
+	for order_id = range sequences {
+		for i = 0; i < len(sequences[order_id]); i++ {
+			if( sequences[order_id][i] != i ){
+				ordered = false;
+			}
+		}
+	}
+	if( ordered == true && len(sequences) == 3 && len(sequences["order-0"]) == 20 && wrong_keys == 0 ){
+		log.Printf("Success: Each order's events were handled in order.\n");
+	} else{
+		t.Fail();
+		log.Printf("Failure: Events were handled as %v with %d wrong partition keys.\n", sequences, wrong_keys);
+	}
+	//Return
+}